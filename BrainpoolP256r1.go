@@ -0,0 +1,43 @@
+package ecc
+
+import (
+	"math/big"
+)
+
+type BrainpoolP256r1 struct {
+	*ECParams
+}
+
+// GetBrainpoolP256r1Parameters returns the parameters of brainpoolP256r1 from
+// RFC 5639 section 3.4.
+func GetBrainpoolP256r1Parameters() *BrainpoolP256r1 {
+	p, _ := new(big.Int).SetString("A9FB57DBA1EEA9BC3E660A909D838D726E3BF623D52620282013481D1F6E5377", 16)
+	n, _ := new(big.Int).SetString("A9FB57DBA1EEA9BC3E660A909D838D718C397AA3B561A6F7901E0E82974856A7", 16)
+	b, _ := new(big.Int).SetString("26DC5C6CE94A4B44F330B5D9BBD77CBF958416295CF7E1CE6BCCDC18FF8C07B6", 16)
+	a, _ := new(big.Int).SetString("7D5A0975FC2C3057EEF67530417AFFE7FB8055C126DC5C6CE94A4B44F330B5D9", 16)
+	gx, _ := new(big.Int).SetString("8BD2AEB9CB7E57CB2C4B482FFC81B7AFB9DE27E1E3BD23C23A4453BD9ACE3262", 16)
+	gy, _ := new(big.Int).SetString("547EF835C3DAC4FD97F8461A14611DC9C27745132DED8E545C1D54C72F046997", 16)
+
+	var curveParams = BrainpoolP256r1{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}},
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *BrainpoolP256r1) Name() string {
+	return "brainpoolP256r1"
+}
+
+// ToTwisted maps a point of brainpoolP256r1 to the isomorphic point of
+// brainpoolP256t1. Points that are not on brainpoolP256r1 are rejected.
+func (E *BrainpoolP256r1) ToTwisted(P *Point) (*Point, error) {
+	return brainpoolP256Isomorphism().toTwisted(P)
+}
+
+// FromTwisted maps a point of brainpoolP256t1 back to brainpoolP256r1.
+// Points that are not on brainpoolP256t1 are rejected.
+func (E *BrainpoolP256r1) FromTwisted(P *Point) (*Point, error) {
+	return brainpoolP256Isomorphism().fromTwisted(P)
+}
@@ -2,8 +2,6 @@ package ecc
 
 import (
 	"crypto/sha256"
-	"errors"
-	"fmt"
 	"math/big"
 )
 
@@ -47,55 +45,9 @@ func GetBrainpoolP256t1Parameters() *BrainpoolP256t1 {
 	return &curveParams
 }
 
-func (E *BrainpoolP256t1) IsValidPrivateKey(privateKey *ECPrivateKey) bool {
-	key := privateKey.D.Bytes()
-
-	// Check if the key is 32 bytes long
-	if len(key) != 32 {
-		fmt.Errorf("Key length must be 32 bytes. Given key length %d\n", len(key))
-		return false
-	}
-
-	// Check if the key is all zeros (an edge case to avoid)
-	isAllZero := true
-	for _, b := range key {
-		if b != 0 {
-			isAllZero = false
-			break
-		}
-	}
-	if isAllZero {
-		return false
-	}
-
-	return true
-}
-
-// GeneratePrivateKey generates at new 32 byte key
-func (E *BrainpoolP256t1) GeneratePrivateKey() (*ECPrivateKey, error) {
-
-	// Iterate till you find a valid private key
-	const MAX_ITER = 100
-	iter := 0
-	for {
-		iter += 1
-
-		// generate random 32 bytes
-		randomBytes, err := GenerateRandomBytes(32)
-
-		// Initialize private key
-		privateKey := ECPrivateKey{D: new(big.Int).SetBytes(randomBytes), curve: E.ECParams, PublicKey: &Point{}}
-
-		// Return if valid private key, else generate another one
-		if E.IsValidPrivateKey(&privateKey) {
-			return &privateKey, err
-		}
-
-		// Quit if you cannot generate valid private key after MAX_ITER
-		if iter == MAX_ITER {
-			return &ECPrivateKey{}, errors.New("MAX ITERATION REACHED. Cannot generate private key")
-		}
-	}
+// Name returns the standard name of the curve
+func (E *BrainpoolP256t1) Name() string {
+	return "brainpoolP256t1"
 }
 
 func Messagehash256(message []byte) []byte {
@@ -1,6 +1,7 @@
 package ecc
 
 import (
+	"crypto/rand"
 	"fmt"
 	"testing"
 )
@@ -10,11 +11,11 @@ func TestBrainpoolP256t1_ECDH(t *testing.T) {
 	// perform ECDH
 	params := GetBrainpoolP256t1Parameters()
 
-	priv1, _ := params.GeneratePrivateKey()
+	priv1, _ := params.GeneratePrivateKey(rand.Reader)
 	publicKey1 := priv1.GeneratePublicKey()
 	fmt.Printf("Private key 1 : %x\n", priv1.D.Bytes())
 
-	priv2, _ := params.GeneratePrivateKey()
+	priv2, _ := params.GeneratePrivateKey(rand.Reader)
 	publicKey2 := priv2.GeneratePublicKey()
 	fmt.Printf("Private key 2 : %x\n", priv2.D.Bytes())
 
@@ -38,7 +39,7 @@ func TestBrainpoolP256t1SignAndVerifyValid(t *testing.T) {
 
 	// Generate random private key
 	params := GetBrainpoolP256t1Parameters()
-	privateKey, _ := params.GeneratePrivateKey()
+	privateKey, _ := params.GeneratePrivateKey(rand.Reader)
 	fmt.Printf("Private Key :%d\n", privateKey.D)
 	publicKey := privateKey.GeneratePublicKey()
 	fmt.Printf("Public Key :%d\n", publicKey)
@@ -62,7 +63,7 @@ func TestBrainpoolP256t1SignAndVerifyInValid(t *testing.T) {
 
 	// Generate random private key
 	params := GetBrainpoolP256t1Parameters()
-	privateKey, _ := params.GeneratePrivateKey()
+	privateKey, _ := params.GeneratePrivateKey(rand.Reader)
 	fmt.Printf("Private Key :%d\n", privateKey.D)
 	publicKey := privateKey.GeneratePublicKey()
 	fmt.Printf("Public Key :%d\n", publicKey)
@@ -0,0 +1,45 @@
+package ecc
+
+import (
+	"crypto"
+	_ "crypto/sha512" // registers SHA-384 for crypto.SHA384
+	"math/big"
+)
+
+type BrainpoolP384r1 struct {
+	*ECParams
+}
+
+// GetBrainpoolP384r1Parameters returns the parameters of brainpoolP384r1 from
+// RFC 5639 section 3.6. Signatures on the curve hash messages with SHA-384.
+func GetBrainpoolP384r1Parameters() *BrainpoolP384r1 {
+	p, _ := new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B412B1DA197FB71123ACD3A729901D1A71874700133107EC53", 16)
+	n, _ := new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B31F166E6CAC0425A7CF3AB6AF6B7FC3103B883202E9046565", 16)
+	b, _ := new(big.Int).SetString("04A8C7DD22CE28268B39B55416F0447C2FB77DE107DCD2A62E880EA53EEB62D57CB4390295DBC9943AB78696FA504C11", 16)
+	a, _ := new(big.Int).SetString("7BC382C63D8C150C3C72080ACE05AFA0C2BEA28E4FB22787139165EFBA91F90F8AA5814A503AD4EB04A8C7DD22CE2826", 16)
+	gx, _ := new(big.Int).SetString("1D1C64F068CF45FFA2A63A81B7C13F6B8847A3E77EF14FE3DB7FCAFE0CBD10E8E826E03436D646AAEF87B2E247D4AF1E", 16)
+	gy, _ := new(big.Int).SetString("8ABE1D7520F9C2A45CB1EB8E95CFD55262B70B29FEEC5864E19C054FF99129280E4646217791811142820341263C5315", 16)
+
+	var curveParams = BrainpoolP384r1{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}, Hash: crypto.SHA384},
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *BrainpoolP384r1) Name() string {
+	return "brainpoolP384r1"
+}
+
+// ToTwisted maps a point of brainpoolP384r1 to the isomorphic point of
+// brainpoolP384t1. Points that are not on brainpoolP384r1 are rejected.
+func (E *BrainpoolP384r1) ToTwisted(P *Point) (*Point, error) {
+	return brainpoolP384Isomorphism().toTwisted(P)
+}
+
+// FromTwisted maps a point of brainpoolP384t1 back to brainpoolP384r1.
+// Points that are not on brainpoolP384t1 are rejected.
+func (E *BrainpoolP384r1) FromTwisted(P *Point) (*Point, error) {
+	return brainpoolP384Isomorphism().fromTwisted(P)
+}
@@ -0,0 +1,34 @@
+package ecc
+
+import (
+	"crypto"
+	_ "crypto/sha512" // registers SHA-384 for crypto.SHA384
+	"math/big"
+)
+
+type BrainpoolP384t1 struct {
+	*ECParams
+}
+
+// GetBrainpoolP384t1Parameters returns the parameters of brainpoolP384t1, the
+// twisted variant (a = -3) of brainpoolP384r1 from RFC 5639 section 3.6.
+// Signatures on the curve hash messages with SHA-384.
+func GetBrainpoolP384t1Parameters() *BrainpoolP384t1 {
+	p, _ := new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B412B1DA197FB71123ACD3A729901D1A71874700133107EC53", 16)
+	n, _ := new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B31F166E6CAC0425A7CF3AB6AF6B7FC3103B883202E9046565", 16)
+	b, _ := new(big.Int).SetString("7F519EADA7BDA81BD826DBA647910F8C4B9346ED8CCDC64E4B1ABD11756DCE1D2074AA263B88805CED70355A33B471EE", 16)
+	a, _ := new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B412B1DA197FB71123ACD3A729901D1A71874700133107EC50", 16)
+	gx, _ := new(big.Int).SetString("18DE98B02DB9A306F2AFCD7235F72A819B80AB12EBD653172476FECD462AABFFC4FF191B946A5F54D8D0AA2F418808CC", 16)
+	gy, _ := new(big.Int).SetString("25AB056962D30651A114AFD2755AD336747F93475B7A1FCA3B88F2B6A208CCFE469408584DC2B2912675BF5B9E582928", 16)
+
+	var curveParams = BrainpoolP384t1{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}, Hash: crypto.SHA384},
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *BrainpoolP384t1) Name() string {
+	return "brainpoolP384t1"
+}
@@ -0,0 +1,45 @@
+package ecc
+
+import (
+	"crypto"
+	_ "crypto/sha512" // registers SHA-512 for crypto.SHA512
+	"math/big"
+)
+
+type BrainpoolP512r1 struct {
+	*ECParams
+}
+
+// GetBrainpoolP512r1Parameters returns the parameters of brainpoolP512r1 from
+// RFC 5639 section 3.7. Signatures on the curve hash messages with SHA-512.
+func GetBrainpoolP512r1Parameters() *BrainpoolP512r1 {
+	p, _ := new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA703308717D4D9B009BC66842AECDA12AE6A380E62881FF2F2D82C68528AA6056583A48F3", 16)
+	n, _ := new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA70330870553E5C414CA92619418661197FAC10471DB1D381085DDADDB58796829CA90069", 16)
+	b, _ := new(big.Int).SetString("3DF91610A83441CAEA9863BC2DED5D5AA8253AA10A2EF1C98B9AC8B57F1117A72BF2C7B9E7C1AC4D77FC94CADC083E67984050B75EBAE5DD2809BD638016F723", 16)
+	a, _ := new(big.Int).SetString("7830A3318B603B89E2327145AC234CC594CBDD8D3DF91610A83441CAEA9863BC2DED5D5AA8253AA10A2EF1C98B9AC8B57F1117A72BF2C7B9E7C1AC4D77FC94CA", 16)
+	gx, _ := new(big.Int).SetString("81AEE4BDD82ED9645A21322E9C4C6A9385ED9F70B5D916C1B43B62EEF4D0098EFF3B1F78E2D0D48D50D1687B93B97D5F7C6D5047406A5E688B352209BCB9F822", 16)
+	gy, _ := new(big.Int).SetString("7DDE385D566332ECC0EABFA9CF7822FDF209F70024A57B1AA000C55B881F8111B2DCDE494A5F485E5BCA4BD88A2763AED1CA2B2FA8F0540678CD1E0F3AD80892", 16)
+
+	var curveParams = BrainpoolP512r1{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}, Hash: crypto.SHA512},
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *BrainpoolP512r1) Name() string {
+	return "brainpoolP512r1"
+}
+
+// ToTwisted maps a point of brainpoolP512r1 to the isomorphic point of
+// brainpoolP512t1. Points that are not on brainpoolP512r1 are rejected.
+func (E *BrainpoolP512r1) ToTwisted(P *Point) (*Point, error) {
+	return brainpoolP512Isomorphism().toTwisted(P)
+}
+
+// FromTwisted maps a point of brainpoolP512t1 back to brainpoolP512r1.
+// Points that are not on brainpoolP512t1 are rejected.
+func (E *BrainpoolP512r1) FromTwisted(P *Point) (*Point, error) {
+	return brainpoolP512Isomorphism().fromTwisted(P)
+}
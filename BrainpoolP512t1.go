@@ -0,0 +1,34 @@
+package ecc
+
+import (
+	"crypto"
+	_ "crypto/sha512" // registers SHA-512 for crypto.SHA512
+	"math/big"
+)
+
+type BrainpoolP512t1 struct {
+	*ECParams
+}
+
+// GetBrainpoolP512t1Parameters returns the parameters of brainpoolP512t1, the
+// twisted variant (a = -3) of brainpoolP512r1 from RFC 5639 section 3.7.
+// Signatures on the curve hash messages with SHA-512.
+func GetBrainpoolP512t1Parameters() *BrainpoolP512t1 {
+	p, _ := new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA703308717D4D9B009BC66842AECDA12AE6A380E62881FF2F2D82C68528AA6056583A48F3", 16)
+	n, _ := new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA70330870553E5C414CA92619418661197FAC10471DB1D381085DDADDB58796829CA90069", 16)
+	b, _ := new(big.Int).SetString("7CBBBCF9441CFAB76E1890E46884EAE321F70C0BCB4981527897504BEC3E36A62BCDFA2304976540F6450085F2DAE145C22553B465763689180EA2571867423E", 16)
+	a, _ := new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA703308717D4D9B009BC66842AECDA12AE6A380E62881FF2F2D82C68528AA6056583A48F0", 16)
+	gx, _ := new(big.Int).SetString("640ECE5C12788717B9C1BA06CBC2A6FEBA85842458C56DDE9DB1758D39C0313D82BA51735CDB3EA499AA77A7D6943A64F7A3F25FE26F06B51BAA2696FA9035DA", 16)
+	gy, _ := new(big.Int).SetString("5B534BD595F5AF0FA2C892376C84ACE1BB4E3019B71634C01131159CAE03CEE9D9932184BEEF216BD71DF2DADF86A627306ECFF96DBB8BACE198B61E00F8B332", 16)
+
+	var curveParams = BrainpoolP512t1{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}, Hash: crypto.SHA512},
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *BrainpoolP512t1) Name() string {
+	return "brainpoolP512t1"
+}
@@ -0,0 +1,210 @@
+package ecc
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// BatchVerificationError is returned by the batch verifiers when at least one
+// signature of the batch is invalid
+type BatchVerificationError struct {
+	Failed []int // indices of the invalid entries, in increasing order
+}
+
+func (err *BatchVerificationError) Error() string {
+	return fmt.Sprintf("ecc: %d signatures of the batch are invalid", len(err.Failed))
+}
+
+// ECDSABatchEntry is an ECDSA signature to verify as part of a batch. The
+// recovery identifier is the one returned by SignRecoverable.
+type ECDSABatchEntry struct {
+	PublicKey  *Point
+	Message    []byte
+	Signature  *ECSignature
+	RecoveryID byte
+}
+
+// SchnorrBatchEntry is a BIP-340 signature to verify as part of a batch
+type SchnorrBatchEntry struct {
+	PublicKey []byte // 32-byte x-only public key
+	Message   []byte
+	Signature []byte
+}
+
+// batchTerm is the equation sum(scalars[i] * points[i]) = O of one entry
+type batchTerm struct {
+	scalars []*big.Int
+	points  []*Point
+}
+
+// VerifyECDSABatch verifies ECDSA signatures on one curve together. The point
+// R = k * G of every signature is recovered from r and the recovery identifier,
+// which turns each signature into the equation u1 * G + u2 * Q - R = O. The
+// equations are combined with random coefficients read from rand, or
+// crypto/rand when rand is nil, and checked with a single multi-scalar
+// multiplication. When the batch fails, the entries are checked one by one and
+// a *BatchVerificationError lists the invalid ones.
+//
+// An entry is only valid with its correct recovery identifier, so a signature
+// that passes Verify may still fail in a batch.
+func VerifyECDSABatch(rand io.Reader, params *ECParams, entries []ECDSABatchEntry) error {
+	terms := make([]*batchTerm, len(entries))
+	for i, entry := range entries {
+		terms[i] = ecdsaBatchTerm(params, entry)
+	}
+	return verifyBatch(rand, params, terms)
+}
+
+// VerifySchnorrBatch verifies BIP-340 signatures together, as described in the
+// batch verification section of BIP-340. Random coefficients are read from
+// rand, or crypto/rand when rand is nil. When the batch fails, the entries are
+// checked one by one and a *BatchVerificationError lists the invalid ones.
+func VerifySchnorrBatch(rand io.Reader, entries []SchnorrBatchEntry) error {
+	terms := make([]*batchTerm, len(entries))
+	for i, entry := range entries {
+		terms[i] = schnorrBatchTerm(entry)
+	}
+	return verifyBatch(rand, bip340Curve, terms)
+}
+
+// ecdsaBatchTerm returns the equation u1 * G + u2 * Q + (n - 1) * R = O of a
+// signature, or nil when the signature is malformed
+func ecdsaBatchTerm(params *ECParams, entry ECDSABatchEntry) *batchTerm {
+	signature := entry.Signature
+	if signature == nil || signature.r == nil || signature.s == nil || entry.RecoveryID > 3 {
+		return nil
+	}
+	if signature.curve != nil && !sameCurve(signature.curve, params) {
+		return nil
+	}
+	if signature.r.Sign() <= 0 || signature.r.Cmp(params.N) >= 0 || signature.s.Sign() <= 0 || signature.s.Cmp(params.N) >= 0 {
+		return nil
+	}
+	if !params.IsOnCurve(entry.PublicKey) {
+		return nil
+	}
+	R := recoverR(params, signature.r, entry.RecoveryID)
+	if R == nil {
+		return nil
+	}
+
+	messageHash := bits2int(params.hashMessage(entry.Message), params.N)
+	sInv := new(big.Int).ModInverse(signature.s, params.N)
+	u1 := new(big.Int).Mul(messageHash, sInv)
+	u2 := new(big.Int).Mul(signature.r, sInv)
+	return &batchTerm{
+		scalars: []*big.Int{u1.Mod(u1, params.N), u2.Mod(u2, params.N), new(big.Int).Sub(params.N, big.NewInt(1))},
+		points:  []*Point{params.BasePoint, entry.PublicKey, R},
+	}
+}
+
+// recoverR returns the point R with x-coordinate r + n (when bit 1 of the
+// recovery identifier is set) or r and the y parity given by bit 0
+func recoverR(params *ECParams, r *big.Int, recoveryID byte) *Point {
+	x := new(big.Int).Set(r)
+	if recoveryID&2 != 0 {
+		x.Add(x, params.N)
+	}
+	if x.Cmp(params.P) >= 0 {
+		return nil
+	}
+
+	// y^2 = x^3 + ax + b
+	c := new(big.Int).Mul(x, x)
+	c.Add(c, params.A).Mul(c, x).Add(c, params.B).Mod(c, params.P)
+	y := new(big.Int).ModSqrt(c, params.P)
+	if y == nil {
+		return nil
+	}
+	if y.Bit(0) != uint(recoveryID&1) {
+		y.Sub(params.P, y).Mod(y, params.P)
+	}
+	return &Point{X: x, Y: y}
+}
+
+// schnorrBatchTerm returns the equation s * G + (n - e) * P + (n - 1) * R = O
+// of a BIP-340 signature, or nil when the signature is malformed
+func schnorrBatchTerm(entry SchnorrBatchEntry) *batchTerm {
+	ec := bip340Curve
+	if len(entry.Signature) != 64 {
+		return nil
+	}
+	P, err := ParseSchnorrPublicKey(entry.PublicKey)
+	if err != nil {
+		return nil
+	}
+	R := liftX(new(big.Int).SetBytes(entry.Signature[:32]))
+	if R == nil {
+		return nil
+	}
+	s := new(big.Int).SetBytes(entry.Signature[32:])
+	if s.Cmp(ec.N) >= 0 {
+		return nil
+	}
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", entry.Signature[:32], entry.PublicKey, entry.Message))
+	e.Mod(e, ec.N)
+	return &batchTerm{
+		scalars: []*big.Int{s, e.Sub(ec.N, e), new(big.Int).Sub(ec.N, big.NewInt(1))},
+		points:  []*Point{ec.BasePoint, P, R},
+	}
+}
+
+// verifyBatch checks the sum of all terms, the first one unweighted and the
+// others multiplied by random coefficients, and falls back to checking every
+// term on its own when the sum is not the point at infinity
+func verifyBatch(rand io.Reader, ec *ECParams, terms []*batchTerm) error {
+	var failed []int
+	for i, term := range terms {
+		if term == nil {
+			failed = append(failed, i)
+		}
+	}
+
+	// Terms on the base point are merged into a single scalar
+	base := new(big.Int)
+	var scalars []*big.Int
+	var points []*Point
+	first := true
+	for _, term := range terms {
+		if term == nil {
+			continue
+		}
+		a := big.NewInt(1)
+		if !first {
+			var err error
+			if a, err = ec.randomScalar(rand); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		base.Add(base, new(big.Int).Mul(a, term.scalars[0]))
+		for j := 1; j < len(term.scalars); j++ {
+			k := new(big.Int).Mul(a, term.scalars[j])
+			scalars = append(scalars, k.Mod(k, ec.N))
+			points = append(points, term.points[j])
+		}
+	}
+	scalars = append(scalars, base.Mod(base, ec.N))
+	points = append(points, ec.BasePoint)
+
+	if sum := multiScalarMult(scalars, points, ec); sum.X.Sign() == 0 && sum.Y.Sign() == 0 {
+		if len(failed) == 0 {
+			return nil
+		}
+		return &BatchVerificationError{Failed: failed}
+	}
+
+	failed = failed[:0]
+	for i, term := range terms {
+		if term == nil {
+			failed = append(failed, i)
+			continue
+		}
+		if sum := multiScalarMult(term.scalars, term.points, ec); sum.X.Sign() != 0 || sum.Y.Sign() != 0 {
+			failed = append(failed, i)
+		}
+	}
+	return &BatchVerificationError{Failed: failed}
+}
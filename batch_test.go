@@ -0,0 +1,116 @@
+package ecc
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestBatch_MultiScalarMult(t *testing.T) {
+	params := GetSecp256r1Parameters().ECParams
+	var scalars []*big.Int
+	var points []*Point
+	expected := &Point{X: big.NewInt(0), Y: big.NewInt(0)}
+	for i := 0; i < 5; i++ {
+		k, _ := params.randomScalar(rand.Reader)
+		P := ScalarMult(big.NewInt(int64(i+2)), params.BasePoint, params)
+		scalars = append(scalars, k)
+		points = append(points, P)
+		expected = addPoints(expected, ScalarMult(k, P, params), params)
+	}
+	observed := multiScalarMult(scalars, points, params)
+	if observed.X.Cmp(expected.X) != 0 || observed.Y.Cmp(expected.Y) != 0 {
+		t.Fatalf("Expected %x, Observed %x", expected.X, observed.X)
+	}
+
+	// k * P + (n - k) * P is the point at infinity
+	k := big.NewInt(12345)
+	sum := multiScalarMult([]*big.Int{k, new(big.Int).Sub(params.N, k)}, []*Point{params.BasePoint, params.BasePoint}, params)
+	if sum.X.Sign() != 0 || sum.Y.Sign() != 0 {
+		t.Fatalf("Expected the point at infinity, Observed %x", sum.X)
+	}
+}
+
+func TestBatch_ECDSA(t *testing.T) {
+	for _, params := range []*ECParams{GetSecp256k1Parametes().ECParams, GetSecp256r1Parameters().ECParams} {
+		var entries []ECDSABatchEntry
+		for i := 0; i < 8; i++ {
+			privateKey, _ := params.GeneratePrivateKey(rand.Reader)
+			message := []byte(fmt.Sprintf("message %d", i))
+			signature, recoveryID := privateKey.SignRecoverable(message)
+			entries = append(entries, ECDSABatchEntry{PublicKey: privateKey.PublicKey, Message: message, Signature: signature, RecoveryID: recoveryID})
+		}
+		if err := VerifyECDSABatch(rand.Reader, params, entries); err != nil {
+			t.Fatalf("Valid batch failed : %v", err)
+		}
+
+		// A wrong message, a wrong recovery identifier and a signature of another key
+		entries[1].Message = []byte("tampered")
+		entries[4].RecoveryID ^= 1
+		entries[6].Signature = entries[7].Signature
+		err := VerifyECDSABatch(nil, params, entries)
+		var batchErr *BatchVerificationError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("Expected a BatchVerificationError, Observed %v", err)
+		}
+		if !reflect.DeepEqual(batchErr.Failed, []int{1, 4, 6}) {
+			t.Fatalf("Expected failures [1 4 6], Observed %v", batchErr.Failed)
+		}
+	}
+}
+
+func TestBatch_Schnorr(t *testing.T) {
+	var entries []SchnorrBatchEntry
+	for i := 0; i < 8; i++ {
+		privateKey, _ := GenerateSchnorrPrivateKey(rand.Reader)
+		message := []byte(fmt.Sprintf("message %d", i))
+		signature, err := privateKey.SignSchnorr(message, nil)
+		if err != nil {
+			t.Fatalf("Failed to sign : %v", err)
+		}
+		entries = append(entries, SchnorrBatchEntry{PublicKey: privateKey.PublicKey.XOnly(), Message: message, Signature: signature})
+	}
+	if err := VerifySchnorrBatch(rand.Reader, entries); err != nil {
+		t.Fatalf("Valid batch failed : %v", err)
+	}
+	if err := VerifySchnorrBatch(nil, nil); err != nil {
+		t.Fatalf("Empty batch failed : %v", err)
+	}
+
+	// A wrong message and a malformed signature
+	entries[2].Message = []byte("tampered")
+	entries[5].Signature = entries[5].Signature[:63]
+	err := VerifySchnorrBatch(nil, entries)
+	var batchErr *BatchVerificationError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected a BatchVerificationError, Observed %v", err)
+	}
+	if !reflect.DeepEqual(batchErr.Failed, []int{2, 5}) {
+		t.Fatalf("Expected failures [2 5], Observed %v", batchErr.Failed)
+	}
+
+	// Only the malformed signature
+	entries[2].Message = []byte("message 2")
+	if err := VerifySchnorrBatch(nil, entries); !errors.As(err, &batchErr) || !reflect.DeepEqual(batchErr.Failed, []int{5}) {
+		t.Fatalf("Expected failure [5], Observed %v", err)
+	}
+}
+
+func TestBatch_RecoveryID(t *testing.T) {
+	params := GetSecp256k1Parametes().ECParams
+	privateKey, _ := params.GeneratePrivateKey(rand.Reader)
+	signature, recoveryID := privateKey.SignRecoverable([]byte("Hello 123"))
+	if !privateKey.PublicKey.Verify([]byte("Hello 123"), signature, params) {
+		t.Fatalf("Recoverable signature does not verify")
+	}
+	R := recoverR(params, signature.r, recoveryID)
+	if R == nil || !params.IsOnCurve(R) {
+		t.Fatalf("Failed to recover R")
+	}
+	if recoverR(params, signature.r, recoveryID^1).Y.Cmp(R.Y) == 0 {
+		t.Fatalf("Recovery identifiers with different parity give the same R")
+	}
+}
@@ -0,0 +1,170 @@
+package ecc
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// bip340Curve is secp256k1, the only curve BIP-340 is defined for
+var bip340Curve = GetSecp256k1Parametes().ECParams
+
+// taggedHash computes the BIP-340 tagged hash
+// SHA256(SHA256(tag) || SHA256(tag) || parts)
+func taggedHash(tag string, parts ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+// GenerateSchnorrPrivateKey creates a secp256k1 private key whose public key
+// has an even y-coordinate, so that the x-only public key of BIP-340 refers to
+// the public key itself. Randomness is read from rand, or crypto/rand when rand
+// is nil.
+func GenerateSchnorrPrivateKey(rand io.Reader) (*ECPrivateKey, error) {
+	key, err := bip340Curve.GeneratePrivateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	if key.PublicKey.Y.Bit(0) == 1 {
+		key.D.Sub(bip340Curve.N, key.D)
+		key.PublicKey.Y.Sub(bip340Curve.P, key.PublicKey.Y)
+	}
+	return key, nil
+}
+
+// XOnly returns the 32-byte x-only encoding of a secp256k1 public key
+func (publicKey *Point) XOnly() []byte {
+	return publicKey.X.FillBytes(make([]byte, 32))
+}
+
+// ParseSchnorrPublicKey decodes a 32-byte x-only public key into the point
+// with that x-coordinate and an even y-coordinate (lift_x of BIP-340)
+func ParseSchnorrPublicKey(data []byte) (*Point, error) {
+	if len(data) != 32 {
+		return nil, errors.New("ecc: x-only public key must be 32 bytes")
+	}
+	P := liftX(new(big.Int).SetBytes(data))
+	if P == nil {
+		return nil, errors.New("ecc: x-only public key is not on secp256k1")
+	}
+	return P, nil
+}
+
+// liftX returns the point with x-coordinate x and an even y-coordinate, or nil
+// when there is none
+func liftX(x *big.Int) *Point {
+	p := bip340Curve.P
+	if x.Cmp(p) >= 0 {
+		return nil
+	}
+
+	// y^2 = x^3 + 7
+	c := new(big.Int).Mul(x, x)
+	c.Mul(c, x).Add(c, bip340Curve.B).Mod(c, p)
+	y := new(big.Int).ModSqrt(c, p)
+	if y == nil {
+		return nil
+	}
+	if y.Bit(0) == 1 {
+		y.Sub(p, y)
+	}
+	return &Point{X: new(big.Int).Set(x), Y: y}
+}
+
+// SignSchnorr creates a 64-byte BIP-340 signature of a message with a
+// secp256k1 private key. auxRand is the 32 bytes of auxiliary randomness mixed
+// into the nonce; when nil, it is read from crypto/rand. The private key may
+// belong to a public key with an odd y-coordinate, in which case it is negated
+// as required by BIP-340.
+func (key *ECPrivateKey) SignSchnorr(message, auxRand []byte) ([]byte, error) {
+	ec := bip340Curve
+	if key.curve != nil && !sameCurve(key.curve, ec) {
+		return nil, errors.New("ecc: BIP-340 signatures require a secp256k1 key")
+	}
+	if key.D == nil || key.D.Sign() <= 0 || key.D.Cmp(ec.N) >= 0 {
+		return nil, errors.New("ecc: invalid secp256k1 private key")
+	}
+	if auxRand == nil {
+		auxRand = make([]byte, 32)
+		if _, err := io.ReadFull(cryptorand.Reader, auxRand); err != nil {
+			return nil, err
+		}
+	}
+	if len(auxRand) != 32 {
+		return nil, errors.New("ecc: BIP-340 auxiliary randomness must be 32 bytes")
+	}
+
+	// d = d' when P = d'*G has an even y-coordinate and n - d' otherwise
+	P := ScalarMult(key.D, ec.BasePoint, ec)
+	d := new(big.Int).Set(key.D)
+	if P.Y.Bit(0) == 1 {
+		d.Sub(ec.N, d)
+	}
+	publicKey := P.XOnly()
+
+	// t = bytes(d) xor hash_BIP0340/aux(a)
+	t := d.FillBytes(make([]byte, 32))
+	subtle.XORBytes(t, t, taggedHash("BIP0340/aux", auxRand))
+
+	// k' = int(hash_BIP0340/nonce(t || bytes(P) || m)) mod n
+	k := new(big.Int).SetBytes(taggedHash("BIP0340/nonce", t, publicKey, message))
+	k.Mod(k, ec.N)
+	if k.Sign() == 0 {
+		return nil, errors.New("ecc: BIP-340 nonce is zero")
+	}
+	R := ScalarMult(k, ec.BasePoint, ec)
+	if R.Y.Bit(0) == 1 {
+		k.Sub(ec.N, k)
+	}
+	r := R.XOnly()
+
+	// s = (k + e*d) mod n with e = int(hash_BIP0340/challenge(bytes(R) || bytes(P) || m)) mod n
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", r, publicKey, message))
+	s := e.Mul(e.Mod(e, ec.N), d)
+	s.Add(s, k).Mod(s, ec.N)
+
+	signature := append(r, s.FillBytes(make([]byte, 32))...)
+	if !VerifySchnorr(publicKey, message, signature) {
+		return nil, errors.New("ecc: BIP-340 signature failed to verify")
+	}
+	return signature, nil
+}
+
+// VerifySchnorr checks a 64-byte BIP-340 signature of a message against a
+// 32-byte x-only public key
+func VerifySchnorr(publicKey, message, signature []byte) bool {
+	ec := bip340Curve
+	if len(signature) != 64 {
+		return false
+	}
+	P, err := ParseSchnorrPublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	if r.Cmp(ec.P) >= 0 {
+		return false
+	}
+	s := new(big.Int).SetBytes(signature[32:])
+	if s.Cmp(ec.N) >= 0 {
+		return false
+	}
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", signature[:32], publicKey, message))
+	e.Mod(e, ec.N)
+
+	// R = s*G - e*P must not be infinity, have an even y-coordinate and x(R) = r
+	R := addPoints(ScalarMult(s, ec.BasePoint, ec), ScalarMult(e.Sub(ec.N, e), P, ec), ec)
+	if R.X.Sign() == 0 && R.Y.Sign() == 0 {
+		return false
+	}
+	return R.Y.Bit(0) == 0 && R.X.Cmp(r) == 0
+}
@@ -0,0 +1,93 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+)
+
+func TestSchnorr_BIP340Vectors(t *testing.T) {
+	f, err := os.Open("testdata/bip340_vectors.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test vectors : %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse test vectors : %v", err)
+	}
+
+	for _, record := range records[1:] {
+		index, comment := record[0], record[7]
+		publicKey, _ := hex.DecodeString(record[2])
+		auxRand, _ := hex.DecodeString(record[3])
+		message, _ := hex.DecodeString(record[4])
+		signature, _ := hex.DecodeString(record[5])
+		expected := record[6] == "TRUE"
+
+		if record[1] != "" {
+			d, _ := new(big.Int).SetString(record[1], 16)
+			privateKey := CreatePrivateKeyFromScalar(GetSecp256k1Parametes().ECParams, d)
+			if observed := privateKey.GeneratePublicKey().XOnly(); !bytes.Equal(observed, publicKey) {
+				t.Fatalf("Vector %s: Expected public key %x, Observed %x", index, publicKey, observed)
+			}
+			observed, err := privateKey.SignSchnorr(message, auxRand)
+			if err != nil {
+				t.Fatalf("Vector %s: failed to sign : %v", index, err)
+			}
+			if !bytes.Equal(observed, signature) {
+				t.Fatalf("Vector %s: Expected signature %x, Observed %x", index, signature, observed)
+			}
+		}
+
+		if observed := VerifySchnorr(publicKey, message, signature); observed != expected {
+			t.Fatalf("Vector %s (%s): Expected %v, Observed %v", index, comment, expected, observed)
+		}
+	}
+}
+
+func TestSchnorr_EvenYKeys(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		privateKey, err := GenerateSchnorrPrivateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate private key : %v", err)
+		}
+		if privateKey.PublicKey.Y.Bit(0) != 0 {
+			t.Fatalf("Generated public key has an odd y-coordinate")
+		}
+		P, err := ParseSchnorrPublicKey(privateKey.PublicKey.XOnly())
+		if err != nil || P.X.Cmp(privateKey.PublicKey.X) != 0 || P.Y.Cmp(privateKey.PublicKey.Y) != 0 {
+			t.Fatalf("x-only public key does not lift to the public key : %v", err)
+		}
+		if expected := ScalarMult(privateKey.D, bip340Curve.BasePoint, bip340Curve); expected.Y.Cmp(P.Y) != 0 {
+			t.Fatalf("Private key does not match the even public key")
+		}
+
+		signature, err := privateKey.SignSchnorr([]byte("Hello 123"), nil)
+		if err != nil {
+			t.Fatalf("Failed to sign : %v", err)
+		}
+		if !VerifySchnorr(privateKey.PublicKey.XOnly(), []byte("Hello 123"), signature) {
+			t.Fatalf("Signature does not verify")
+		}
+		if VerifySchnorr(privateKey.PublicKey.XOnly(), []byte("Hello 124"), signature) {
+			t.Fatalf("Signature verified for a different message")
+		}
+	}
+}
+
+func TestSchnorr_RejectsOtherCurves(t *testing.T) {
+	k, _ := new(big.Int).SetString("71f25609dcec384ebc6655ef856242cb36e2f80c1092ceb21d32e3caad9c9d16", 16)
+	privateKey := CreatePrivateKeyFromScalar(GetSecp256r1Parameters().ECParams, k)
+	if _, err := privateKey.SignSchnorr([]byte("Hello 123"), make([]byte, 32)); err == nil {
+		t.Fatalf("Expected error when signing with a P-256 key")
+	}
+	privateKey = CreatePrivateKeyFromScalar(GetSecp256k1Parametes().ECParams, k)
+	if _, err := privateKey.SignSchnorr([]byte("Hello 123"), make([]byte, 31)); err == nil {
+		t.Fatalf("Expected error for short auxiliary randomness")
+	}
+}
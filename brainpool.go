@@ -0,0 +1,95 @@
+package ecc
+
+import (
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// Object identifiers of the Brainpool curves (RFC 5639 appendix A)
+var (
+	OIDBrainpoolP256r1 = asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 7}
+	OIDBrainpoolP256t1 = asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 8}
+	OIDBrainpoolP384r1 = asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 11}
+	OIDBrainpoolP384t1 = asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 12}
+	OIDBrainpoolP512r1 = asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 13}
+	OIDBrainpoolP512t1 = asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 14}
+)
+
+// brainpoolIsomorphism is the map F(x, y) = (x*z^2, y*z^3) of RFC 5639
+// section 3 from a random curve y^2 = x^3 + ax + b to its twisted variant with
+// a = -3, where z^4 = -3/a (mod p)
+type brainpoolIsomorphism struct {
+	random, twisted         *ECParams
+	randomName, twistedName string
+	p, z2, z3, z2Inv, z3Inv *big.Int
+}
+
+// The isomorphisms are computed on first use and shared by all callers
+var (
+	brainpoolP256Isomorphism = sync.OnceValue(func() *brainpoolIsomorphism {
+		return newBrainpoolIsomorphism("brainpoolP256r1", GetBrainpoolP256r1Parameters().ECParams, "brainpoolP256t1", GetBrainpoolP256t1Parameters().ECParams)
+	})
+	brainpoolP384Isomorphism = sync.OnceValue(func() *brainpoolIsomorphism {
+		return newBrainpoolIsomorphism("brainpoolP384r1", GetBrainpoolP384r1Parameters().ECParams, "brainpoolP384t1", GetBrainpoolP384t1Parameters().ECParams)
+	})
+	brainpoolP512Isomorphism = sync.OnceValue(func() *brainpoolIsomorphism {
+		return newBrainpoolIsomorphism("brainpoolP512r1", GetBrainpoolP512r1Parameters().ECParams, "brainpoolP512t1", GetBrainpoolP512t1Parameters().ECParams)
+	})
+)
+
+// newBrainpoolIsomorphism computes z for the pair of curves. Among the roots
+// of z^4 = -3/a the one that maps the base point of the random curve onto the
+// base point of the twisted curve is chosen.
+func newBrainpoolIsomorphism(randomName string, random *ECParams, twistedName string, twisted *ECParams) *brainpoolIsomorphism {
+	p := random.P
+
+	// z^4 = -3 * a^-1
+	z4 := new(big.Int).ModInverse(random.A, p)
+	z4.Mul(z4, big.NewInt(-3))
+	z4.Mod(z4, p)
+
+	// p = 3 (mod 4) for all Brainpool primes, so exactly one of the square
+	// roots of z^4 is itself a square
+	z2 := new(big.Int).ModSqrt(z4, p)
+	if big.Jacobi(z2, p) != 1 {
+		z2.Sub(p, z2)
+	}
+	z := new(big.Int).ModSqrt(z2, p)
+
+	z3 := new(big.Int).Mul(z2, z)
+	z3.Mod(z3, p)
+	if y := new(big.Int).Mul(random.BasePoint.Y, z3); y.Mod(y, p).Cmp(twisted.BasePoint.Y) != 0 {
+		z3.Sub(p, z3)
+	}
+	return &brainpoolIsomorphism{
+		random: random, twisted: twisted, randomName: randomName, twistedName: twistedName,
+		p: p, z2: z2, z3: z3,
+		z2Inv: new(big.Int).ModInverse(z2, p), z3Inv: new(big.Int).ModInverse(z3, p),
+	}
+}
+
+// toTwisted computes F(P) for a point P of the random curve
+func (iso *brainpoolIsomorphism) toTwisted(P *Point) (*Point, error) {
+	if !iso.random.IsOnCurve(P) {
+		return nil, errors.New("ecc: point is not on curve " + iso.randomName)
+	}
+	return iso.scale(P, iso.z2, iso.z3), nil
+}
+
+// fromTwisted computes the inverse map (x*z^-2, y*z^-3) for a point P of the
+// twisted curve
+func (iso *brainpoolIsomorphism) fromTwisted(P *Point) (*Point, error) {
+	if !iso.twisted.IsOnCurve(P) {
+		return nil, errors.New("ecc: point is not on curve " + iso.twistedName)
+	}
+	return iso.scale(P, iso.z2Inv, iso.z3Inv), nil
+}
+
+// scale returns (x*u, y*v)
+func (iso *brainpoolIsomorphism) scale(P *Point, u, v *big.Int) *Point {
+	x := new(big.Int).Mul(P.X, u)
+	y := new(big.Int).Mul(P.Y, v)
+	return &Point{X: x.Mod(x, iso.p), Y: y.Mod(y, iso.p)}
+}
@@ -0,0 +1,146 @@
+package ecc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func brainpoolCurves() map[string]*ECParams {
+	return map[string]*ECParams{
+		"brainpoolP256r1": GetBrainpoolP256r1Parameters().ECParams,
+		"brainpoolP256t1": GetBrainpoolP256t1Parameters().ECParams,
+		"brainpoolP384r1": GetBrainpoolP384r1Parameters().ECParams,
+		"brainpoolP384t1": GetBrainpoolP384t1Parameters().ECParams,
+		"brainpoolP512r1": GetBrainpoolP512r1Parameters().ECParams,
+		"brainpoolP512t1": GetBrainpoolP512t1Parameters().ECParams,
+	}
+}
+
+func TestBrainpool_Parameters(t *testing.T) {
+	for name, params := range brainpoolCurves() {
+		if !params.P.ProbablyPrime(20) || !params.N.ProbablyPrime(20) {
+			t.Fatalf("%s: p and n must be prime", name)
+		}
+		if !params.IsOnCurve(params.BasePoint) {
+			t.Fatalf("%s: base point is not on the curve", name)
+		}
+		O := ScalarMult(params.N, params.BasePoint, params)
+		if O.X.Sign() != 0 || O.Y.Sign() != 0 {
+			t.Fatalf("%s: n * G is not the point at infinity", name)
+		}
+	}
+}
+
+func TestBrainpool_PublicKeys(t *testing.T) {
+
+	// Key pairs generated with OpenSSL
+	vectors := []struct {
+		name    string
+		params  *ECParams
+		d, x, y string
+	}{
+		{"brainpoolP256r1", GetBrainpoolP256r1Parameters().ECParams,
+			"256fa89c398962d36acdde109cfebce0ef199797ec89d6ff3bd1228e9945c80b",
+			"1ae0537759b9a6e3479f38c19b1a2bc9a818e311aa1ac26fb877c5fb7f67b7b3",
+			"6ebecabd249508bc0d15e49fc2ea9792e6bea17763df87d30ef6ace42608b0c1"},
+		{"brainpoolP384r1", GetBrainpoolP384r1Parameters().ECParams,
+			"8ac7b5e090afe7bdb0ac31039f8c2bf48c39e7eff1ee28dd7402127746c79dca34633c64214bcc35e1b6bbae24ab7c0d",
+			"2eddb6859012fa6654765a788b44fcf26b5a4458cafef8777a68baac7fc34b91a3c8c2b52dd31ea1b0d566abf3f85c9e",
+			"5955ac16e222e6b1b4c0d219ce5b475b4e7b5ffe6fed6acecf21563ac4c988058dc1487510e458095439920d07c0bab2"},
+		{"brainpoolP384t1", GetBrainpoolP384t1Parameters().ECParams,
+			"223a3eb1034e91f6cb229f09f69fc126a8a9b2343d38c479eddc6361c484a3cd216ed75bb11c4b40a88d8595971d79b6",
+			"02b5d300fca3001cc441382525b51214abf875817e1ad10e7bbdd2e5face5181f2507f9dc70d1936fa0dee2b063a8c0c",
+			"30c9e43900c97c5c29fe58abf8af3c94fba472fda385e2ea8a29674215ce8bb8e74a32023f767a8d1c65f2f42645559b"},
+		{"brainpoolP512r1", GetBrainpoolP512r1Parameters().ECParams,
+			"680fa4e428b67a64f7d23c2b6b23dfa7aae76c94d63f920f71da9235f130a17657460878c6716bde14db101104fe4c8cf3b07bbde4b645a5dad7e3b8886c82b1",
+			"78d92d1ee6485cb0d587697c78efcda3a6de812dfd36a3c4224fbb058d1f607b201394f2198eb74a09ab708750906a2ecc9d3cd6d7b59df52fa471e519860efa",
+			"a57f8d2d59c579a55da61af5729b3872de469d2a64c078f1650dbf5c25cec3fec3171e2185eb950d8f82a055efbd9efb95313e0bf222ee8f49c0dc6107b14a65"},
+		{"brainpoolP512t1", GetBrainpoolP512t1Parameters().ECParams,
+			"026c6750ab14d84363e4229e49bc9eaa24b4a0430a90b218e4d19143f491c2cd9caba194586ea4458b946b2337689a37755e4800e276de2eadfb6e1e86c26542",
+			"851ca0f92e140a23c4c315998f41207c9e8f5f1246e567c75e9cb6e1f1857bb0e1b87eaad0b9d78981ab6221f7cb6ff50f29fe50762774b82fc6c6ae2b16b7eb",
+			"440e762135c91b4fdf31d82020578faf69d700db5c986e481d2df5ddf522cb59a493829ac2c259670b09e64e92771bf0bae020abdda3d0b1cc7dc8e1c8040d0c"},
+	}
+	for _, vector := range vectors {
+		d, _ := new(big.Int).SetString(vector.d, 16)
+		expectedX, _ := new(big.Int).SetString(vector.x, 16)
+		expectedY, _ := new(big.Int).SetString(vector.y, 16)
+		publicKey := CreatePrivateKeyFromScalar(vector.params, d).GeneratePublicKey()
+		if publicKey.X.Cmp(expectedX) != 0 || publicKey.Y.Cmp(expectedY) != 0 {
+			t.Fatalf("%s: public key mismatch. Expected (%x, %x), Observed (%x, %x)", vector.name, expectedX, expectedY, publicKey.X, publicKey.Y)
+		}
+	}
+}
+
+func TestBrainpool_SignAndVerify(t *testing.T) {
+	for name, params := range brainpoolCurves() {
+		d, _ := new(big.Int).SetString("6a3bbd8c1d63c2e9b9e6bc28d44a07a11b4e0a3c5ffd7c9b0e2e9fb1e0c3d1a2", 16)
+		priv := CreatePrivateKeyFromScalar(params, d)
+		publicKey := priv.GeneratePublicKey()
+		signature := priv.Sign([]byte("Hello 123"))
+		if !publicKey.Verify([]byte("Hello 123"), signature, params) {
+			t.Fatalf("%s: signature did not verify", name)
+		}
+		if publicKey.Verify([]byte("Hello 124"), signature, params) {
+			t.Fatalf("%s: signature verified for a different message", name)
+		}
+	}
+}
+
+func TestBrainpool_TwistedIsomorphism(t *testing.T) {
+	k, _ := new(big.Int).SetString("2d5a166ee81fff6c3bf30bf6a67f84cd8b56a2e7932f426d5976786d26373271", 16)
+
+	type pair struct {
+		random, twisted *ECParams
+		toTwisted       func(*Point) (*Point, error)
+		fromTwisted     func(*Point) (*Point, error)
+	}
+	r256, r384, r512 := GetBrainpoolP256r1Parameters(), GetBrainpoolP384r1Parameters(), GetBrainpoolP512r1Parameters()
+	pairs := map[string]pair{
+		"P256": {r256.ECParams, GetBrainpoolP256t1Parameters().ECParams, r256.ToTwisted, r256.FromTwisted},
+		"P384": {r384.ECParams, GetBrainpoolP384t1Parameters().ECParams, r384.ToTwisted, r384.FromTwisted},
+		"P512": {r512.ECParams, GetBrainpoolP512t1Parameters().ECParams, r512.ToTwisted, r512.FromTwisted},
+	}
+	for name, curves := range pairs {
+		// The base points correspond to each other
+		G, err := curves.toTwisted(curves.random.BasePoint)
+		if err != nil || G.X.Cmp(curves.twisted.BasePoint.X) != 0 || G.Y.Cmp(curves.twisted.BasePoint.Y) != 0 {
+			t.Fatalf("%s: base point of r is not mapped onto base point of t", name)
+		}
+
+		// The map is a group homomorphism, so k * G maps to k * G'
+		P := ScalarMult(k, curves.random.BasePoint, curves.random)
+		Q, err := curves.toTwisted(P)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		expected := ScalarMult(k, curves.twisted.BasePoint, curves.twisted)
+		if !curves.twisted.IsOnCurve(Q) || Q.X.Cmp(expected.X) != 0 || Q.Y.Cmp(expected.Y) != 0 {
+			t.Fatalf("%s: k * G is not mapped onto k * G'. Expected %x, Observed %x", name, expected.X, Q.X)
+		}
+
+		back, err := curves.fromTwisted(Q)
+		if err != nil || back.X.Cmp(P.X) != 0 || back.Y.Cmp(P.Y) != 0 {
+			t.Fatalf("%s: inverse map does not restore the point", name)
+		}
+
+		// Points of the other curve, off both curves and nil are rejected
+		if _, err := curves.toTwisted(Q); err == nil {
+			t.Fatalf("%s: Expected error for a point of t mapped to t", name)
+		}
+		if _, err := curves.fromTwisted(P); err == nil {
+			t.Fatalf("%s: Expected error for a point of r mapped to r", name)
+		}
+		if _, err := curves.toTwisted(&Point{X: big.NewInt(1), Y: big.NewInt(1)}); err == nil {
+			t.Fatalf("%s: Expected error for a point off the curve", name)
+		}
+		if _, err := curves.fromTwisted(nil); err == nil {
+			t.Fatalf("%s: Expected error for a nil point", name)
+		}
+	}
+}
+
+func TestBrainpool_OIDs(t *testing.T) {
+	if OIDBrainpoolP256r1.String() != "1.3.36.3.3.2.8.1.1.7" || OIDBrainpoolP512t1.String() != "1.3.36.3.3.2.8.1.1.14" {
+		t.Fatalf("Unexpected object identifiers %s, %s", OIDBrainpoolP256r1, OIDBrainpoolP512t1)
+	}
+}
@@ -0,0 +1,321 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// This file implements the subset of CBOR (RFC 8949) needed by COSE. Values
+// are represented as
+//
+//	int64                        major types 0 and 1
+//	[]byte                       major type 2
+//	string                       major type 3
+//	[]interface{}                major type 4
+//	map[interface{}]interface{}  major type 5, keys are int64 or string
+//	cborTag                      major type 6
+//	bool, nil                    simple values false, true and null
+//
+// Encoding follows the core deterministic encoding requirements of RFC 8949
+// section 4.2.1: shortest length arguments, definite lengths and map keys
+// sorted by their encoded bytes. The decoder rejects anything else.
+
+const (
+	cborUnsigned = 0
+	cborNegative = 1
+	cborBytes    = 2
+	cborText     = 3
+	cborArray    = 4
+	cborMap      = 5
+	cborTagged   = 6
+	cborSimple   = 7
+
+	cborMaxDepth = 16
+)
+
+// cborTag is a tagged data item
+type cborTag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// cborMarshal returns the deterministic encoding of v
+func cborMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cborEncode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func cborEncode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(cborSimple<<5 | 22)
+	case bool:
+		if v {
+			buf.WriteByte(cborSimple<<5 | 21)
+		} else {
+			buf.WriteByte(cborSimple<<5 | 20)
+		}
+	case int:
+		cborEncodeInt(buf, int64(v))
+	case int64:
+		cborEncodeInt(buf, v)
+	case uint64:
+		cborEncodeHead(buf, cborUnsigned, v)
+	case []byte:
+		cborEncodeHead(buf, cborBytes, uint64(len(v)))
+		buf.Write(v)
+	case string:
+		cborEncodeHead(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case []interface{}:
+		cborEncodeHead(buf, cborArray, uint64(len(v)))
+		for _, item := range v {
+			if err := cborEncode(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		type entry struct{ key, value []byte }
+		entries := make([]entry, 0, len(v))
+		for key, value := range v {
+			encodedKey, err := cborMarshal(key)
+			if err != nil {
+				return err
+			}
+			encodedValue, err := cborMarshal(value)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{encodedKey, encodedValue})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+		cborEncodeHead(buf, cborMap, uint64(len(entries)))
+		for _, e := range entries {
+			buf.Write(e.key)
+			buf.Write(e.value)
+		}
+	case cborTag:
+		cborEncodeHead(buf, cborTagged, v.Number)
+		return cborEncode(buf, v.Content)
+	default:
+		return fmt.Errorf("cbor: unsupported type %T", v)
+	}
+	return nil
+}
+
+func cborEncodeInt(buf *bytes.Buffer, v int64) {
+	if v >= 0 {
+		cborEncodeHead(buf, cborUnsigned, uint64(v))
+	} else {
+		cborEncodeHead(buf, cborNegative, uint64(-(v + 1)))
+	}
+}
+
+// cborEncodeHead writes the initial byte and the shortest form of the argument
+func cborEncodeHead(buf *bytes.Buffer, major byte, arg uint64) {
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= math.MaxUint8:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(arg)))
+	case arg <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(arg)))
+	default:
+		buf.WriteByte(major<<5 | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, arg))
+	}
+}
+
+// cborUnmarshal decodes a single data item which must span all of data
+func cborUnmarshal(data []byte) (interface{}, error) {
+	d := cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, errors.New("cbor: trailing data after item")
+	}
+	return v, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+var errCBORTruncated = errors.New("cbor: unexpected end of data")
+
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("cbor: nesting too deep")
+	}
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflows int64")
+		}
+		return int64(arg), nil
+	case cborNegative:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflows int64")
+		}
+		return -1 - int64(arg), nil
+	case cborBytes, cborText:
+		content, err := d.read(arg)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborText {
+			return string(content), nil
+		}
+		return append([]byte{}, content...), nil
+	case cborArray:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case cborMap:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		m := make(map[interface{}]interface{}, arg)
+		var previousKey []byte
+		for i := uint64(0); i < arg; i++ {
+			start := d.pos
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+			}
+			encodedKey := d.data[start:d.pos]
+			if previousKey != nil && bytes.Compare(previousKey, encodedKey) >= 0 {
+				return nil, errors.New("cbor: map keys not in deterministic order")
+			}
+			previousKey = encodedKey
+
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case cborTagged:
+		content, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return cborTag{Number: arg, Content: content}, nil
+	default:
+		switch arg {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cbor: unsupported simple value %d", arg)
+	}
+}
+
+// head reads the initial byte and argument of a data item, rejecting
+// indefinite lengths and arguments that are not in their shortest form
+func (d *cborDecoder) head() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, errCBORTruncated
+	}
+	initial := d.data[d.pos]
+	d.pos++
+	major, info := initial>>5, initial&0x1f
+
+	var arg uint64
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		b, err := d.read(1)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = uint64(b[0])
+		if arg < 24 {
+			return 0, 0, errors.New("cbor: non-minimal integer encoding")
+		}
+	case info == 25:
+		b, err := d.read(2)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = uint64(binary.BigEndian.Uint16(b))
+		if arg <= math.MaxUint8 {
+			return 0, 0, errors.New("cbor: non-minimal integer encoding")
+		}
+	case info == 26:
+		b, err := d.read(4)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = uint64(binary.BigEndian.Uint32(b))
+		if arg <= math.MaxUint16 {
+			return 0, 0, errors.New("cbor: non-minimal integer encoding")
+		}
+	case info == 27:
+		b, err := d.read(8)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = binary.BigEndian.Uint64(b)
+		if arg <= math.MaxUint32 {
+			return 0, 0, errors.New("cbor: non-minimal integer encoding")
+		}
+	default:
+		return 0, 0, errors.New("cbor: indefinite lengths are not supported")
+	}
+	if major == cborSimple {
+		// Floating point numbers and extended simple values
+		return 0, 0, errors.New("cbor: unsupported simple value or float")
+	}
+	return major, arg, nil
+}
+
+func (d *cborDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errCBORTruncated
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
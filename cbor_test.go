@@ -0,0 +1,91 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCBOR_RFC8949Examples(t *testing.T) {
+	// Examples from RFC 8949 appendix A
+	testCases := []struct {
+		value   interface{}
+		encoded string
+	}{
+		{int64(0), "00"},
+		{int64(23), "17"},
+		{int64(24), "1818"},
+		{int64(100), "1864"},
+		{int64(1000), "1903e8"},
+		{int64(1000000), "1a000f4240"},
+		{int64(1000000000000), "1b000000e8d4a51000"},
+		{int64(-1), "20"},
+		{int64(-100), "3863"},
+		{int64(-1000), "3903e7"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{[]byte{}, "40"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{"", "60"},
+		{"a", "6161"},
+		{"IETF", "6449455446"},
+		{"ü", "62c3bc"},
+		{[]interface{}{}, "80"},
+		{[]interface{}{int64(1), int64(2), int64(3)}, "83010203"},
+		{[]interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}}, "8301820203820405"},
+		{map[interface{}]interface{}{}, "a0"},
+		{map[interface{}]interface{}{int64(1): int64(2), int64(3): int64(4)}, "a201020304"},
+		{map[interface{}]interface{}{"a": "A", "b": "B", "c": "C", "d": "D", "e": "E"}, "a56161614161626142616361436164614461656145"},
+		{cborTag{Number: 1, Content: int64(1363896240)}, "c11a514b67b0"},
+	}
+	for _, tc := range testCases {
+		encoded, err := cborMarshal(tc.value)
+		if err != nil {
+			t.Fatalf("Failed to encode %v : %v", tc.value, err)
+		}
+		if hex.EncodeToString(encoded) != tc.encoded {
+			t.Fatalf("Unexpected encoding of %v. Expected %s, Observed %x", tc.value, tc.encoded, encoded)
+		}
+
+		decoded, err := cborUnmarshal(encoded)
+		if err != nil {
+			t.Fatalf("Failed to decode %s : %v", tc.encoded, err)
+		}
+		reencoded, _ := cborMarshal(decoded)
+		if !bytes.Equal(reencoded, encoded) {
+			t.Fatalf("Decoding %s is not round trip safe. Observed %x", tc.encoded, reencoded)
+		}
+	}
+}
+
+func TestCBOR_DeterministicMapOrder(t *testing.T) {
+	// Integer keys sort before text keys, shorter encodings before longer ones
+	value := map[interface{}]interface{}{"z": int64(1), int64(-1): int64(2), int64(10): int64(3), int64(100): int64(4)}
+	encoded, _ := cborMarshal(value)
+	expected := "a40a031864042002617a01"
+	if hex.EncodeToString(encoded) != expected {
+		t.Fatalf("Unexpected encoding. Expected %s, Observed %x", expected, encoded)
+	}
+}
+
+func TestCBOR_RejectsNonDeterministicInput(t *testing.T) {
+	invalid := []string{
+		"1817",         // non-minimal integer
+		"190017",       // non-minimal integer
+		"5f4101ff",     // indefinite length byte string
+		"9fff",         // indefinite length array
+		"a202010102",   // unsorted map keys
+		"a201010102",   // duplicate map keys
+		"f97e00",       // half precision float
+		"0000",         // trailing data
+		"5801",         // truncated byte string
+		"9b0000000100", // array length exceeding input
+	}
+	for _, data := range invalid {
+		raw, _ := hex.DecodeString(data)
+		if _, err := cborUnmarshal(raw); err == nil {
+			t.Fatalf("Expected error when decoding %s", data)
+		}
+	}
+}
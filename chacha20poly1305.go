@@ -0,0 +1,239 @@
+package ecc
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+const (
+	chacha20KeySize   = 32
+	chacha20NonceSize = 12
+	poly1305TagSize   = 16
+)
+
+// chacha20Poly1305 is the ChaCha20-Poly1305 AEAD of RFC 8439
+type chacha20Poly1305 struct {
+	key [chacha20KeySize]byte
+}
+
+// newChaCha20Poly1305 returns ChaCha20-Poly1305 with a 32-byte key as a
+// cipher.AEAD
+func newChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if len(key) != chacha20KeySize {
+		return nil, errors.New("ecc: ChaCha20-Poly1305 key must be 32 bytes")
+	}
+	aead := new(chacha20Poly1305)
+	copy(aead.key[:], key)
+	return aead, nil
+}
+
+func (aead *chacha20Poly1305) NonceSize() int {
+	return chacha20NonceSize
+}
+
+func (aead *chacha20Poly1305) Overhead() int {
+	return poly1305TagSize
+}
+
+// Seal encrypts and authenticates the plaintext and appends the result to dst,
+// as described in RFC 8439 section 2.8
+func (aead *chacha20Poly1305) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != chacha20NonceSize {
+		panic("ecc: invalid ChaCha20-Poly1305 nonce size")
+	}
+	if uint64(len(plaintext)) > (1<<32-1)*64 {
+		panic("ecc: ChaCha20-Poly1305 plaintext is too long")
+	}
+	out := append(dst, make([]byte, len(plaintext)+poly1305TagSize)...)
+	ciphertext := out[len(dst) : len(dst)+len(plaintext)]
+	chacha20XORKeyStream(ciphertext, plaintext, &aead.key, nonce, 1)
+	tag := aead.tag(nonce, ciphertext, additionalData)
+	copy(out[len(dst)+len(plaintext):], tag[:])
+	return out
+}
+
+// Open authenticates and decrypts the ciphertext and appends the plaintext to
+// dst
+func (aead *chacha20Poly1305) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != chacha20NonceSize {
+		panic("ecc: invalid ChaCha20-Poly1305 nonce size")
+	}
+	if len(ciphertext) < poly1305TagSize {
+		return nil, errors.New("ecc: message authentication failed")
+	}
+	tag := ciphertext[len(ciphertext)-poly1305TagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-poly1305TagSize]
+	expected := aead.tag(nonce, ciphertext, additionalData)
+	if subtle.ConstantTimeCompare(expected[:], tag) != 1 {
+		return nil, errors.New("ecc: message authentication failed")
+	}
+	out := append(dst, make([]byte, len(ciphertext))...)
+	chacha20XORKeyStream(out[len(dst):], ciphertext, &aead.key, nonce, 1)
+	return out, nil
+}
+
+// tag computes the Poly1305 tag of aad || pad16 || ciphertext || pad16 ||
+// len(aad) || len(ciphertext) with the one-time key of ChaCha20 block 0
+func (aead *chacha20Poly1305) tag(nonce, ciphertext, additionalData []byte) [poly1305TagSize]byte {
+	var polyKey [32]byte
+	chacha20XORKeyStream(polyKey[:], polyKey[:], &aead.key, nonce, 0)
+
+	var mac poly1305
+	mac.init(&polyKey)
+	mac.writePadded(additionalData)
+	mac.writePadded(ciphertext)
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData)))
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(ciphertext)))
+	mac.blocks(lengths[:])
+	return mac.sum()
+}
+
+// chacha20XORKeyStream XORs src with the ChaCha20 key stream of RFC 8439
+// section 2.4 starting at the given block counter
+func chacha20XORKeyStream(dst, src []byte, key *[chacha20KeySize]byte, nonce []byte, counter uint32) {
+	var state [16]uint32
+	state[0], state[1], state[2], state[3] = 0x61707865, 0x3320646e, 0x79622d32, 0x6b206574
+	for i := 0; i < 8; i++ {
+		state[4+i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	for i := 0; i < 3; i++ {
+		state[13+i] = binary.LittleEndian.Uint32(nonce[4*i:])
+	}
+
+	var block [64]byte
+	for len(src) > 0 {
+		state[12] = counter
+		chacha20Block(&block, &state)
+		n := subtle.XORBytes(dst, src, block[:])
+		dst, src = dst[n:], src[n:]
+		counter++
+	}
+}
+
+// chacha20Block computes one 64-byte block of key stream
+func chacha20Block(out *[64]byte, state *[16]uint32) {
+	x := *state
+	for i := 0; i < 10; i++ {
+		// Column rounds
+		chacha20QuarterRound(&x, 0, 4, 8, 12)
+		chacha20QuarterRound(&x, 1, 5, 9, 13)
+		chacha20QuarterRound(&x, 2, 6, 10, 14)
+		chacha20QuarterRound(&x, 3, 7, 11, 15)
+
+		// Diagonal rounds
+		chacha20QuarterRound(&x, 0, 5, 10, 15)
+		chacha20QuarterRound(&x, 1, 6, 11, 12)
+		chacha20QuarterRound(&x, 2, 7, 8, 13)
+		chacha20QuarterRound(&x, 3, 4, 9, 14)
+	}
+	for i := range x {
+		binary.LittleEndian.PutUint32(out[4*i:], x[i]+state[i])
+	}
+}
+
+func chacha20QuarterRound(x *[16]uint32, a, b, c, d int) {
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+}
+
+// poly1305 computes the Poly1305 one-time authenticator of RFC 8439 section
+// 2.5 with the accumulator h in three 64-bit limbs
+type poly1305 struct {
+	r0, r1     uint64
+	s0, s1     uint64
+	h0, h1, h2 uint64
+}
+
+func (p *poly1305) init(key *[32]byte) {
+	p.r0 = binary.LittleEndian.Uint64(key[0:8]) & 0x0ffffffc0fffffff
+	p.r1 = binary.LittleEndian.Uint64(key[8:16]) & 0x0ffffffc0ffffffc
+	p.s0 = binary.LittleEndian.Uint64(key[16:24])
+	p.s1 = binary.LittleEndian.Uint64(key[24:32])
+}
+
+// writePadded processes the data followed by zeros up to a multiple of 16
+// bytes
+func (p *poly1305) writePadded(data []byte) {
+	full := len(data) &^ 15
+	p.blocks(data[:full])
+	if full < len(data) {
+		var block [16]byte
+		copy(block[:], data[full:])
+		p.blocks(block[:])
+	}
+}
+
+// blocks processes full 16-byte blocks, h = (h + block + 2^128) * r mod 2^130 - 5
+func (p *poly1305) blocks(data []byte) {
+	h0, h1, h2 := p.h0, p.h1, p.h2
+	r0, r1 := p.r0, p.r1
+	for ; len(data) >= 16; data = data[16:] {
+		var c uint64
+		h0, c = bits.Add64(h0, binary.LittleEndian.Uint64(data[0:8]), 0)
+		h1, c = bits.Add64(h1, binary.LittleEndian.Uint64(data[8:16]), c)
+		h2 += c + 1
+
+		// h * r, with h2 < 8 and r0, r1 < 2^60
+		h0r0hi, h0r0lo := bits.Mul64(h0, r0)
+		h1r0hi, h1r0lo := bits.Mul64(h1, r0)
+		h0r1hi, h0r1lo := bits.Mul64(h0, r1)
+		h1r1hi, h1r1lo := bits.Mul64(h1, r1)
+		h2r0 := h2 * r0
+		h2r1 := h2 * r1
+
+		t0 := h0r0lo
+		t1, c := bits.Add64(h0r0hi, h1r0lo, 0)
+		t2, c2 := bits.Add64(h1r0hi, h1r1lo, c)
+		t3 := h1r1hi + c2
+		t1, c = bits.Add64(t1, h0r1lo, 0)
+		t2, c = bits.Add64(t2, h0r1hi, c)
+		t3 += c
+		t2, c = bits.Add64(t2, h2r0, 0)
+		t3 += h2r1 + c
+
+		// Reduce with 2^130 = 5: the part above 2^130 is (t3:t2 &^ 3) / 4,
+		// added once as it is (4x) and once shifted right by two (x)
+		h0, h1, h2 = t0, t1, t2&3
+		cc0, cc1 := t2&^3, t3
+		h0, c = bits.Add64(h0, cc0, 0)
+		h1, c = bits.Add64(h1, cc1, c)
+		h2 += c
+		cc0 = cc0>>2 | cc1<<62
+		cc1 >>= 2
+		h0, c = bits.Add64(h0, cc0, 0)
+		h1, c = bits.Add64(h1, cc1, c)
+		h2 += c
+	}
+	p.h0, p.h1, p.h2 = h0, h1, h2
+}
+
+// sum returns (h mod 2^130 - 5) + s mod 2^128
+func (p *poly1305) sum() [poly1305TagSize]byte {
+	h0, h1, h2 := p.h0, p.h1, p.h2
+
+	// h - p, kept when it does not borrow
+	t0, b := bits.Sub64(h0, 0xfffffffffffffffb, 0)
+	t1, b := bits.Sub64(h1, 0xffffffffffffffff, b)
+	_, b = bits.Sub64(h2, 3, b)
+	mask := b - 1
+	h0 = h0&^mask | t0&mask
+	h1 = h1&^mask | t1&mask
+
+	var c uint64
+	h0, c = bits.Add64(h0, p.s0, 0)
+	h1, _ = bits.Add64(h1, p.s1, c)
+	var tag [poly1305TagSize]byte
+	binary.LittleEndian.PutUint64(tag[0:8], h0)
+	binary.LittleEndian.PutUint64(tag[8:16], h1)
+	return tag
+}
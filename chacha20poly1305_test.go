@@ -0,0 +1,44 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// RFC 8439 section 2.8.2
+func TestChaCha20Poly1305_RFC8439(t *testing.T) {
+	key, _ := hex.DecodeString("808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+	nonce, _ := hex.DecodeString("070000004041424344454647")
+	aad, _ := hex.DecodeString("50515253c0c1c2c3c4c5c6c7")
+	plaintext := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	expected, _ := hex.DecodeString("d31a8d34648e60db7b86afbc53ef7ec2a4aded51296e08fea9e2b5a736ee62d63dbea45e8ca9671282fafb69da92728b1a71de0a9e060b2905d6a5b67ecd3b3692ddbd7f2d778b8c9803aee328091b58fab324e4fad675945585808b4831d7bc3ff4def08e4b7a9de576d26586cec64b6116" +
+		"1ae10b594f09e26a7e902ecbd0600691")
+
+	aead, err := newChaCha20Poly1305(key)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	ciphertext := aead.Seal(nil, nonce, plaintext, aad)
+	if !bytes.Equal(ciphertext, expected) {
+		t.Fatalf("Expected %x, Observed %x", expected, ciphertext)
+	}
+	opened, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("Expected %x, Observed %x", plaintext, opened)
+	}
+
+	for i := range ciphertext {
+		tampered := append([]byte{}, ciphertext...)
+		tampered[i] ^= 0x80
+		if _, err := aead.Open(nil, nonce, tampered, aad); err == nil {
+			t.Fatalf("Expected an error for a ciphertext modified at byte %d", i)
+		}
+	}
+	if _, err := aead.Open(nil, nonce, ciphertext, nil); err == nil {
+		t.Fatalf("Expected an error for different associated data")
+	}
+}
@@ -0,0 +1,254 @@
+package ecc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE labels and values from RFC 9052 and RFC 9053
+const (
+	coseSign1Tag = 18
+
+	coseHeaderAlgorithm = 1
+	coseHeaderCritical  = 2
+
+	coseKeyType    = 1
+	coseKeyTypeEC2 = 2
+	coseKeyCurve   = -1
+	coseKeyX       = -2
+	coseKeyY       = -3
+	coseKeyD       = -4
+)
+
+// SignCOSE1 signs the payload and returns a tagged COSE_Sign1 message
+// (RFC 9052 section 4.2). Secp256r1, Secp384r1 and Secp521r1 keys sign with
+// ES256, ES384 and ES512, and Secp256k1 keys with ES256K, always with the hash
+// function of the algorithm. The external additional authenticated data may be
+// nil.
+func (key *ECPrivateKey) SignCOSE1(payload, externalAAD []byte) ([]byte, error) {
+	curve := lookupCurve(key.curve)
+	if curve == nil || curve.COSEAlgorithm == 0 {
+		return nil, errors.New("cose: no COSE algorithm is defined for the curve of the key")
+	}
+
+	protected, err := cborMarshal(map[interface{}]interface{}{
+		int64(coseHeaderAlgorithm): curve.COSEAlgorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+	toBeSigned, err := coseSigStructure(protected, externalAAD, payload)
+	if err != nil {
+		return nil, err
+	}
+	signer := &ECPrivateKey{D: key.D, curve: curve.Params, PublicKey: key.PublicKey}
+	signature := signer.Sign(toBeSigned)
+
+	return cborMarshal(cborTag{Number: coseSign1Tag, Content: []interface{}{
+		protected,
+		map[interface{}]interface{}{},
+		payload,
+		signature.rawBytes(key.curve.scalarSize()),
+	}})
+}
+
+// VerifyCOSE1 verifies a COSE_Sign1 message, tagged or untagged, with the
+// public key and returns its payload. The algorithm of the protected header
+// must be the one defined for params, and its hash function is used whatever
+// the Hash of params.
+func (publicKey *Point) VerifyCOSE1(message, externalAAD []byte, params *ECParams) ([]byte, error) {
+	decoded, err := cborUnmarshal(message)
+	if err != nil {
+		return nil, err
+	}
+	if tag, ok := decoded.(cborTag); ok {
+		if tag.Number != coseSign1Tag {
+			return nil, fmt.Errorf("cose: unexpected tag %d", tag.Number)
+		}
+		decoded = tag.Content
+	}
+	items, ok := decoded.([]interface{})
+	if !ok || len(items) != 4 {
+		return nil, errors.New("cose: COSE_Sign1 must be an array of four items")
+	}
+	protected, ok1 := items[0].([]byte)
+	_, ok2 := items[1].(map[interface{}]interface{})
+	payload, ok3 := items[2].([]byte)
+	rawSignature, ok4 := items[3].([]byte)
+	if !ok1 || !ok2 || !ok4 {
+		return nil, errors.New("cose: malformed COSE_Sign1")
+	}
+	if !ok3 {
+		return nil, errors.New("cose: detached payloads are not supported")
+	}
+
+	// The algorithm must be integrity protected
+	if len(protected) == 0 {
+		return nil, errors.New("cose: missing protected header")
+	}
+	decodedHeader, err := cborUnmarshal(protected)
+	if err != nil {
+		return nil, err
+	}
+	header, ok := decodedHeader.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("cose: protected header must be a map")
+	}
+	if _, ok := header[int64(coseHeaderCritical)]; ok {
+		return nil, errors.New("cose: critical header parameters are not supported")
+	}
+	algorithm, ok := header[int64(coseHeaderAlgorithm)].(int64)
+	if !ok {
+		return nil, errors.New("cose: missing algorithm in protected header")
+	}
+	curve := lookupCurve(params)
+	if curve == nil || curve.COSEAlgorithm == 0 {
+		return nil, errors.New("cose: no COSE algorithm is defined for the curve")
+	}
+	if algorithm != curve.COSEAlgorithm {
+		return nil, fmt.Errorf("cose: algorithm %d cannot be used with a %s key", algorithm, curve.Name)
+	}
+
+	signature, err := parseRawSignature(rawSignature, params)
+	if err != nil {
+		return nil, fmt.Errorf("cose: %w", err)
+	}
+	toBeSigned, err := coseSigStructure(protected, externalAAD, payload)
+	if err != nil {
+		return nil, err
+	}
+	if !params.IsOnCurve(publicKey) {
+		return nil, errors.New("cose: public key is not on the curve")
+	}
+	if !publicKey.Verify(toBeSigned, signature, curve.Params) {
+		return nil, errors.New("cose: invalid signature")
+	}
+	return payload, nil
+}
+
+// coseSigStructure builds the Sig_structure of RFC 9052 section 4.4 for a
+// COSE_Sign1 message
+func coseSigStructure(protected, externalAAD, payload []byte) ([]byte, error) {
+	if externalAAD == nil {
+		externalAAD = []byte{}
+	}
+	return cborMarshal([]interface{}{"Signature1", protected, externalAAD, payload})
+}
+
+// MarshalCOSEKey encodes the public key as an EC2 COSE_Key (RFC 9053 section 7.1)
+func (publicKey *Point) MarshalCOSEKey() ([]byte, error) {
+	curve := lookupCurveOfPoint(publicKey)
+	if curve == nil || curve.COSECurve == 0 {
+		return nil, errors.New("cose: point is not on a curve with a COSE identifier")
+	}
+	return cborMarshal(newCOSEKey(curve, publicKey))
+}
+
+// UnmarshalCOSEKey decodes an EC2 COSE_Key holding a public key. The private
+// key parameter is ignored.
+func (publicKey *Point) UnmarshalCOSEKey(data []byte) error {
+	_, point, _, err := parseCOSEKey(data)
+	if err != nil {
+		return err
+	}
+	publicKey.X = point.X
+	publicKey.Y = point.Y
+	return nil
+}
+
+// MarshalCOSEKey encodes the private key, including its public part, as an EC2
+// COSE_Key
+func (key *ECPrivateKey) MarshalCOSEKey() ([]byte, error) {
+	curve := lookupCurve(key.curve)
+	if curve == nil || curve.COSECurve == 0 {
+		return nil, errors.New("cose: private key is not on a curve with a COSE identifier")
+	}
+	if key.D == nil || key.D.Sign() <= 0 || key.D.Cmp(key.curve.N) >= 0 {
+		return nil, errors.New("cose: private key is out of range")
+	}
+
+	coseKey := newCOSEKey(curve, ScalarMult(key.D, key.curve.BasePoint, key.curve))
+	coseKey[int64(coseKeyD)] = key.D.FillBytes(make([]byte, key.curve.scalarSize()))
+	return cborMarshal(coseKey)
+}
+
+// UnmarshalCOSEKey decodes an EC2 COSE_Key holding a private key. The public
+// coordinates must match the public key derived from the private key.
+func (key *ECPrivateKey) UnmarshalCOSEKey(data []byte) error {
+	curve, point, d, err := parseCOSEKey(data)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.New("cose: missing private key parameter")
+	}
+	if d.Sign() <= 0 || d.Cmp(curve.Params.N) >= 0 {
+		return errors.New("cose: private key is out of range")
+	}
+	publicKey := ScalarMult(d, curve.Params.BasePoint, curve.Params)
+	if publicKey.X.Cmp(point.X) != 0 || publicKey.Y.Cmp(point.Y) != 0 {
+		return errors.New("cose: public key does not match private key")
+	}
+
+	key.D = d
+	key.curve = curve.Params
+	key.PublicKey = publicKey
+	return nil
+}
+
+func newCOSEKey(curve *CurveInfo, publicKey *Point) map[interface{}]interface{} {
+	size := curve.Params.coordinateSize()
+	return map[interface{}]interface{}{
+		int64(coseKeyType):  int64(coseKeyTypeEC2),
+		int64(coseKeyCurve): curve.COSECurve,
+		int64(coseKeyX):     publicKey.X.FillBytes(make([]byte, size)),
+		int64(coseKeyY):     publicKey.Y.FillBytes(make([]byte, size)),
+	}
+}
+
+// parseCOSEKey decodes an EC2 COSE_Key. The returned private key is nil when
+// the key has no "d" parameter.
+func parseCOSEKey(data []byte) (*CurveInfo, *Point, *big.Int, error) {
+	decoded, err := cborUnmarshal(data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	coseKey, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, nil, errors.New("cose: COSE_Key must be a map")
+	}
+	if kty, _ := coseKey[int64(coseKeyType)].(int64); kty != coseKeyTypeEC2 {
+		return nil, nil, nil, fmt.Errorf("cose: unsupported key type %v", coseKey[int64(coseKeyType)])
+	}
+	crv, _ := coseKey[int64(coseKeyCurve)].(int64)
+	curve, err := LookupCurveByCOSE(crv)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cose: unsupported curve %v", coseKey[int64(coseKeyCurve)])
+	}
+
+	size := curve.Params.coordinateSize()
+	x, ok := coseKey[int64(coseKeyX)].([]byte)
+	if !ok || len(x) != size {
+		return nil, nil, nil, errors.New("cose: invalid x coordinate")
+	}
+	y, ok := coseKey[int64(coseKeyY)].([]byte)
+	if !ok || len(y) != size {
+		// A boolean y is the compressed form, which is not supported
+		return nil, nil, nil, errors.New("cose: invalid or compressed y coordinate")
+	}
+	point := &Point{X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !curve.Params.IsOnCurve(point) {
+		return nil, nil, nil, errors.New("cose: point is not on curve " + curve.Name)
+	}
+
+	var d *big.Int
+	if value, present := coseKey[int64(coseKeyD)]; present {
+		raw, ok := value.([]byte)
+		if !ok || len(raw) != curve.Params.scalarSize() {
+			return nil, nil, nil, errors.New("cose: invalid private key parameter")
+		}
+		d = new(big.Int).SetBytes(raw)
+	}
+	return curve, point, d, nil
+}
@@ -0,0 +1,137 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestCOSE_RFC9052Sign1Example(t *testing.T) {
+	// COSE_Sign1 example from RFC 9052 appendix C.2.1, signed with key "11"
+	x, _ := new(big.Int).SetString("bac5b11cad8f99f9c72b05cf4b9e26d244dc189f745228255a219a86d6a09eff", 16)
+	y, _ := new(big.Int).SetString("20138bf82dc1b6d562be0fa54ab7804a3a64b6d72ccfed6b6fb6ed28bbfc117e", 16)
+	publicKey := &Point{X: x, Y: y}
+
+	message, _ := hex.DecodeString("d28443a10126a10442313154546869732069732074686520636f6e74656e742e5840" +
+		"8eb33e4ca31d1c465ab05aac34cc6b23d58fef5c083106c4d25a91aef0b0117e2af9a291aa32e14ab834dc56ed2a223444547e01f11d3b0916e5a4c345cacb36")
+
+	payload, err := publicKey.VerifyCOSE1(message, nil, GetSecp256r1Parameters().ECParams)
+	if err != nil {
+		t.Fatalf("Failed to verify RFC 9052 example : %v", err)
+	}
+	if string(payload) != "This is the content." {
+		t.Fatalf("Unexpected payload %q", payload)
+	}
+}
+
+func TestCOSE_SignAndVerify(t *testing.T) {
+	k, _ := new(big.Int).SetString("57c92077664146e876760c9520d054aa93c3afb04e306705db6090308507b4d3", 16)
+	for _, params := range []*ECParams{GetSecp256k1Parametes().ECParams, GetSecp256r1Parameters().ECParams, GetSecp384r1Parameters().ECParams, GetSecp521r1Parameters().ECParams} {
+		privateKey := CreatePrivateKeyFromScalar(params, k)
+		publicKey := privateKey.GeneratePublicKey()
+
+		message, err := privateKey.SignCOSE1([]byte("sensor reading"), []byte("device-42"))
+		if err != nil {
+			t.Fatalf("Failed to sign : %v", err)
+		}
+		payload, err := publicKey.VerifyCOSE1(message, []byte("device-42"), params)
+		if err != nil {
+			t.Fatalf("Failed to verify : %v", err)
+		}
+		if string(payload) != "sensor reading" {
+			t.Fatalf("Unexpected payload %q", payload)
+		}
+
+		if _, err := publicKey.VerifyCOSE1(message, []byte("device-43"), params); err == nil {
+			t.Fatalf("Message verified with wrong external AAD")
+		}
+	}
+
+	// A secp256k1 message must not verify as ES256
+	privateKey := CreatePrivateKeyFromScalar(GetSecp256k1Parametes().ECParams, k)
+	publicKey := privateKey.GeneratePublicKey()
+	message, _ := privateKey.SignCOSE1([]byte("payload"), nil)
+	if _, err := publicKey.VerifyCOSE1(message, nil, GetSecp256r1Parameters().ECParams); err == nil {
+		t.Fatalf("Expected error for algorithm / curve mismatch")
+	}
+}
+
+// ES256 always hashes with SHA-256, also for keys whose parameters set
+// another hash function for Sign
+func TestCOSE_HashOfAlgorithm(t *testing.T) {
+	params := *GetSecp256r1Parameters().ECParams
+	params.Hash = crypto.SHA512
+	k, _ := new(big.Int).SetString("57c92077664146e876760c9520d054aa93c3afb04e306705db6090308507b4d3", 16)
+	privateKey := CreatePrivateKeyFromScalar(&params, k)
+	publicKey := privateKey.GeneratePublicKey()
+
+	message, err := privateKey.SignCOSE1([]byte("payload"), nil)
+	if err != nil {
+		t.Fatalf("Failed to sign : %v", err)
+	}
+	for _, verifyParams := range []*ECParams{&params, GetSecp256r1Parameters().ECParams} {
+		if _, err := publicKey.VerifyCOSE1(message, nil, verifyParams); err != nil {
+			t.Fatalf("Failed to verify : %v", err)
+		}
+	}
+
+	// The signature is the one of Sign with SHA-256
+	decoded, _ := cborUnmarshal(message)
+	items := decoded.(cborTag).Content.([]interface{})
+	toBeSigned, _ := coseSigStructure(items[0].([]byte), nil, []byte("payload"))
+	signature := CreatePrivateKeyFromScalar(GetSecp256r1Parameters().ECParams, k).Sign(toBeSigned)
+	if !bytes.Equal(items[3].([]byte), signature.rawBytes(32)) {
+		t.Fatalf("Expected %x, Observed %x", signature.rawBytes(32), items[3])
+	}
+}
+
+func TestCOSE_KeyRoundTrip(t *testing.T) {
+	// Key "meriadoc.brandybuck@buckland.example" from RFC 9052 appendix C.7
+	d, _ := new(big.Int).SetString("aff907c99f9ad3aae6c4cdf21122bce2bd68b5283e6907154ad911840fa208cf", 16)
+	privateKey := CreatePrivateKeyFromScalar(GetSecp256r1Parameters().ECParams, d)
+	publicKey := privateKey.GeneratePublicKey()
+
+	encoded, err := publicKey.MarshalCOSEKey()
+	if err != nil {
+		t.Fatalf("Failed to encode public key : %v", err)
+	}
+	expected := "a401022001215820" + "65eda5a12577c2bae829437fe338701a10aaa375e1bb5b5de108de439c08551d" +
+		"225820" + "1e52ed75701163f7f9e40ddf9f341b3dc9ba860af7e0ca7ca7e9eecd0084d19c"
+	if hex.EncodeToString(encoded) != expected {
+		t.Fatalf("Unexpected COSE_Key. Expected %s, Observed %x", expected, encoded)
+	}
+
+	var decodedPublic Point
+	if err := decodedPublic.UnmarshalCOSEKey(encoded); err != nil {
+		t.Fatalf("Failed to decode public key : %v", err)
+	}
+	if decodedPublic.X.Cmp(publicKey.X) != 0 || decodedPublic.Y.Cmp(publicKey.Y) != 0 {
+		t.Fatalf("Public key changed after round trip")
+	}
+
+	encoded, err = privateKey.MarshalCOSEKey()
+	if err != nil {
+		t.Fatalf("Failed to encode private key : %v", err)
+	}
+	var decodedPrivate ECPrivateKey
+	if err := decodedPrivate.UnmarshalCOSEKey(encoded); err != nil {
+		t.Fatalf("Failed to decode private key : %v", err)
+	}
+	if decodedPrivate.D.Cmp(d) != 0 {
+		t.Fatalf("Private key changed after round trip")
+	}
+
+	// Private key that does not match the public coordinates
+	tampered := bytes.Replace(encoded, d.Bytes(), new(big.Int).Add(d, big.NewInt(1)).Bytes(), 1)
+	if err := decodedPrivate.UnmarshalCOSEKey(tampered); err == nil {
+		t.Fatalf("Expected error for mismatched private key")
+	}
+
+	// Brainpool curves have no COSE identifier
+	bp := CreatePrivateKeyFromScalar(GetBrainpoolP256t1Parameters().ECParams, d)
+	if _, err := bp.GeneratePublicKey().MarshalCOSEKey(); err == nil {
+		t.Fatalf("Expected error for brainpoolP256t1 COSE_Key")
+	}
+}
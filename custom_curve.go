@@ -0,0 +1,155 @@
+package ecc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Weaknesses reported by CheckSecurity
+var (
+	ErrAnomalousCurve       = errors.New("ecc: curve is anomalous (#E = p), discrete logarithms are easy (Smart's attack)")
+	ErrSmallEmbeddingDegree = errors.New("ecc: curve has a small embedding degree, discrete logarithms reduce to a small finite field (MOV attack)")
+	ErrSmallSubgroup        = errors.New("ecc: order of the base point is too small")
+)
+
+const (
+	// minimumOrderBits is the smallest size of n accepted by CheckSecurity,
+	// which gives 112 bits of security (NIST SP 800-57)
+	minimumOrderBits = 224
+
+	// maximumWeakEmbeddingDegree is the largest embedding degree for which the
+	// MOV reduction is considered practical
+	maximumWeakEmbeddingDegree = 20
+)
+
+// CustomCurve is a curve built from user-supplied parameters by NewCurve
+type CustomCurve struct {
+	*ECParams
+}
+
+// NewCurve builds the curve y^2 = x^3 + ax + b over F_p with base point G of
+// order n and cofactor h, and checks that the parameters describe a usable
+// group:
+//
+//   - p is a prime larger than 3 and a, b and the coordinates of G are reduced
+//     modulo p
+//   - the discriminant 4a^3 + 27b^2 is nonzero modulo p
+//   - b is nonzero, since (0, 0) represents the point at infinity
+//   - G lies on the curve
+//   - n is prime and n * G is the point at infinity
+//   - h * n lies within the Hasse bound p + 1 - 2*sqrt(p) <= h * n <= p + 1 + 2*sqrt(p)
+//
+// A nil h means a cofactor of 1. The curve is not checked for known
+// weaknesses, use CheckSecurity for that.
+func NewCurve(p, a, b *big.Int, G *Point, n, h *big.Int) (*CustomCurve, error) {
+	if p == nil || a == nil || b == nil || G == nil || G.X == nil || G.Y == nil || n == nil {
+		return nil, errors.New("ecc: missing curve parameter")
+	}
+	if h == nil {
+		h = big.NewInt(1)
+	}
+
+	params := &ECParams{
+		P:         new(big.Int).Set(p),
+		A:         new(big.Int).Set(a),
+		B:         new(big.Int).Set(b),
+		N:         new(big.Int).Set(n),
+		H:         new(big.Int).Set(h),
+		BasePoint: &Point{X: new(big.Int).Set(G.X), Y: new(big.Int).Set(G.Y)},
+	}
+	if err := validateCurveParams(params); err != nil {
+		return nil, err
+	}
+	return &CustomCurve{ECParams: params}, nil
+}
+
+// validateCurveParams runs the checks of NewCurve on a complete set of
+// parameters
+func validateCurveParams(params *ECParams) error {
+	p, a, b, G, n, h := params.P, params.A, params.B, params.BasePoint, params.N, params.cofactor()
+	if p.Cmp(big.NewInt(3)) <= 0 || !p.ProbablyPrime(20) {
+		return fmt.Errorf("ecc: p = %d is not a prime larger than 3", p)
+	}
+	for _, value := range []struct {
+		name  string
+		value *big.Int
+	}{{"a", a}, {"b", b}, {"x coordinate of G", G.X}, {"y coordinate of G", G.Y}} {
+		if value.value.Sign() < 0 || value.value.Cmp(p) >= 0 {
+			return fmt.Errorf("ecc: %s is not reduced modulo p", value.name)
+		}
+	}
+
+	// 4a^3 + 27b^2 != 0 (mod p)
+	discriminant := new(big.Int).Exp(a, big.NewInt(3), p)
+	discriminant.Mul(discriminant, big.NewInt(4))
+	discriminant.Add(discriminant, new(big.Int).Mul(big.NewInt(27), new(big.Int).Mul(b, b)))
+	if discriminant.Mod(discriminant, p).Sign() == 0 {
+		return errors.New("ecc: curve is singular, 4a^3 + 27b^2 = 0 (mod p)")
+	}
+	if b.Sign() == 0 {
+		return errors.New("ecc: b must be nonzero, since (0, 0) represents the point at infinity")
+	}
+
+	if !params.IsOnCurve(params.BasePoint) {
+		return errors.New("ecc: base point G is not on the curve")
+	}
+
+	if n.Cmp(big.NewInt(2)) < 0 || !n.ProbablyPrime(20) {
+		return fmt.Errorf("ecc: order n = %d is not prime", n)
+	}
+	if O := ScalarMult(n, params.BasePoint, params); O.X.Sign() != 0 || O.Y.Sign() != 0 {
+		return errors.New("ecc: n * G is not the point at infinity, n is not the order of G")
+	}
+
+	// |p + 1 - h*n| <= 2*sqrt(p), checked as (p + 1 - h*n)^2 <= 4p
+	if h.Sign() <= 0 {
+		return errors.New("ecc: cofactor h must be positive")
+	}
+	trace := new(big.Int).Add(p, big.NewInt(1))
+	trace.Sub(trace, new(big.Int).Mul(h, n))
+	if trace.Mul(trace, trace).Cmp(new(big.Int).Lsh(p, 2)) > 0 {
+		return errors.New("ecc: h * n violates the Hasse bound, h or n is wrong")
+	}
+	return nil
+}
+
+// Name returns the name under which the curve is registered, or "custom" if
+// it is not registered
+func (E *CustomCurve) Name() string {
+	if curve := lookupCurve(E.ECParams); curve != nil {
+		return curve.Name
+	}
+	return "custom"
+}
+
+// CheckSecurity checks the curve for known weaknesses. The returned error
+// joins ErrAnomalousCurve, ErrSmallEmbeddingDegree and ErrSmallSubgroup for
+// each weakness found, and is nil for curves without them.
+func (E *CustomCurve) CheckSecurity() error {
+	var weaknesses []error
+
+	// #E = h * n = p
+	if new(big.Int).Mul(E.cofactor(), E.N).Cmp(E.P) == 0 {
+		weaknesses = append(weaknesses, ErrAnomalousCurve)
+	}
+
+	// The embedding degree is the smallest k with p^k = 1 (mod n)
+	if E.N.Cmp(E.P) != 0 {
+		pk := big.NewInt(1)
+		for k := 1; k <= maximumWeakEmbeddingDegree; k++ {
+			pk.Mul(pk, E.P)
+			pk.Mod(pk, E.N)
+			if pk.Cmp(big.NewInt(1)) == 0 {
+				weaknesses = append(weaknesses, fmt.Errorf("%w (k = %d)", ErrSmallEmbeddingDegree, k))
+				break
+			}
+		}
+	}
+
+	if E.N.BitLen() < minimumOrderBits {
+		weaknesses = append(weaknesses, fmt.Errorf("%w (%d bits, at least %d are needed)", ErrSmallSubgroup, E.N.BitLen(), minimumOrderBits))
+	}
+
+	return errors.Join(weaknesses...)
+}
@@ -0,0 +1,92 @@
+package ecc
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestNewCurve_StandardCurves(t *testing.T) {
+	for _, params := range []*ECParams{GetSecp256k1Parametes().ECParams, GetSecp256r1Parameters().ECParams, GetBrainpoolP384r1Parameters().ECParams} {
+		curve, err := NewCurve(params.P, params.A, params.B, params.BasePoint, params.N, big.NewInt(1))
+		if err != nil {
+			t.Fatalf("Failed to validate standard curve : %v", err)
+		}
+		if err := curve.CheckSecurity(); err != nil {
+			t.Fatalf("Unexpected weakness of standard curve : %v", err)
+		}
+		if info, _ := LookupCurveByParams(params); curve.Name() != info.Name {
+			t.Fatalf("Expected name %s, Observed %s", info.Name, curve.Name())
+		}
+
+		// The curve is usable through the Curve interface
+		var _ Curve = curve
+		priv, err := curve.GeneratePrivateKey(rand.Reader)
+		if err != nil || !curve.IsOnCurve(priv.PublicKey) {
+			t.Fatalf("Failed to generate key on validated curve : %v", err)
+		}
+	}
+}
+
+func TestNewCurve_InvalidParameters(t *testing.T) {
+	toy := func(p, a, b, x, y, n, h int64) error {
+		var cofactor *big.Int
+		if h != 0 {
+			cofactor = big.NewInt(h)
+		}
+		_, err := NewCurve(big.NewInt(p), big.NewInt(a), big.NewInt(b), &Point{X: big.NewInt(x), Y: big.NewInt(y)}, big.NewInt(n), cofactor)
+		return err
+	}
+
+	// y^2 = x^3 + 2x + 3 over F_97 has 100 points, and (3, 6) has order 5
+	if err := toy(97, 2, 3, 3, 6, 5, 20); err != nil {
+		t.Fatalf("Failed to validate toy curve : %v", err)
+	}
+
+	invalid := map[string]error{
+		"not a prime":      toy(91, 2, 3, 3, 6, 5, 20),
+		"not reduced":      toy(97, 99, 3, 3, 6, 5, 20),
+		"singular":         toy(97, 94, 2, 3, 6, 5, 20),
+		"b must be":        toy(97, 2, 0, 3, 6, 5, 20),
+		"not on the curve": toy(97, 2, 3, 3, 7, 5, 20),
+		"is not prime":     toy(97, 2, 3, 3, 6, 25, 4),
+		"not the order":    toy(97, 2, 3, 3, 6, 7, 14),
+		"Hasse bound":      toy(97, 2, 3, 3, 6, 5, 1),
+		"positive":         toy(97, 2, 3, 3, 6, 5, -20),
+	}
+	for expected, err := range invalid {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected error containing %q, Observed %v", expected, err)
+		}
+	}
+}
+
+func TestNewCurve_CheckSecurity(t *testing.T) {
+
+	// Anomalous curve y^2 = x^3 + x + 4 over F_103 with 103 points
+	anomalous, err := NewCurve(big.NewInt(103), big.NewInt(1), big.NewInt(4), &Point{X: big.NewInt(0), Y: big.NewInt(2)}, big.NewInt(103), nil)
+	if err != nil {
+		t.Fatalf("Failed to build anomalous curve : %v", err)
+	}
+	err = anomalous.CheckSecurity()
+	if !errors.Is(err, ErrAnomalousCurve) || !errors.Is(err, ErrSmallSubgroup) || errors.Is(err, ErrSmallEmbeddingDegree) {
+		t.Fatalf("Unexpected weaknesses of anomalous curve : %v", err)
+	}
+
+	// Supersingular curve y^2 = x^3 + 7 over F_p with p = 2 (mod 3) has p + 1
+	// points and embedding degree 2
+	p, _ := new(big.Int).SetString("13123616436e13b7b", 16)
+	n, _ := new(big.Int).SetString("196d9d730492c4f5", 16)
+	gx, _ := new(big.Int).SetString("10d90818e2de902bc", 16)
+	gy, _ := new(big.Int).SetString("6e13f2161b5ed032", 16)
+	supersingular, err := NewCurve(p, big.NewInt(0), big.NewInt(7), &Point{X: gx, Y: gy}, n, big.NewInt(12))
+	if err != nil {
+		t.Fatalf("Failed to build supersingular curve : %v", err)
+	}
+	err = supersingular.CheckSecurity()
+	if !errors.Is(err, ErrSmallEmbeddingDegree) || !strings.Contains(err.Error(), "k = 2") || errors.Is(err, ErrAnomalousCurve) {
+		t.Fatalf("Unexpected weaknesses of supersingular curve : %v", err)
+	}
+}
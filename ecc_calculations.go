@@ -1,6 +1,11 @@
 package ecc
 
 import (
+	"crypto"
+	cryptorand "crypto/rand"
+	"errors"
+	"hash"
+	"io"
 	"math/big"
 )
 
@@ -13,6 +18,157 @@ type Point struct {
 type ECParams struct {
 	P, A, B, N *big.Int
 	BasePoint  *Point
+	H          *big.Int    // Cofactor of the curve, 1 when nil
+	Hash       crypto.Hash // Hash used by Sign and Verify, SHA-256 when zero
+
+	// reduce, when set, is a faster replacement for x mod P used by the point
+	// arithmetic of curves with a special prime
+	reduce func(x *big.Int) *big.Int
+}
+
+// IsOnCurve reports whether P satisfies y^2 = x^3 + ax + b (mod p) with both
+// coordinates reduced modulo p
+func (ec *ECParams) IsOnCurve(P *Point) bool {
+	if P == nil || P.X == nil || P.Y == nil {
+		return false
+	}
+	if P.X.Sign() < 0 || P.X.Cmp(ec.P) >= 0 || P.Y.Sign() < 0 || P.Y.Cmp(ec.P) >= 0 {
+		return false
+	}
+
+	// y^2
+	lhs := new(big.Int).Mul(P.Y, P.Y)
+	lhs.Mod(lhs, ec.P)
+
+	// x^3 + ax + b
+	rhs := new(big.Int).Mul(P.X, P.X)
+	rhs.Mul(rhs, P.X)
+	rhs.Add(rhs, new(big.Int).Mul(ec.A, P.X))
+	rhs.Add(rhs, ec.B)
+	rhs.Mod(rhs, ec.P)
+
+	return lhs.Cmp(rhs) == 0
+}
+
+// clone returns a deep copy of the parameters
+func (ec *ECParams) clone() *ECParams {
+	copied := *ec
+	for _, x := range []**big.Int{&copied.P, &copied.A, &copied.B, &copied.N, &copied.H} {
+		if *x != nil {
+			*x = new(big.Int).Set(*x)
+		}
+	}
+	if ec.BasePoint != nil {
+		copied.BasePoint = &Point{X: new(big.Int).Set(ec.BasePoint.X), Y: new(big.Int).Set(ec.BasePoint.Y)}
+	}
+	return &copied
+}
+
+// cofactor returns the cofactor h of the curve
+func (ec *ECParams) cofactor() *big.Int {
+	if ec.H == nil {
+		return big.NewInt(1)
+	}
+	return ec.H
+}
+
+// hasSmallOrder reports whether the point, multiplied by the cofactor, is the
+// point at infinity
+func (ec *ECParams) hasSmallOrder(P *Point) bool {
+	S := ScalarMult(ec.cofactor(), P, ec)
+	return S.X.Sign() == 0 && S.Y.Sign() == 0
+}
+
+// coordinateSize returns the number of bytes needed to encode a field element
+func (ec *ECParams) coordinateSize() int {
+	return (ec.P.BitLen() + 7) / 8
+}
+
+// scalarSize returns the number of bytes needed to encode a scalar modulo N
+func (ec *ECParams) scalarSize() int {
+	return (ec.N.BitLen() + 7) / 8
+}
+
+// marshalUncompressed encodes P in the SEC 1 uncompressed form 0x04 || X || Y
+func (ec *ECParams) marshalUncompressed(P *Point) []byte {
+	size := ec.coordinateSize()
+	encoded := make([]byte, 1+2*size)
+	encoded[0] = 4
+	P.X.FillBytes(encoded[1 : 1+size])
+	P.Y.FillBytes(encoded[1+size:])
+	return encoded
+}
+
+// unmarshalUncompressed decodes a point in the SEC 1 uncompressed form and
+// checks that it lies on the curve
+func (ec *ECParams) unmarshalUncompressed(data []byte) (*Point, error) {
+	size := ec.coordinateSize()
+	if len(data) != 1+2*size || data[0] != 4 {
+		return nil, errors.New("invalid uncompressed point encoding")
+	}
+	P := &Point{
+		X: new(big.Int).SetBytes(data[1 : 1+size]),
+		Y: new(big.Int).SetBytes(data[1+size:]),
+	}
+	if !ec.IsOnCurve(P) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return P, nil
+}
+
+// mod reduces x modulo P in place and returns it
+func (ec *ECParams) mod(x *big.Int) *big.Int {
+	if ec.reduce != nil {
+		return ec.reduce(x)
+	}
+	return x.Mod(x, ec.P)
+}
+
+// randomScalar returns a uniformly random scalar in the range [1, N-1]. Random
+// bytes are drawn from rand, or crypto/rand when rand is nil, for the size of N
+// with the bits above the length of N cleared, and candidates outside the range
+// are rejected, so the loop ends after two attempts on average for any curve
+// order.
+func (ec *ECParams) randomScalar(rand io.Reader) (*big.Int, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	const MAX_ITER = 100
+	excess := uint(ec.scalarSize()*8 - ec.N.BitLen())
+	for iter := 0; iter < MAX_ITER; iter++ {
+		randomBytes := make([]byte, ec.scalarSize())
+		if _, err := io.ReadFull(rand, randomBytes); err != nil {
+			return nil, err
+		}
+		randomBytes[0] &= 0xff >> excess
+
+		d := new(big.Int).SetBytes(randomBytes)
+		if d.Sign() > 0 && d.Cmp(ec.N) < 0 {
+			return d, nil
+		}
+	}
+	return nil, errors.New("MAX ITERATION REACHED. Cannot generate private key")
+}
+
+// hash returns the hash function used for signatures on the curve
+func (ec *ECParams) hash() func() hash.Hash {
+	return ec.signatureHash().New
+}
+
+// signatureHash returns the hash function used for signatures on the curve,
+// SHA-256 unless Hash is set
+func (ec *ECParams) signatureHash() crypto.Hash {
+	if ec.Hash == 0 {
+		return crypto.SHA256
+	}
+	return ec.Hash
+}
+
+// hashMessage hashes the message with the hash function of the curve
+func (ec *ECParams) hashMessage(message []byte) []byte {
+	hasher := ec.hash()()
+	hasher.Write(message)
+	return hasher.Sum(nil)
 }
 
 // ScalarMult performs scalar multiplication k * P on the elliptic curve
@@ -30,6 +186,28 @@ func ScalarMult(k *big.Int, P *Point, ec *ECParams) *Point {
 	return result
 }
 
+// multiScalarMult computes k1 * P1 + k2 * P2 + ... + kn * Pn with non-negative
+// scalars, one per point. The terms share a single chain of point doublings
+// (Straus' method), so the sum costs little more than the slowest of its scalar
+// multiplications.
+func multiScalarMult(scalars []*big.Int, points []*Point, ec *ECParams) *Point {
+	bitLen := 0
+	for _, k := range scalars {
+		bitLen = max(bitLen, k.BitLen())
+	}
+
+	result := &Point{X: big.NewInt(0), Y: big.NewInt(0)}
+	for i := bitLen - 1; i >= 0; i-- {
+		result = doublePoint(result, ec)
+		for j, k := range scalars {
+			if k.Bit(i) == 1 {
+				result = addPoints(result, points[j], ec)
+			}
+		}
+	}
+	return result
+}
+
 // addPoints adds two points on the elliptic curve
 func addPoints(P, Q *Point, ec *ECParams) *Point {
 	if P.X.Cmp(big.NewInt(0)) == 0 && P.Y.Cmp(big.NewInt(0)) == 0 {
@@ -52,19 +230,19 @@ func addPoints(P, Q *Point, ec *ECParams) *Point {
 	m.Sub(Q.Y, P.Y)
 	xDiff := new(big.Int).Sub(Q.X, P.X)
 	m.Mul(m, new(big.Int).ModInverse(xDiff, ec.P))
-	m.Mod(m, ec.P)
+	ec.mod(m)
 
 	// Calculate the new X coordinate
 	xR := new(big.Int).Mul(m, m)
 	xR.Sub(xR, P.X)
 	xR.Sub(xR, Q.X)
-	xR.Mod(xR, ec.P)
+	ec.mod(xR)
 
 	// Calculate the new Y coordinate
 	yR := new(big.Int).Sub(P.X, xR)
 	yR.Mul(yR, m)
 	yR.Sub(yR, P.Y)
-	yR.Mod(yR, ec.P)
+	ec.mod(yR)
 
 	return &Point{X: xR, Y: yR}
 }
@@ -78,16 +256,16 @@ func doublePoint(P *Point, ec *ECParams) *Point {
 	m := new(big.Int).Mul(big.NewInt(3), new(big.Int).Mul(P.X, P.X))
 	m.Add(m, ec.A)
 	m.Mul(m, new(big.Int).ModInverse(new(big.Int).Mul(big.NewInt(2), P.Y), ec.P))
-	m.Mod(m, ec.P)
+	ec.mod(m)
 
 	xR := new(big.Int).Mul(m, m)
 	xR.Sub(xR, new(big.Int).Mul(big.NewInt(2), P.X))
-	xR.Mod(xR, ec.P)
+	ec.mod(xR)
 
 	yR := new(big.Int).Sub(P.X, xR)
 	yR.Mul(yR, m)
 	yR.Sub(yR, P.Y)
-	yR.Mod(yR, ec.P)
+	ec.mod(yR)
 
 	return &Point{X: xR, Y: yR}
 }
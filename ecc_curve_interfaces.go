@@ -2,7 +2,9 @@ package ecc
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 )
 
@@ -13,7 +15,8 @@ type ECPrivateKey struct {
 }
 
 type ECSignature struct {
-	r, s *big.Int
+	r, s  *big.Int
+	curve *ECParams // curve the signature was made or parsed for, may be nil
 }
 
 // GeneratePublicKey interface returns a point struct which is an X,Y coordinate
@@ -23,13 +26,89 @@ type GeneratePublicKey interface {
 
 // GeneratePrivateKey returns a private key struct
 type GeneratePrivateKey interface {
-	GeneratePrivateKey() (*ECPrivateKey, error)
+	GeneratePrivateKey(rand io.Reader) (*ECPrivateKey, error)
 }
 
 type IsValidPrivateKey interface {
 	IsValidPrivateKey(key *ECPrivateKey) bool
 }
 
+// Curve is implemented by every curve of the package (Secp256k1, Secp256r1,
+// Secp384r1, Secp521r1 and the Brainpool curves). The key handling methods are
+// shared through the embedded ECParams, so they behave the same on all curves.
+type Curve interface {
+	// Name returns the standard name of the curve, as used by LookupCurve
+	Name() string
+	Params() *ECParams
+	// BitSize returns the size of the underlying field in bits
+	BitSize() int
+	GeneratePrivateKey
+	IsValidPrivateKey
+	// NewPrivateKey creates a private key from its big-endian encoding
+	NewPrivateKey(d []byte) (*ECPrivateKey, error)
+	// NewPublicKey creates a public key from its SEC 1 uncompressed encoding
+	NewPublicKey(data []byte) (*Point, error)
+}
+
+// Params returns the curve parameters
+func (ec *ECParams) Params() *ECParams {
+	return ec
+}
+
+// BitSize returns the size of the underlying field in bits
+func (ec *ECParams) BitSize() int {
+	return ec.P.BitLen()
+}
+
+// GeneratePrivateKey generates a private key uniformly in the range [1, N-1]
+// using random bytes read from rand, or from crypto/rand when rand is nil. The
+// public key is computed as well.
+func (ec *ECParams) GeneratePrivateKey(rand io.Reader) (*ECPrivateKey, error) {
+	d, err := ec.randomScalar(rand)
+	if err != nil {
+		return &ECPrivateKey{}, err
+	}
+	privateKey := ECPrivateKey{D: d, curve: ec, PublicKey: &Point{}}
+	privateKey.GeneratePublicKey()
+	return &privateKey, nil
+}
+
+// IsValidPrivateKey reports whether 0 < d < N and, for keys that are bound to
+// a curve, whether the key belongs to this curve
+func (ec *ECParams) IsValidPrivateKey(key *ECPrivateKey) bool {
+	if key == nil || key.D == nil {
+		return false
+	}
+	if key.curve != nil && !sameCurve(key.curve, ec) {
+		return false
+	}
+	return key.D.Sign() > 0 && key.D.Cmp(ec.N) < 0
+}
+
+// NewPrivateKey creates a private key from its big-endian encoding, which must
+// be as long as the encoding of N, and computes the public key
+func (ec *ECParams) NewPrivateKey(d []byte) (*ECPrivateKey, error) {
+	if len(d) != ec.scalarSize() {
+		return nil, fmt.Errorf("ecc: private key must be %d bytes, got %d", ec.scalarSize(), len(d))
+	}
+	privateKey := ECPrivateKey{D: new(big.Int).SetBytes(d), curve: ec, PublicKey: &Point{}}
+	if !ec.IsValidPrivateKey(&privateKey) {
+		return nil, errors.New("ecc: private key is out of range")
+	}
+	privateKey.GeneratePublicKey()
+	return &privateKey, nil
+}
+
+// NewPublicKey creates a public key from its SEC 1 uncompressed encoding
+// 0x04 || X || Y and checks that it lies on the curve
+func (ec *ECParams) NewPublicKey(data []byte) (*Point, error) {
+	publicKey, err := ec.unmarshalUncompressed(data)
+	if err != nil {
+		return nil, fmt.Errorf("ecc: %w", err)
+	}
+	return publicKey, nil
+}
+
 func CreatePrivateKeyFromScalar(E *ECParams, scalar *big.Int) *ECPrivateKey {
 	privateKey := ECPrivateKey{D: scalar, curve: E, PublicKey: &Point{}}
 	return &privateKey
@@ -70,49 +149,77 @@ func (key *ECPrivateKey) GeneratePublicKey() *Point {
 }
 
 // ECDH Runs the ECDH and returns the shared key X,Y coordinates
+// without validating the public key. SharedSecret returns the encoded
+// x-coordinate after validation, and DeriveSharedKey a key derived from it.
 func (key *ECPrivateKey) ECDH(public *Point) *Point {
 	result := ScalarMult(key.D, public, key.curve)
 	return result
 }
 
+// Sign signs the hash of the message, computed with the hash function of the
+// curve. The nonce k is derived deterministically from the private key and the
+// hash as described in RFC 6979.
 func (key *ECPrivateKey) Sign(message []byte) *ECSignature {
+	signature, _ := key.SignRecoverable(message)
+	return signature
+}
 
-	// Step 1 : Hash the message
-	messageHash := new(big.Int)
-	messageHash.SetBytes(Messagehash256(message))
-	//messageHash.SetString("86032112319101611046176971828093669637772856272773459297323797145286374828050", 10)
-	fmt.Printf("Hash of message : %x\n", messageHash)
-	//fmt.Printf("length of Hash of message : %d\n", len(messageHash.Bytes()))
-
-	// Step 2 : Select k such that 1 < k < n
-	//kdata, _ := GenerateRandomBytes(32)
-	k, _ := new(big.Int).SetString("28695618543805844332113829720373285210420739438570883203839696518176414791234", 10)
-
-	// Step 3 : Find R = k * G
-	R := ScalarMult(k, key.curve.BasePoint, key.curve)
-	//fmt.Printf("R : %d\n", R)
-	//fmt.Printf("Rx : %x\n", R.X)
-
-	// Step 4 :Calculate r = x coordinate of R % n
-	r := new(big.Int).Mod(R.X, key.curve.N)
-	//fmt.Printf("r = Rx mod n : %d\n", r)
-
-	// Step 5: Find inverse modulo of k
-	k_inv := new(big.Int).ModInverse(k, key.curve.N)
-
-	// Step 6 : (k^-1( hash + r*d ))(modN)
-	s := new(big.Int).Mod(new(big.Int).Mul(k_inv, new(big.Int).Add(new(big.Int).Mul(r, key.D), messageHash)), key.curve.N)
-	return &ECSignature{r: r, s: s}
+// SignRecoverable signs like Sign and also returns the recovery identifier of
+// the signature: bit 0 is the parity of the y-coordinate of R = k * G and bit 1
+// is set when the x-coordinate of R is at least n. Together with r, it
+// identifies R, which lets verifiers such as VerifyECDSABatch work with R
+// directly.
+func (key *ECPrivateKey) SignRecoverable(message []byte) (*ECSignature, byte) {
+
+	// Step 1 : Hash the message, keeping its leftmost bits when it is longer than n
+	digest := key.curve.hashMessage(message)
+	messageHash := bits2int(digest, key.curve.N)
+
+	nonces := newRFC6979Nonces(key.D, digest, key.curve.N, key.curve.hash())
+	for {
+		// Step 2 : Select k such that 1 < k < n
+		k := nonces.next()
+
+		// Step 3 : Find R = k * G
+		R := ScalarMult(k, key.curve.BasePoint, key.curve)
+
+		// Step 4 :Calculate r = x coordinate of R % n
+		r := new(big.Int).Mod(R.X, key.curve.N)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		// Step 5: Find inverse modulo of k
+		k_inv := new(big.Int).ModInverse(k, key.curve.N)
+
+		// Step 6 : (k^-1( hash + r*d ))(modN)
+		s := new(big.Int).Mod(new(big.Int).Mul(k_inv, new(big.Int).Add(new(big.Int).Mul(r, key.D), messageHash)), key.curve.N)
+		if s.Sign() == 0 {
+			continue
+		}
+		recoveryID := byte(R.Y.Bit(0))
+		if R.X.Cmp(key.curve.N) >= 0 {
+			recoveryID |= 2
+		}
+		return &ECSignature{r: r, s: s, curve: key.curve}, recoveryID
+	}
 }
 
 func (publicKey *Point) Verify(message []byte, signature *ECSignature, params *ECParams) bool {
 
-	// Step 1 : Hash the message
-	messageHash := new(big.Int)
-	messageHash.SetBytes(Messagehash256(message))
-	//messageHash.SetString("86032112319101611046176971828093669637772856272773459297323797145286374828050", 10)
-	fmt.Printf("Hash of message : %x\n", messageHash)
-	//fmt.Printf("length of Hash of message : %d\n", len(messageHash.Bytes()))
+	// r and s must lie in [1, n-1]
+	if signature == nil || signature.r == nil || signature.s == nil {
+		return false
+	}
+	if signature.curve != nil && !sameCurve(signature.curve, params) {
+		return false
+	}
+	if signature.r.Sign() <= 0 || signature.r.Cmp(params.N) >= 0 || signature.s.Sign() <= 0 || signature.s.Cmp(params.N) >= 0 {
+		return false
+	}
+
+	// Step 1 : Hash the message, keeping its leftmost bits when it is longer than n
+	messageHash := bits2int(params.hashMessage(message), params.N)
 
 	// Compute modulo inverse of s
 	// s * x === 1 % N
@@ -141,7 +248,7 @@ func (publicKey *Point) Verify(message []byte, signature *ECSignature, params *E
 	//fmt.Printf("R_dash = (s^-1 (hash*G + Rx*P)) mod P  : %d\n", R_dash)
 
 	// Compare x cordinate of Rdash with Signature's r
-	if R_dash.X.Cmp(signature.r) == 0 {
+	if new(big.Int).Mod(R_dash.X, params.N).Cmp(signature.r) == 0 {
 		return true
 	}
 	return false
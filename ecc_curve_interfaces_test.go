@@ -0,0 +1,117 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func allCurves() []Curve {
+	return []Curve{
+		GetSecp256k1Parametes(),
+		GetSecp256r1Parameters(),
+		GetSecp384r1Parameters(),
+		GetSecp521r1Parameters(),
+		GetBrainpoolP256r1Parameters(),
+		GetBrainpoolP256t1Parameters(),
+		GetBrainpoolP384r1Parameters(),
+		GetBrainpoolP384t1Parameters(),
+		GetBrainpoolP512r1Parameters(),
+		GetBrainpoolP512t1Parameters(),
+		GetSM2Parameters(),
+	}
+}
+
+func TestCurve_NamesAndSizes(t *testing.T) {
+	bitSizes := map[string]int{
+		"secp256k1": 256, "P-256": 256, "P-384": 384, "P-521": 521,
+		"brainpoolP256r1": 256, "brainpoolP256t1": 256, "brainpoolP384r1": 384,
+		"brainpoolP384t1": 384, "brainpoolP512r1": 512, "brainpoolP512t1": 512,
+		"SM2": 256,
+	}
+	for _, curve := range allCurves() {
+		if curve.BitSize() != bitSizes[curve.Name()] {
+			t.Fatalf("%s: Expected bit size %d, Observed %d", curve.Name(), bitSizes[curve.Name()], curve.BitSize())
+		}
+		info, err := LookupCurve(curve.Name())
+		if err != nil || !sameCurve(info.Params, curve.Params()) {
+			t.Fatalf("%s: name does not resolve to the curve : %v", curve.Name(), err)
+		}
+	}
+}
+
+func TestCurve_PrivateKeys(t *testing.T) {
+	for _, curve := range allCurves() {
+		params := curve.Params()
+
+		// A fixed random source gives a fixed key
+		seed := bytes.Repeat([]byte{0x5a}, 2*params.scalarSize())
+		priv1, err := curve.GeneratePrivateKey(bytes.NewReader(seed))
+		if err != nil {
+			t.Fatalf("%s: failed to generate private key : %v", curve.Name(), err)
+		}
+		priv2, _ := curve.GeneratePrivateKey(bytes.NewReader(seed))
+		if priv1.D.Cmp(priv2.D) != 0 || !curve.IsValidPrivateKey(priv1) || !params.IsOnCurve(priv1.PublicKey) {
+			t.Fatalf("%s: unexpected generated key %x", curve.Name(), priv1.D)
+		}
+		if _, err := curve.GeneratePrivateKey(bytes.NewReader(nil)); err == nil {
+			t.Fatalf("%s: expected error for an empty random source", curve.Name())
+		}
+		if priv, err := curve.GeneratePrivateKey(rand.Reader); err != nil || !curve.IsValidPrivateKey(priv) {
+			t.Fatalf("%s: failed to generate private key : %v", curve.Name(), err)
+		}
+
+		// Validity is 0 < d < n on every curve, including keys with leading zero bytes
+		for _, d := range []*big.Int{big.NewInt(1), new(big.Int).Sub(params.N, big.NewInt(1))} {
+			if !curve.IsValidPrivateKey(&ECPrivateKey{D: d}) {
+				t.Fatalf("%s: expected %x to be a valid private key", curve.Name(), d)
+			}
+		}
+		for _, d := range []*big.Int{big.NewInt(0), new(big.Int).Set(params.N), big.NewInt(-1)} {
+			if curve.IsValidPrivateKey(&ECPrivateKey{D: d}) {
+				t.Fatalf("%s: expected %x to be an invalid private key", curve.Name(), d)
+			}
+		}
+
+		// NewPrivateKey takes the fixed-length encoding of d
+		encoded := priv1.D.FillBytes(make([]byte, params.scalarSize()))
+		priv, err := curve.NewPrivateKey(encoded)
+		if err != nil || priv.PublicKey.X.Cmp(priv1.PublicKey.X) != 0 {
+			t.Fatalf("%s: failed to create private key : %v", curve.Name(), err)
+		}
+		if _, err := curve.NewPrivateKey(encoded[1:]); err == nil {
+			t.Fatalf("%s: expected error for a short private key", curve.Name())
+		}
+		if _, err := curve.NewPrivateKey(params.N.FillBytes(make([]byte, params.scalarSize()))); err == nil {
+			t.Fatalf("%s: expected error for d = n", curve.Name())
+		}
+	}
+
+	// Keys of one curve are not valid on another
+	k1, _ := GetSecp256k1Parametes().GeneratePrivateKey(rand.Reader)
+	if GetSecp256r1Parameters().IsValidPrivateKey(k1) {
+		t.Fatalf("secp256k1 key accepted as P-256 key")
+	}
+}
+
+func TestCurve_PublicKeys(t *testing.T) {
+	for _, curve := range allCurves() {
+		params := curve.Params()
+		priv, _ := curve.GeneratePrivateKey(rand.Reader)
+		encoded := params.marshalUncompressed(priv.PublicKey)
+
+		publicKey, err := curve.NewPublicKey(encoded)
+		if err != nil || publicKey.X.Cmp(priv.PublicKey.X) != 0 || publicKey.Y.Cmp(priv.PublicKey.Y) != 0 {
+			t.Fatalf("%s: failed to create public key : %v", curve.Name(), err)
+		}
+
+		encoded[len(encoded)-1] ^= 1
+		if _, err := curve.NewPublicKey(encoded); err == nil {
+			t.Fatalf("%s: expected error for a point off the curve", curve.Name())
+		}
+		if _, err := curve.NewPublicKey(encoded[1:]); err == nil {
+			t.Fatalf("%s: expected error for a malformed encoding", curve.Name())
+		}
+	}
+}
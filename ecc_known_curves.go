@@ -0,0 +1,248 @@
+package ecc
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CurveInfo describes a curve of the registry together with the identifiers
+// used for it by names, ASN.1 and the key formats of this package. Only Name
+// and Params are required, the other identifiers are left empty when a format
+// does not define one for the curve. Curves and the lookup functions return
+// copies, so changing them does not affect the registry.
+type CurveInfo struct {
+	Name    string   // standard name, e.g. "P-256"
+	Aliases []string // further names, e.g. "secp256r1" and "prime256v1"
+	OID     asn1.ObjectIdentifier
+	Params  *ECParams
+
+	ID            byte   // leading byte of the binary encodings of encoding.go
+	JWK           string // "crv" member of a JSON Web Key
+	JWS           string // "alg" of JWS signatures
+	COSECurve     int64  // COSE elliptic curve identifier
+	COSEAlgorithm int64  // COSE signature algorithm
+	SSH           string // SSH curve identifier, e.g. "nistp256"
+}
+
+var (
+	curveRegistryLock sync.RWMutex
+	curveRegistry     = []*CurveInfo{
+		{Name: "secp256k1", OID: asn1.ObjectIdentifier{1, 3, 132, 0, 10}, Params: GetSecp256k1Parametes().ECParams,
+			ID: 1, JWK: "secp256k1", JWS: "ES256K", COSECurve: 8, COSEAlgorithm: -47},
+		{Name: "P-256", Aliases: []string{"secp256r1", "prime256v1"}, OID: asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, Params: GetSecp256r1Parameters().ECParams,
+			ID: 2, JWK: "P-256", JWS: "ES256", COSECurve: 1, COSEAlgorithm: -7, SSH: "nistp256"},
+		{Name: "brainpoolP256t1", OID: OIDBrainpoolP256t1, Params: GetBrainpoolP256t1Parameters().ECParams,
+			ID: 3, JWK: "brainpoolP256t1"},
+		{Name: "P-384", Aliases: []string{"secp384r1"}, OID: asn1.ObjectIdentifier{1, 3, 132, 0, 34}, Params: GetSecp384r1Parameters().ECParams,
+			ID: 4, JWK: "P-384", JWS: "ES384", COSECurve: 2, COSEAlgorithm: -35, SSH: "nistp384"},
+		{Name: "P-521", Aliases: []string{"secp521r1"}, OID: asn1.ObjectIdentifier{1, 3, 132, 0, 35}, Params: GetSecp521r1Parameters().ECParams,
+			ID: 5, JWK: "P-521", JWS: "ES512", COSECurve: 3, COSEAlgorithm: -36, SSH: "nistp521"},
+		{Name: "brainpoolP256r1", OID: OIDBrainpoolP256r1, Params: GetBrainpoolP256r1Parameters().ECParams,
+			ID: 6, JWK: "brainpoolP256r1"},
+		{Name: "brainpoolP384r1", OID: OIDBrainpoolP384r1, Params: GetBrainpoolP384r1Parameters().ECParams,
+			ID: 7, JWK: "brainpoolP384r1"},
+		{Name: "brainpoolP384t1", OID: OIDBrainpoolP384t1, Params: GetBrainpoolP384t1Parameters().ECParams,
+			ID: 8, JWK: "brainpoolP384t1"},
+		{Name: "brainpoolP512r1", OID: OIDBrainpoolP512r1, Params: GetBrainpoolP512r1Parameters().ECParams,
+			ID: 9, JWK: "brainpoolP512r1"},
+		{Name: "brainpoolP512t1", OID: OIDBrainpoolP512t1, Params: GetBrainpoolP512t1Parameters().ECParams,
+			ID: 10, JWK: "brainpoolP512t1"},
+		{Name: "SM2", Aliases: []string{"sm2p256v1"}, OID: OIDSM2, Params: GetSM2Parameters().ECParams,
+			ID: 11},
+	}
+)
+
+// RegisterCurve adds a copy of a curve to the registry, making it available to
+// the lookup functions and to the formats for which identifiers are given. The
+// parameters must pass the checks of NewCurve. Names and aliases are compared
+// case-insensitively, and none of the identifiers nor the parameters may
+// already be registered.
+func RegisterCurve(info *CurveInfo) error {
+	if info == nil || info.Name == "" {
+		return errors.New("ecc: curve must have a name")
+	}
+	params := info.Params
+	if params == nil || params.P == nil || params.A == nil || params.B == nil || params.N == nil ||
+		params.BasePoint == nil || params.BasePoint.X == nil || params.BasePoint.Y == nil {
+		return fmt.Errorf("ecc: curve %s has incomplete parameters", info.Name)
+	}
+	if err := validateCurveParams(params); err != nil {
+		return fmt.Errorf("ecc: invalid parameters for curve %s: %w", info.Name, err)
+	}
+
+	curveRegistryLock.Lock()
+	defer curveRegistryLock.Unlock()
+
+	names := append([]string{info.Name}, info.Aliases...)
+	for _, curve := range curveRegistry {
+		for _, name := range names {
+			if curve.hasName(name) {
+				return fmt.Errorf("ecc: curve name %q is already registered", name)
+			}
+		}
+		switch {
+		case len(info.OID) > 0 && info.OID.Equal(curve.OID):
+			return fmt.Errorf("ecc: curve OID %s is already registered", info.OID)
+		case sameCurve(info.Params, curve.Params):
+			return fmt.Errorf("ecc: parameters of curve %s are already registered as %s", info.Name, curve.Name)
+		case info.ID != 0 && info.ID == curve.ID:
+			return fmt.Errorf("ecc: curve identifier %d is already registered", info.ID)
+		case info.JWK != "" && info.JWK == curve.JWK:
+			return fmt.Errorf("ecc: JWK curve %q is already registered", info.JWK)
+		case info.JWS != "" && info.JWS == curve.JWS:
+			return fmt.Errorf("ecc: JWS algorithm %q is already registered", info.JWS)
+		case info.COSECurve != 0 && info.COSECurve == curve.COSECurve:
+			return fmt.Errorf("ecc: COSE curve %d is already registered", info.COSECurve)
+		case info.COSEAlgorithm != 0 && info.COSEAlgorithm == curve.COSEAlgorithm:
+			return fmt.Errorf("ecc: COSE algorithm %d is already registered", info.COSEAlgorithm)
+		case info.SSH != "" && info.SSH == curve.SSH:
+			return fmt.Errorf("ecc: SSH curve %q is already registered", info.SSH)
+		}
+	}
+
+	curveRegistry = append(curveRegistry, info.clone())
+	return nil
+}
+
+// Curves returns copies of all registered curves in the order of
+// registration, starting with the curves of this package
+func Curves() []*CurveInfo {
+	curveRegistryLock.RLock()
+	defer curveRegistryLock.RUnlock()
+	curves := make([]*CurveInfo, len(curveRegistry))
+	for i, curve := range curveRegistry {
+		curves[i] = curve.clone()
+	}
+	return curves
+}
+
+// LookupCurve resolves a curve from a string, which may be its name, one of its
+// aliases, its JWK curve name or its OID in dotted form such as
+// "1.2.840.10045.3.1.7". Names are compared case-insensitively.
+func LookupCurve(name string) (*CurveInfo, error) {
+	curve := findCurve(func(curve *CurveInfo) bool {
+		return curve.hasName(name) || curve.JWK == name || (len(curve.OID) > 0 && curve.OID.String() == name)
+	})
+	if curve == nil {
+		return nil, fmt.Errorf("ecc: unknown curve %q", name)
+	}
+	return curve.clone(), nil
+}
+
+// LookupCurveByOID resolves a curve from its ASN.1 object identifier
+func LookupCurveByOID(oid asn1.ObjectIdentifier) (*CurveInfo, error) {
+	curve := findCurve(func(curve *CurveInfo) bool {
+		return len(curve.OID) > 0 && curve.OID.Equal(oid)
+	})
+	if curve == nil {
+		return nil, fmt.Errorf("ecc: unknown curve OID %s", oid)
+	}
+	return curve.clone(), nil
+}
+
+// LookupCurveByJWK resolves a curve from the "crv" member of a JSON Web Key
+func LookupCurveByJWK(crv string) (*CurveInfo, error) {
+	curve := findCurve(func(curve *CurveInfo) bool {
+		return curve.JWK != "" && curve.JWK == crv
+	})
+	if curve == nil {
+		return nil, fmt.Errorf("ecc: unknown JWK curve %q", crv)
+	}
+	return curve.clone(), nil
+}
+
+// LookupCurveByCOSE resolves a curve from its COSE elliptic curve identifier
+func LookupCurveByCOSE(crv int64) (*CurveInfo, error) {
+	curve := findCurve(func(curve *CurveInfo) bool {
+		return curve.COSECurve != 0 && curve.COSECurve == crv
+	})
+	if curve == nil {
+		return nil, fmt.Errorf("ecc: unknown COSE curve %d", crv)
+	}
+	return curve.clone(), nil
+}
+
+// LookupCurveByParams returns the registered curve with the given parameters
+func LookupCurveByParams(params *ECParams) (*CurveInfo, error) {
+	curve := lookupCurve(params)
+	if curve == nil {
+		return nil, errors.New("ecc: curve parameters are not registered")
+	}
+	return curve.clone(), nil
+}
+
+// hasName reports whether name is the name or an alias of the curve
+func (curve *CurveInfo) hasName(name string) bool {
+	if strings.EqualFold(curve.Name, name) {
+		return true
+	}
+	for _, alias := range curve.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a deep copy of the curve and its parameters
+func (curve *CurveInfo) clone() *CurveInfo {
+	copied := *curve
+	copied.Aliases = append([]string(nil), curve.Aliases...)
+	copied.OID = append(asn1.ObjectIdentifier(nil), curve.OID...)
+	copied.Params = curve.Params.clone()
+	return &copied
+}
+
+// findCurve returns the first registered curve for which match is true
+func findCurve(match func(curve *CurveInfo) bool) *CurveInfo {
+	curveRegistryLock.RLock()
+	defer curveRegistryLock.RUnlock()
+	for _, curve := range curveRegistry {
+		if match(curve) {
+			return curve
+		}
+	}
+	return nil
+}
+
+// sameCurve reports whether two parameter sets describe the same curve
+func sameCurve(E1, E2 *ECParams) bool {
+	if E1 == E2 {
+		return true
+	}
+	if E1 == nil || E2 == nil {
+		return false
+	}
+	return E1.P.Cmp(E2.P) == 0 && E1.A.Cmp(E2.A) == 0 && E1.B.Cmp(E2.B) == 0 &&
+		E1.N.Cmp(E2.N) == 0 &&
+		E1.BasePoint.X.Cmp(E2.BasePoint.X) == 0 && E1.BasePoint.Y.Cmp(E2.BasePoint.Y) == 0
+}
+
+// lookupCurve returns the registered curve matching the given parameters
+func lookupCurve(E *ECParams) *CurveInfo {
+	return findCurve(func(curve *CurveInfo) bool {
+		return sameCurve(curve.Params, E)
+	})
+}
+
+// lookupCurveOfPoint returns the registered curve on which P lies. Points carry
+// no reference to their curve, so the curve equation of every registered curve
+// is checked in turn, and nil is returned when P lies on more than one of them,
+// as do the points common to brainpoolP256r1 and brainpoolP256t1.
+func lookupCurveOfPoint(P *Point) *CurveInfo {
+	curveRegistryLock.RLock()
+	defer curveRegistryLock.RUnlock()
+	var found *CurveInfo
+	for _, curve := range curveRegistry {
+		if curve.Params.IsOnCurve(P) {
+			if found != nil {
+				return nil
+			}
+			found = curve
+		}
+	}
+	return found
+}
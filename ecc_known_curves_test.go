@@ -0,0 +1,159 @@
+package ecc
+
+import (
+	"crypto"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestRegistry_LookupCurve(t *testing.T) {
+	names := map[string]*ECParams{
+		"secp256k1":           GetSecp256k1Parametes().ECParams,
+		"P-256":               GetSecp256r1Parameters().ECParams,
+		"prime256v1":          GetSecp256r1Parameters().ECParams,
+		"SECP256R1":           GetSecp256r1Parameters().ECParams,
+		"1.2.840.10045.3.1.7": GetSecp256r1Parameters().ECParams,
+		"secp384r1":           GetSecp384r1Parameters().ECParams,
+		"p-521":               GetSecp521r1Parameters().ECParams,
+		"brainpoolP256t1":     GetBrainpoolP256t1Parameters().ECParams,
+		"brainpoolP512r1":     GetBrainpoolP512r1Parameters().ECParams,
+	}
+	for name, params := range names {
+		curve, err := LookupCurve(name)
+		if err != nil {
+			t.Fatalf("Failed to look up %s : %v", name, err)
+		}
+		if !sameCurve(curve.Params, params) {
+			t.Fatalf("%s resolved to the wrong curve %s", name, curve.Name)
+		}
+	}
+	if _, err := LookupCurve("P-999"); err == nil {
+		t.Fatalf("Expected error for an unknown curve")
+	}
+}
+
+func TestRegistry_LookupByIdentifiers(t *testing.T) {
+	curve, err := LookupCurveByOID(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+	if err != nil || curve.Name != "secp256k1" {
+		t.Fatalf("OID lookup failed : %v", err)
+	}
+	curve, err = LookupCurveByOID(OIDBrainpoolP384t1)
+	if err != nil || curve.Name != "brainpoolP384t1" {
+		t.Fatalf("OID lookup failed : %v", err)
+	}
+	curve, err = LookupCurveByJWK("P-384")
+	if err != nil || !sameCurve(curve.Params, GetSecp384r1Parameters().ECParams) {
+		t.Fatalf("JWK lookup failed : %v", err)
+	}
+	if _, err := LookupCurveByJWK("p-384"); err == nil {
+		t.Fatalf("JWK curve names are case-sensitive")
+	}
+	curve, err = LookupCurveByCOSE(8)
+	if err != nil || curve.Name != "secp256k1" {
+		t.Fatalf("COSE lookup failed : %v", err)
+	}
+	curve, err = LookupCurveByParams(GetSecp521r1Parameters().ECParams)
+	if err != nil || curve.Name != "P-521" {
+		t.Fatalf("Parameter lookup failed : %v", err)
+	}
+}
+
+func TestRegistry_RegisterCurve(t *testing.T) {
+	curveRegistryLock.RLock()
+	saved := append([]*CurveInfo(nil), curveRegistry...)
+	curveRegistryLock.RUnlock()
+	defer func() {
+		curveRegistryLock.Lock()
+		curveRegistry = saved
+		curveRegistryLock.Unlock()
+	}()
+
+	// y^2 = x^3 + 2x + 3 over F_97 has 100 points, and (3, 6) has order 5
+	toy := &ECParams{P: big.NewInt(97), A: big.NewInt(2), B: big.NewInt(3), N: big.NewInt(5), H: big.NewInt(20),
+		BasePoint: &Point{X: big.NewInt(3), Y: big.NewInt(6)}}
+	if err := RegisterCurve(&CurveInfo{Name: "toy97", Aliases: []string{"textbook"}, OID: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1},
+		Params: toy, ID: 200, JWK: "toy97"}); err != nil {
+		t.Fatalf("Failed to register curve : %v", err)
+	}
+
+	for _, name := range []string{"toy97", "TEXTBOOK", "1.3.6.1.4.1.99999.1"} {
+		curve, err := LookupCurve(name)
+		if err != nil || !sameCurve(curve.Params, toy) || curve.Params == toy {
+			t.Fatalf("Failed to look up %s : %v", name, err)
+		}
+	}
+
+	// The formats pick up the registered identifiers
+	publicKey := CreatePrivateKeyFromScalar(toy, big.NewInt(2)).GeneratePublicKey()
+	encoded, err := json.Marshal(publicKey)
+	if err != nil {
+		t.Fatalf("Failed to encode JWK : %v", err)
+	}
+	var decoded Point
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.X.Cmp(publicKey.X) != 0 {
+		t.Fatalf("Failed to decode JWK %s : %v", encoded, err)
+	}
+	binary, err := publicKey.MarshalBinary()
+	if err != nil || binary[0] != 200 {
+		t.Fatalf("Unexpected binary encoding %x : %v", binary, err)
+	}
+
+	// Conflicting and invalid registrations are rejected
+	invalid := []*CurveInfo{
+		{Name: "P-256", Params: &ECParams{P: big.NewInt(97), A: big.NewInt(2), B: big.NewInt(3), N: big.NewInt(5), BasePoint: &Point{X: big.NewInt(80), Y: big.NewInt(10)}}},
+		{Name: "prime256V1", Params: toy},
+		{Name: "toy97-copy", Params: toy},
+		{Name: "off-curve", Params: &ECParams{P: big.NewInt(97), A: big.NewInt(2), B: big.NewInt(3), N: big.NewInt(5), H: big.NewInt(20), BasePoint: &Point{X: big.NewInt(3), Y: big.NewInt(7)}}},
+		{Name: "composite-p", Params: &ECParams{P: big.NewInt(91), A: big.NewInt(2), B: big.NewInt(3), N: big.NewInt(5), BasePoint: &Point{X: big.NewInt(3), Y: big.NewInt(6)}}},
+		{Name: "wrong-order", Params: &ECParams{P: big.NewInt(97), A: big.NewInt(2), B: big.NewInt(3), N: big.NewInt(7), H: big.NewInt(14), BasePoint: &Point{X: big.NewInt(3), Y: big.NewInt(6)}}},
+		{Name: "no-cofactor", Params: &ECParams{P: big.NewInt(97), A: big.NewInt(2), B: big.NewInt(3), N: big.NewInt(5), BasePoint: &Point{X: big.NewInt(3), Y: big.NewInt(6)}}},
+		{Name: "no-params"},
+		{Params: GetSecp256k1Parametes().ECParams},
+	}
+	for _, info := range invalid {
+		if err := RegisterCurve(info); err == nil {
+			t.Fatalf("Expected error when registering %q", info.Name)
+		}
+	}
+}
+
+func TestRegistry_ReturnsCopies(t *testing.T) {
+	curve, _ := LookupCurve("P-256")
+	curve.Params.Hash = crypto.SHA1
+	curve.Params.BasePoint.X.SetInt64(1)
+	curve.JWK = "bogus"
+	curve.Aliases[0] = "bogus"
+	Curves()[1].Params.N.SetInt64(1)
+
+	curve, err := LookupCurve("prime256v1")
+	if err != nil || curve.Params.Hash != 0 || !sameCurve(curve.Params, GetSecp256r1Parameters().ECParams) {
+		t.Fatalf("Registry changed through a returned curve : %v", err)
+	}
+	if _, err := LookupCurveByJWK("P-256"); err != nil {
+		t.Fatalf("Registry changed through a returned curve : %v", err)
+	}
+	if _, err := LookupCurve("secp256r1"); err != nil {
+		t.Fatalf("Registry changed through a returned curve : %v", err)
+	}
+
+	// RegisterCurve keeps its own copy as well
+	curveRegistryLock.RLock()
+	saved := append([]*CurveInfo(nil), curveRegistry...)
+	curveRegistryLock.RUnlock()
+	defer func() {
+		curveRegistryLock.Lock()
+		curveRegistry = saved
+		curveRegistryLock.Unlock()
+	}()
+	toy := &ECParams{P: big.NewInt(97), A: big.NewInt(2), B: big.NewInt(3), N: big.NewInt(5), H: big.NewInt(20),
+		BasePoint: &Point{X: big.NewInt(3), Y: big.NewInt(6)}}
+	if err := RegisterCurve(&CurveInfo{Name: "toy97", Params: toy}); err != nil {
+		t.Fatalf("Failed to register curve : %v", err)
+	}
+	toy.N.SetInt64(7)
+	if curve, err := LookupCurve("toy97"); err != nil || curve.Params.N.Int64() != 5 {
+		t.Fatalf("Registry changed through the registered parameters : %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package ecc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+// ECIESKDF selects the key derivation function of ECIES
+type ECIESKDF int
+
+const (
+	// ECIESX963 derives the key with the ANSI X9.63 KDF and SHA-256
+	ECIESX963 ECIESKDF = iota
+
+	// ECIESHKDF derives the key with HKDF-SHA256 of RFC 5869 without salt
+	ECIESHKDF
+)
+
+// eciesNonceSize and eciesTagSize are the sizes of the AES-GCM nonce and tag
+const (
+	eciesNonceSize = 12
+	eciesTagSize   = 16
+)
+
+// ECIESOptions configures ECIES. A nil *ECIESOptions selects the X9.63 KDF,
+// AES-256-GCM and no shared information.
+type ECIESOptions struct {
+	KDF        ECIESKDF
+	KeySize    int    // AES key size in bytes, 16, 24 or 32, and 32 when zero
+	SharedInfo []byte // optional data both parties bind to the derived key
+}
+
+// aead derives the AES-GCM key from the shared secret z and the encoded
+// ephemeral public key. As in ISO 18033-2 with single hash mode off, the
+// ephemeral public key is part of the KDF input, followed by SharedInfo.
+func (opts *ECIESOptions) aead(z, ephemeral []byte) (cipher.AEAD, error) {
+	if opts == nil {
+		opts = &ECIESOptions{}
+	}
+	keySize := opts.KeySize
+	if keySize == 0 {
+		keySize = 32
+	}
+	if keySize != 16 && keySize != 24 && keySize != 32 {
+		return nil, errors.New("ecc: ECIES key size must be 16, 24 or 32 bytes")
+	}
+	info := append(append([]byte{}, ephemeral...), opts.SharedInfo...)
+
+	var key []byte
+	var err error
+	switch opts.KDF {
+	case ECIESX963:
+		key, err = x963KDF(sha256.New, z, info, keySize)
+	case ECIESHKDF:
+		key, err = hkdfExpand(sha256.New, hkdfExtract(sha256.New, nil, z), info, keySize)
+	default:
+		return nil, errors.New("ecc: unknown ECIES key derivation function")
+	}
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptECIES encrypts a message to the public key with ECIES. An ephemeral
+// key pair is generated on the curve, the x-coordinate of its ECDH shared
+// point with the public key is turned into an AES-GCM key by the KDF of opts,
+// and the ciphertext is
+//
+//	ephemeral public key (SEC 1 uncompressed) || nonce (12 bytes) || ciphertext || tag (16 bytes)
+//
+// The ephemeral scalar and the nonce are read from rand, or crypto/rand when
+// rand is nil.
+func (publicKey *Point) EncryptECIES(rand io.Reader, message []byte, params *ECParams, opts *ECIESOptions) ([]byte, error) {
+	if !params.IsOnCurve(publicKey) || params.hasSmallOrder(publicKey) {
+		return nil, errors.New("ecc: invalid ECIES public key")
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	ephemeral, err := params.GeneratePrivateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	z, err := ephemeral.SharedSecret(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := params.marshalUncompressed(ephemeral.PublicKey)
+	aead, err := opts.aead(z, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, eciesNonceSize)
+	if _, err := io.ReadFull(rand, nonce); err != nil {
+		return nil, err
+	}
+	ciphertext = append(ciphertext, nonce...)
+	return aead.Seal(ciphertext, nonce, message, nil), nil
+}
+
+// DecryptECIES decrypts a ciphertext created by EncryptECIES with the same
+// options. The ephemeral public key must be on the curve of the private key
+// and must not have small order.
+func (key *ECPrivateKey) DecryptECIES(ciphertext []byte, opts *ECIESOptions) ([]byte, error) {
+	ec := key.curve
+	if ec == nil || !ec.IsValidPrivateKey(key) {
+		return nil, errors.New("ecc: invalid ECIES private key")
+	}
+	pointSize := 1 + 2*ec.coordinateSize()
+	if len(ciphertext) < pointSize+eciesNonceSize+eciesTagSize {
+		return nil, errors.New("ecc: ECIES ciphertext is too short")
+	}
+	ephemeral, err := ec.unmarshalUncompressed(ciphertext[:pointSize])
+	if err != nil || ec.hasSmallOrder(ephemeral) {
+		return nil, errors.New("ecc: invalid ECIES ephemeral public key")
+	}
+	z, err := key.SharedSecret(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := opts.aead(z, ciphertext[:pointSize])
+	if err != nil {
+		return nil, err
+	}
+	nonce := ciphertext[pointSize : pointSize+eciesNonceSize]
+	message, err := aead.Open(nil, nonce, ciphertext[pointSize+eciesNonceSize:], nil)
+	if err != nil {
+		return nil, errors.New("ecc: ECIES decryption failed")
+	}
+	return message, nil
+}
@@ -0,0 +1,124 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// The expected ciphertexts were computed with crypto/ecdh, crypto/hkdf and
+// AES-GCM of the standard library from the same ephemeral key and nonce
+func TestECIES_KnownAnswer(t *testing.T) {
+	params := GetSecp256r1Parameters().ECParams
+	d, _ := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	key, err := params.NewPrivateKey(d)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	ephemeral, _ := hex.DecodeString("a6e3c57dd01abe90086538398355dd4c3b17aa873382b0f24d6129493d8aad60")
+	nonce, _ := hex.DecodeString("000102030405060708090a0b")
+
+	vectors := []struct {
+		kdf        ECIESKDF
+		ciphertext string
+	}{
+		{ECIESX963, "04efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf371634a7e72c423213443152c82df94fe0f6851bf894fd91c64b19555346093ff492000102030405060708090a0b3c38bc5c96501dbbc7a1ed29ffd5be2aede4720186a6"},
+		{ECIESHKDF, "04efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf371634a7e72c423213443152c82df94fe0f6851bf894fd91c64b19555346093ff492000102030405060708090a0b30151a90095568847f00bc8bb89686a128c68a9c13c4"},
+	}
+	for _, v := range vectors {
+		opts := &ECIESOptions{KDF: v.kdf, SharedInfo: []byte("ecies test")}
+		rand := bytes.NewReader(append(append([]byte{}, ephemeral...), nonce...))
+		ciphertext, err := key.PublicKey.EncryptECIES(rand, []byte("sample"), params, opts)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if hex.EncodeToString(ciphertext) != v.ciphertext {
+			t.Fatalf("KDF %d: Expected %s, Observed %x", v.kdf, v.ciphertext, ciphertext)
+		}
+		message, err := key.DecryptECIES(ciphertext, opts)
+		if err != nil || string(message) != "sample" {
+			t.Fatalf("KDF %d: Expected sample, Observed %q (%v)", v.kdf, message, err)
+		}
+	}
+}
+
+func TestECIES_RoundTrip(t *testing.T) {
+	message := []byte("The quick brown fox jumps over the lazy dog")
+	for _, curve := range allCurves() {
+		params := curve.Params()
+		key, err := params.GeneratePrivateKey(nil)
+		if err != nil {
+			t.Fatalf("%s: %v", curve.Name(), err)
+		}
+		for _, opts := range []*ECIESOptions{nil, {KDF: ECIESHKDF, KeySize: 16}, {KeySize: 24, SharedInfo: []byte("info")}} {
+			ciphertext, err := key.PublicKey.EncryptECIES(nil, message, params, opts)
+			if err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			if expected := 1 + 2*params.coordinateSize() + 12 + len(message) + 16; len(ciphertext) != expected {
+				t.Fatalf("%s: Expected %d bytes, Observed %d", curve.Name(), expected, len(ciphertext))
+			}
+			decrypted, err := key.DecryptECIES(ciphertext, opts)
+			if err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			if !bytes.Equal(decrypted, message) {
+				t.Fatalf("%s: Expected %x, Observed %x", curve.Name(), message, decrypted)
+			}
+		}
+	}
+}
+
+func TestECIES_Rejections(t *testing.T) {
+	params := GetSecp256r1Parameters().ECParams
+	key, _ := params.GeneratePrivateKey(nil)
+	other, _ := params.GeneratePrivateKey(nil)
+	ciphertext, err := key.PublicKey.EncryptECIES(nil, []byte("secret"), params, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for i := range ciphertext {
+		tampered := append([]byte{}, ciphertext...)
+		tampered[i] ^= 1
+		if _, err := key.DecryptECIES(tampered, nil); err == nil {
+			t.Fatalf("Expected an error for a ciphertext modified at byte %d", i)
+		}
+	}
+	if _, err := key.DecryptECIES(ciphertext[:len(ciphertext)-1], nil); err == nil {
+		t.Fatalf("Expected an error for a truncated ciphertext")
+	}
+	if _, err := key.DecryptECIES(ciphertext[:65+12+15], nil); err == nil {
+		t.Fatalf("Expected an error for a ciphertext without a full tag")
+	}
+	if _, err := other.DecryptECIES(ciphertext, nil); err == nil {
+		t.Fatalf("Expected an error for the wrong private key")
+	}
+	if _, err := key.DecryptECIES(ciphertext, &ECIESOptions{KDF: ECIESHKDF}); err == nil {
+		t.Fatalf("Expected an error for a different KDF")
+	}
+	if _, err := key.DecryptECIES(ciphertext, &ECIESOptions{SharedInfo: []byte("x")}); err == nil {
+		t.Fatalf("Expected an error for different shared information")
+	}
+	if _, err := key.DecryptECIES(ciphertext, &ECIESOptions{KeySize: 20}); err == nil {
+		t.Fatalf("Expected an error for an invalid key size")
+	}
+
+	// An ephemeral point off the curve, with a valid-looking encoding
+	invalid := append([]byte{}, ciphertext...)
+	y := new(big.Int).SetBytes(invalid[33:65])
+	y.Add(y, big.NewInt(1)).Mod(y, params.P)
+	y.FillBytes(invalid[33:65])
+	if _, err := key.DecryptECIES(invalid, nil); err == nil {
+		t.Fatalf("Expected an error for an ephemeral point off the curve")
+	}
+
+	// Public keys off the curve or at infinity
+	if _, err := (&Point{X: big.NewInt(1), Y: big.NewInt(1)}).EncryptECIES(nil, []byte("secret"), params, nil); err == nil {
+		t.Fatalf("Expected an error for a public key off the curve")
+	}
+	if _, err := (&Point{X: big.NewInt(0), Y: big.NewInt(0)}).EncryptECIES(nil, []byte("secret"), params, nil); err == nil {
+		t.Fatalf("Expected an error for the point at infinity")
+	}
+}
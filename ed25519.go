@@ -0,0 +1,83 @@
+package ecc
+
+import (
+	"crypto/sha512"
+	"errors"
+	"io"
+	"math/big"
+)
+
+const (
+	// Ed25519SeedSize is the length of an Ed25519 private key seed
+	Ed25519SeedSize = 32
+
+	// Ed25519PublicKeySize is the length of an encoded Ed25519 public key
+	Ed25519PublicKeySize = 32
+
+	// Ed25519SignatureSize is the length of an Ed25519 signature
+	Ed25519SignatureSize = 64
+)
+
+// GetEdwards25519Parameters returns the parameters of edwards25519, the twisted
+// Edwards curve -x^2 + y^2 = 1 + d*x^2*y^2 over GF(2^255 - 19) used by Ed25519.
+// It is birationally equivalent to Curve25519.
+func GetEdwards25519Parameters() *EdwardsParams {
+	p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+	// d = -121665 / 121666
+	d := new(big.Int).ModInverse(big.NewInt(121666), p)
+	d.Mul(d, big.NewInt(-121665)).Mod(d, p)
+
+	n, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	x, _ := new(big.Int).SetString("15112221349535400772501151409588531511454012693041857206046113283949847762202", 10)
+	y, _ := new(big.Int).SetString("46316835694926478169428394003475163141307993866256225615783033603165251855960", 10)
+
+	return &EdwardsParams{
+		P:         p,
+		A:         new(big.Int).Sub(p, big.NewInt(1)),
+		D:         d,
+		N:         n,
+		BasePoint: &EdwardsPoint{X: x, Y: y},
+		H:         big.NewInt(8),
+	}
+}
+
+var ed25519Scheme = &eddsa{
+	name:  "Ed25519",
+	curve: GetEdwards25519Parameters(),
+	size:  32,
+	hash: func(parts ...[]byte) []byte {
+		h := sha512.New()
+		for _, part := range parts {
+			h.Write(part)
+		}
+		return h.Sum(nil)
+	},
+	dom: func(context []byte) ([]byte, error) {
+		if len(context) > 0 {
+			return nil, errors.New("ecc: Ed25519 does not support contexts")
+		}
+		return nil, nil
+	},
+	clamp: func(s []byte) {
+		s[0] &= 248
+		s[31] &= 127
+		s[31] |= 64
+	},
+}
+
+// NewEd25519PrivateKey derives an Ed25519 private key from a 32-byte seed
+func NewEd25519PrivateKey(seed []byte) (*EdPrivateKey, error) {
+	return ed25519Scheme.newPrivateKey(seed)
+}
+
+// GenerateEd25519PrivateKey creates an Ed25519 private key from a random seed,
+// reading from crypto/rand when rand is nil
+func GenerateEd25519PrivateKey(rand io.Reader) (*EdPrivateKey, error) {
+	return ed25519Scheme.generatePrivateKey(rand)
+}
+
+// NewEd25519PublicKey decodes a 32-byte Ed25519 public key
+func NewEd25519PublicKey(data []byte) (*EdPublicKey, error) {
+	return ed25519Scheme.newPublicKey(data)
+}
@@ -0,0 +1,124 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestEd25519_RFC8032Vectors(t *testing.T) {
+
+	// RFC 8032 section 7.1
+	vectors := []struct {
+		seed, publicKey, message, signature string
+	}{
+		{"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+			"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+			"",
+			"e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b"},
+		{"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+			"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+			"72",
+			"92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"},
+		{"c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+			"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+			"af82",
+			"6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a"},
+		{"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+			"ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+			"ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+			"dc2a4459e7369633a52b1bf277839a00201009a3efbf3ecb69bea2186c26b58909351fc9ac90b3ecfdfbc7c66431e0303dca179c138ac17ad9bef1177331a704"},
+	}
+	for _, vector := range vectors {
+		seed, _ := hex.DecodeString(vector.seed)
+		message, _ := hex.DecodeString(vector.message)
+
+		privateKey, err := NewEd25519PrivateKey(seed)
+		if err != nil {
+			t.Fatalf("Failed to create private key : %v", err)
+		}
+		if hex.EncodeToString(privateKey.PublicKey.Bytes()) != vector.publicKey {
+			t.Fatalf("Expected %s, Observed %x", vector.publicKey, privateKey.PublicKey.Bytes())
+		}
+		signature := privateKey.Sign(message)
+		if hex.EncodeToString(signature) != vector.signature {
+			t.Fatalf("Expected %s, Observed %x", vector.signature, signature)
+		}
+
+		encoded, _ := hex.DecodeString(vector.publicKey)
+		publicKey, err := NewEd25519PublicKey(encoded)
+		if err != nil {
+			t.Fatalf("Failed to decode public key : %v", err)
+		}
+		if !publicKey.Verify(message, signature) || !publicKey.VerifyCofactored(message, signature) {
+			t.Fatalf("RFC 8032 signature does not verify")
+		}
+		if publicKey.Verify(append(message, 0), signature) {
+			t.Fatalf("Signature verified for a different message")
+		}
+	}
+}
+
+func TestEd25519_SignAndVerify(t *testing.T) {
+	privateKey, err := GenerateEd25519PrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate private key : %v", err)
+	}
+	if len(privateKey.Seed()) != Ed25519SeedSize || len(privateKey.PublicKey.Bytes()) != Ed25519PublicKeySize {
+		t.Fatalf("Unexpected key sizes")
+	}
+	restored, _ := NewEd25519PrivateKey(privateKey.Seed())
+	if !bytes.Equal(restored.PublicKey.Bytes(), privateKey.PublicKey.Bytes()) {
+		t.Fatalf("Public key changed after restoring from the seed")
+	}
+
+	signature := privateKey.Sign([]byte("Hello 123"))
+	if len(signature) != Ed25519SignatureSize {
+		t.Fatalf("Unexpected signature length %d", len(signature))
+	}
+	if !privateKey.PublicKey.Verify([]byte("Hello 123"), signature) {
+		t.Fatalf("Signature does not verify")
+	}
+
+	// S + N is a non-canonical encoding of S and must be rejected
+	S := new(big.Int).SetBytes(reverseBytes(signature[32:]))
+	S.Add(S, GetEdwards25519Parameters().N)
+	malleated := append(signature[:32:32], reverseBytes(S.FillBytes(make([]byte, 32)))...)
+	if privateKey.PublicKey.Verify([]byte("Hello 123"), malleated) || privateKey.PublicKey.VerifyCofactored([]byte("Hello 123"), malleated) {
+		t.Fatalf("Signature with S >= N was accepted")
+	}
+
+	if privateKey.PublicKey.Verify([]byte("Hello 123"), signature[:63]) {
+		t.Fatalf("Short signature was accepted")
+	}
+	if _, err := NewEd25519PrivateKey(make([]byte, 31)); err == nil {
+		t.Fatalf("Expected error for short seed")
+	}
+}
+
+func TestEd25519_CofactoredVerification(t *testing.T) {
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	privateKey, _ := NewEd25519PrivateKey(seed)
+	ed := GetEdwards25519Parameters()
+	message := []byte("Hello 123")
+
+	// Sign with R' = [r]B + T for a point T of order 8. [S]B = R' + [k]A only
+	// holds up to T, which the cofactored equation multiplies away.
+	encoded, _ := hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	T, _ := ed.Unmarshal(encoded)
+	r := big.NewInt(987654321)
+	R := ed.Marshal(ed.Add(ed.ScalarBaseMult(r), T))
+	k := ed25519Scheme.hashToScalar(R, privateKey.PublicKey.Bytes(), message)
+	S := new(big.Int).Mul(k, privateKey.s)
+	S.Add(S, r).Mod(S, ed.N)
+	signature := append(R, ed25519Scheme.encodeScalar(S)...)
+
+	if privateKey.PublicKey.Verify(message, signature) {
+		t.Fatalf("Cofactorless verification accepted a small order component")
+	}
+	if !privateKey.PublicKey.VerifyCofactored(message, signature) {
+		t.Fatalf("Cofactored verification rejected a small order component")
+	}
+}
@@ -0,0 +1,80 @@
+package ecc
+
+import (
+	"errors"
+	"io"
+	"math/big"
+)
+
+const (
+	// Ed448SeedSize is the length of an Ed448 private key seed
+	Ed448SeedSize = 57
+
+	// Ed448PublicKeySize is the length of an encoded Ed448 public key
+	Ed448PublicKeySize = 57
+
+	// Ed448SignatureSize is the length of an Ed448 signature
+	Ed448SignatureSize = 114
+)
+
+// GetEdwards448Parameters returns the parameters of edwards448, the untwisted
+// Edwards curve x^2 + y^2 = 1 - 39081*x^2*y^2 over GF(2^448 - 2^224 - 1) used
+// by Ed448. It is 4-isogenous to Curve448.
+func GetEdwards448Parameters() *EdwardsParams {
+	p := new(big.Int).Lsh(big.NewInt(1), 448)
+	p.Sub(p, new(big.Int).Lsh(big.NewInt(1), 224)).Sub(p, big.NewInt(1))
+
+	// n = 2^446 - 13818066809895115352007386748515426880336692474882178609894547503885
+	n, _ := new(big.Int).SetString("13818066809895115352007386748515426880336692474882178609894547503885", 10)
+	n.Sub(new(big.Int).Lsh(big.NewInt(1), 446), n)
+	x, _ := new(big.Int).SetString("224580040295924300187604334099896036246789641632564134246125461686950415467406032909029192869357953282578032075146446173674602635247710", 10)
+	y, _ := new(big.Int).SetString("298819210078481492676017930443930673437544040154080242095928241372331506189835876003536878655418784733982303233503462500531545062832660", 10)
+
+	return &EdwardsParams{
+		P:         p,
+		A:         big.NewInt(1),
+		D:         new(big.Int).Sub(p, big.NewInt(39081)),
+		N:         n,
+		BasePoint: &EdwardsPoint{X: x, Y: y},
+		H:         big.NewInt(4),
+	}
+}
+
+var ed448Scheme = &eddsa{
+	name:  "Ed448",
+	curve: GetEdwards448Parameters(),
+	size:  57,
+	hash: func(parts ...[]byte) []byte {
+		return shake256(114, parts...)
+	},
+
+	// dom4(0, context) of RFC 8032 section 5.2
+	dom: func(context []byte) ([]byte, error) {
+		if len(context) > 255 {
+			return nil, errors.New("ecc: Ed448 context is longer than 255 bytes")
+		}
+		dom := append([]byte("SigEd448"), 0, byte(len(context)))
+		return append(dom, context...), nil
+	},
+	clamp: func(s []byte) {
+		s[0] &= 252
+		s[55] |= 128
+		s[56] = 0
+	},
+}
+
+// NewEd448PrivateKey derives an Ed448 private key from a 57-byte seed
+func NewEd448PrivateKey(seed []byte) (*EdPrivateKey, error) {
+	return ed448Scheme.newPrivateKey(seed)
+}
+
+// GenerateEd448PrivateKey creates an Ed448 private key from a random seed,
+// reading from crypto/rand when rand is nil
+func GenerateEd448PrivateKey(rand io.Reader) (*EdPrivateKey, error) {
+	return ed448Scheme.generatePrivateKey(rand)
+}
+
+// NewEd448PublicKey decodes a 57-byte Ed448 public key
+func NewEd448PublicKey(data []byte) (*EdPublicKey, error) {
+	return ed448Scheme.newPublicKey(data)
+}
@@ -0,0 +1,119 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestEd448_Parameters(t *testing.T) {
+	ed := GetEdwards448Parameters()
+	if !ed.IsOnCurve(ed.BasePoint) {
+		t.Fatalf("Base point is not on edwards448")
+	}
+	if !ed.ScalarBaseMult(ed.N).Equal(ed.Identity()) {
+		t.Fatalf("Base point does not have order N")
+	}
+	P := ed.ScalarBaseMult(big.NewInt(123456789))
+	decoded, err := ed.Unmarshal(ed.Marshal(P))
+	if err != nil || !decoded.Equal(P) {
+		t.Fatalf("Point changed after round trip : %v", err)
+	}
+}
+
+func TestEd448_RFC8032Vectors(t *testing.T) {
+
+	// RFC 8032 section 7.4
+	vectors := []struct {
+		seed, publicKey, message, context, signature string
+	}{
+		{"6c82a562cb808d10d632be89c8513ebf6c929f34ddfa8c9f63c9960ef6e348a3528c8a3fcc2f044e39a3fc5b94492f8f032e7549a20098f95b",
+			"5fd7449b59b461fd2ce787ec616ad46a1da1342485a70e1f8a0ea75d80e96778edf124769b46c7061bd6783df1e50f6cd1fa1abeafe8256180",
+			"", "",
+			"533a37f6bbe457251f023c0d88f976ae2dfb504a843e34d2074fd823d41a591f2b233f034f628281f2fd7a22ddd47d7828c59bd0a21bfd3980ff0d2028d4b18a9df63e006c5d1c2d345b925d8dc00b4104852db99ac5c7cdda8530a113a0f4dbb61149f05a7363268c71d95808ff2e652600"},
+		{"c4eab05d357007c632f3dbb48489924d552b08fe0c353a0d4a1f00acda2c463afbea67c5e8d2877c5e3bc397a659949ef8021e954e0a12274e",
+			"43ba28f430cdff456ae531545f7ecd0ac834a55d9358c0372bfa0c6c6798c0866aea01eb00742802b8438ea4cb82169c235160627b4c3a9480",
+			"03", "",
+			"26b8f91727bd62897af15e41eb43c377efb9c610d48f2335cb0bd0087810f4352541b143c4b981b7e18f62de8ccdf633fc1bf037ab7cd779805e0dbcc0aae1cbcee1afb2e027df36bc04dcecbf154336c19f0af7e0a6472905e799f1953d2a0ff3348ab21aa4adafd1d234441cf807c03a00"},
+		{"c4eab05d357007c632f3dbb48489924d552b08fe0c353a0d4a1f00acda2c463afbea67c5e8d2877c5e3bc397a659949ef8021e954e0a12274e",
+			"43ba28f430cdff456ae531545f7ecd0ac834a55d9358c0372bfa0c6c6798c0866aea01eb00742802b8438ea4cb82169c235160627b4c3a9480",
+			"03", "666f6f",
+			"d4f8f6131770dd46f40867d6fd5d5055de43541f8c5e35abbcd001b32a89f7d2151f7647f11d8ca2ae279fb842d607217fce6e042f6815ea000c85741de5c8da1144a6a1aba7f96de42505d7a7298524fda538fccbbb754f578c1cad10d54d0d5428407e85dcbc98a49155c13764e66c3c00"},
+		{"cd23d24f714274e744343237b93290f511f6425f98e64459ff203e8985083ffdf60500553abc0e05cd02184bdb89c4ccd67e187951267eb328",
+			"dcea9e78f35a1bf3499a831b10b86c90aac01cd84b67a0109b55a36e9328b1e365fce161d71ce7131a543ea4cb5f7e9f1d8b00696447001400",
+			"0c3e544074ec63b0265e0c", "",
+			"1f0a8888ce25e8d458a21130879b840a9089d999aaba039eaf3e3afa090a09d389dba82c4ff2ae8ac5cdfb7c55e94d5d961a29fe0109941e00b8dbdeea6d3b051068df7254c0cdc129cbe62db2dc957dbb47b51fd3f213fb8698f064774250a5028961c9bf8ffd973fe5d5c206492b140e00"},
+		{"258cdd4ada32ed9c9ff54e63756ae582fb8fab2ac721f2c8e676a72768513d939f63dddb55609133f29adf86ec9929dccb52c1c5fd2ff7e21b",
+			"3ba16da0c6f2cc1f30187740756f5e798d6bc5fc015d7c63cc9510ee3fd44adc24d8e968b6e46e6f94d19b945361726bd75e149ef09817f580",
+			"64a65f3cdedcdd66811e2915", "",
+			"7eeeab7c4e50fb799b418ee5e3197ff6bf15d43a14c34389b59dd1a7b1b85b4ae90438aca634bea45e3a2695f1270f07fdcdf7c62b8efeaf00b45c2c96ba457eb1a8bf075a3db28e5c24f6b923ed4ad747c3c9e03c7079efb87cb110d3a99861e72003cbae6d6b8b827e4e6c143064ff3c00"},
+	}
+	for _, vector := range vectors {
+		seed, _ := hex.DecodeString(vector.seed)
+		message, _ := hex.DecodeString(vector.message)
+		context, _ := hex.DecodeString(vector.context)
+
+		privateKey, err := NewEd448PrivateKey(seed)
+		if err != nil {
+			t.Fatalf("Failed to create private key : %v", err)
+		}
+		if hex.EncodeToString(privateKey.PublicKey.Bytes()) != vector.publicKey {
+			t.Fatalf("Expected %s, Observed %x", vector.publicKey, privateKey.PublicKey.Bytes())
+		}
+		signature, err := privateKey.SignWithContext(message, context)
+		if err != nil {
+			t.Fatalf("Failed to sign : %v", err)
+		}
+		if hex.EncodeToString(signature) != vector.signature {
+			t.Fatalf("Expected %s, Observed %x", vector.signature, signature)
+		}
+
+		encoded, _ := hex.DecodeString(vector.publicKey)
+		publicKey, err := NewEd448PublicKey(encoded)
+		if err != nil {
+			t.Fatalf("Failed to decode public key : %v", err)
+		}
+		if !publicKey.VerifyWithContext(message, signature, context, false) || !publicKey.VerifyWithContext(message, signature, context, true) {
+			t.Fatalf("RFC 8032 signature does not verify")
+		}
+		if publicKey.VerifyWithContext(message, signature, []byte("bar"), false) {
+			t.Fatalf("Signature verified with a different context")
+		}
+	}
+}
+
+func TestEd448_SignAndVerify(t *testing.T) {
+	privateKey, err := GenerateEd448PrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate private key : %v", err)
+	}
+	if len(privateKey.Seed()) != Ed448SeedSize || len(privateKey.PublicKey.Bytes()) != Ed448PublicKeySize {
+		t.Fatalf("Unexpected key sizes")
+	}
+	restored, _ := NewEd448PrivateKey(privateKey.Seed())
+	if !bytes.Equal(restored.PublicKey.Bytes(), privateKey.PublicKey.Bytes()) {
+		t.Fatalf("Public key changed after restoring from the seed")
+	}
+
+	signature := privateKey.Sign([]byte("Hello 123"))
+	if len(signature) != Ed448SignatureSize {
+		t.Fatalf("Unexpected signature length %d", len(signature))
+	}
+	if !privateKey.PublicKey.Verify([]byte("Hello 123"), signature) || !privateKey.PublicKey.VerifyCofactored([]byte("Hello 123"), signature) {
+		t.Fatalf("Signature does not verify")
+	}
+	if privateKey.PublicKey.Verify([]byte("Hello 124"), signature) {
+		t.Fatalf("Signature verified for a different message")
+	}
+	if _, err := privateKey.SignWithContext([]byte("Hello 123"), make([]byte, 256)); err == nil {
+		t.Fatalf("Expected error for a context longer than 255 bytes")
+	}
+
+	// Ed25519 signatures have no context
+	ed25519Key, _ := GenerateEd25519PrivateKey(rand.Reader)
+	if _, err := ed25519Key.SignWithContext([]byte("Hello 123"), []byte("foo")); err == nil {
+		t.Fatalf("Expected error for an Ed25519 context")
+	}
+}
@@ -0,0 +1,177 @@
+package ecc
+
+import (
+	cryptorand "crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// eddsa describes an EdDSA signature scheme of RFC 8032 on an Edwards curve
+type eddsa struct {
+	name  string
+	curve *EdwardsParams
+	size  int // length of seeds, encoded points and encoded scalars in bytes
+
+	// hash computes the 2*size bytes hash H of the concatenated parts
+	hash func(parts ...[]byte) []byte
+
+	// dom returns the prefix hashed before R, A and the message, which binds
+	// signatures to the scheme and to the context string
+	dom func(context []byte) ([]byte, error)
+
+	// clamp sets and clears the fixed bits of the first half of the hashed seed
+	clamp func(s []byte)
+}
+
+// EdPrivateKey is an EdDSA private key. It is derived from a random seed as
+// described in RFC 8032 section 5.1.5.
+type EdPrivateKey struct {
+	seed      []byte
+	s         *big.Int // clamped secret scalar
+	prefix    []byte   // second half of the hashed seed, used to derive nonces
+	PublicKey *EdPublicKey
+}
+
+// EdPublicKey is an EdDSA public key
+type EdPublicKey struct {
+	A       *EdwardsPoint
+	encoded []byte
+	scheme  *eddsa
+}
+
+// newPrivateKey expands a seed into the secret scalar, the nonce prefix and
+// the public key
+func (scheme *eddsa) newPrivateKey(seed []byte) (*EdPrivateKey, error) {
+	if len(seed) != scheme.size {
+		return nil, errors.New("ecc: " + scheme.name + " seed has the wrong length")
+	}
+	h := scheme.hash(seed)
+	s := append([]byte(nil), h[:scheme.size]...)
+	scheme.clamp(s)
+
+	key := &EdPrivateKey{
+		seed:   append([]byte(nil), seed...),
+		s:      new(big.Int).SetBytes(reverseBytes(s)),
+		prefix: h[scheme.size:],
+	}
+	A := scheme.curve.ScalarBaseMult(key.s)
+	key.PublicKey = &EdPublicKey{A: A, encoded: scheme.curve.Marshal(A), scheme: scheme}
+	return key, nil
+}
+
+// generatePrivateKey creates a private key from a random seed, reading from
+// crypto/rand when rand is nil
+func (scheme *eddsa) generatePrivateKey(rand io.Reader) (*EdPrivateKey, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	seed := make([]byte, scheme.size)
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, err
+	}
+	return scheme.newPrivateKey(seed)
+}
+
+// newPublicKey decodes an encoded public key
+func (scheme *eddsa) newPublicKey(data []byte) (*EdPublicKey, error) {
+	A, err := scheme.curve.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &EdPublicKey{A: A, encoded: append([]byte(nil), data...), scheme: scheme}, nil
+}
+
+// hashToScalar returns H(parts) as a little-endian integer modulo N
+func (scheme *eddsa) hashToScalar(parts ...[]byte) *big.Int {
+	k := new(big.Int).SetBytes(reverseBytes(scheme.hash(parts...)))
+	return k.Mod(k, scheme.curve.N)
+}
+
+// encodeScalar encodes a scalar in little-endian
+func (scheme *eddsa) encodeScalar(k *big.Int) []byte {
+	return reverseBytes(k.FillBytes(make([]byte, scheme.size)))
+}
+
+// Seed returns the seed the private key was derived from
+func (key *EdPrivateKey) Seed() []byte {
+	return append([]byte(nil), key.seed...)
+}
+
+// Sign creates the deterministic signature R || S of RFC 8032 section 5.1.6
+// with an empty context
+func (key *EdPrivateKey) Sign(message []byte) []byte {
+	signature, _ := key.SignWithContext(message, nil)
+	return signature
+}
+
+// SignWithContext creates a signature bound to a context string of at most
+// 255 bytes. Ed25519 does not support contexts and only accepts an empty one.
+func (key *EdPrivateKey) SignWithContext(message, context []byte) ([]byte, error) {
+	scheme := key.PublicKey.scheme
+	dom, err := scheme.dom(context)
+	if err != nil {
+		return nil, err
+	}
+
+	r := scheme.hashToScalar(dom, key.prefix, message)
+	R := scheme.curve.Marshal(scheme.curve.ScalarBaseMult(r))
+	k := scheme.hashToScalar(dom, R, key.PublicKey.encoded, message)
+
+	// S = r + k*s mod N
+	S := new(big.Int).Mul(k, key.s)
+	S.Add(S, r).Mod(S, scheme.curve.N)
+
+	return append(R, scheme.encodeScalar(S)...), nil
+}
+
+// Bytes returns the encoding of the public key
+func (publicKey *EdPublicKey) Bytes() []byte {
+	return append([]byte(nil), publicKey.encoded...)
+}
+
+// Verify checks a signature with the cofactorless equation [S]B = R + [k]A,
+// which is the behaviour of most other implementations
+func (publicKey *EdPublicKey) Verify(message, signature []byte) bool {
+	return publicKey.VerifyWithContext(message, signature, nil, false)
+}
+
+// VerifyCofactored checks a signature with the equation
+// [h][S]B = [h]R + [h][k]A of RFC 8032 section 5.1.7. It additionally accepts
+// signatures whose R or A have a component of small order, which makes it the
+// mode of choice for batch and threshold settings that need consistent results.
+func (publicKey *EdPublicKey) VerifyCofactored(message, signature []byte) bool {
+	return publicKey.VerifyWithContext(message, signature, nil, true)
+}
+
+// VerifyWithContext checks a signature created by SignWithContext, using the
+// cofactored equation when cofactored is true
+func (publicKey *EdPublicKey) VerifyWithContext(message, signature, context []byte, cofactored bool) bool {
+	scheme := publicKey.scheme
+	curve := scheme.curve
+	if len(signature) != 2*scheme.size {
+		return false
+	}
+	dom, err := scheme.dom(context)
+	if err != nil {
+		return false
+	}
+
+	R, err := curve.Unmarshal(signature[:scheme.size])
+	if err != nil {
+		return false
+	}
+	S := new(big.Int).SetBytes(reverseBytes(signature[scheme.size:]))
+	if S.Cmp(curve.N) >= 0 {
+		return false
+	}
+	k := scheme.hashToScalar(dom, signature[:scheme.size], publicKey.encoded, message)
+
+	// [S]B - [k]A - R must be the identity, or of small order when cofactored
+	Q := curve.Add(curve.ScalarBaseMult(S), curve.ScalarMult(k, curve.Negate(publicKey.A)))
+	Q = curve.Add(Q, curve.Negate(R))
+	if cofactored {
+		Q = curve.ScalarMult(curve.H, Q)
+	}
+	return Q.Equal(curve.Identity())
+}
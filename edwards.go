@@ -0,0 +1,201 @@
+package ecc
+
+import (
+	"errors"
+	"math/big"
+)
+
+// EdwardsPoint represents a point on a twisted Edwards curve in affine
+// coordinates. Unlike Point, the identity has a regular representation (0, 1).
+type EdwardsPoint struct {
+	X, Y *big.Int
+}
+
+// EdwardsParams represents the parameters of the twisted Edwards curve
+// a*x^2 + y^2 = 1 + d*x^2*y^2 (mod p). With a square and d a non-square, the
+// addition formulas are complete: the same formula adds, doubles and handles
+// the identity for every pair of points.
+type EdwardsParams struct {
+	P, A, D, N *big.Int
+	BasePoint  *EdwardsPoint
+	H          *big.Int // Cofactor of the curve
+}
+
+// extendedPoint is a point in the extended coordinates (X : Y : Z : T) of
+// Hisil, Wong, Carter and Dawson, with x = X/Z, y = Y/Z and x*y = T/Z
+type extendedPoint struct {
+	X, Y, Z, T *big.Int
+}
+
+// Identity returns the neutral element (0, 1)
+func (ed *EdwardsParams) Identity() *EdwardsPoint {
+	return &EdwardsPoint{X: big.NewInt(0), Y: big.NewInt(1)}
+}
+
+// IsOnCurve reports whether P satisfies a*x^2 + y^2 = 1 + d*x^2*y^2 (mod p)
+// with both coordinates reduced modulo p
+func (ed *EdwardsParams) IsOnCurve(P *EdwardsPoint) bool {
+	if P == nil || P.X == nil || P.Y == nil {
+		return false
+	}
+	if P.X.Sign() < 0 || P.X.Cmp(ed.P) >= 0 || P.Y.Sign() < 0 || P.Y.Cmp(ed.P) >= 0 {
+		return false
+	}
+
+	x2 := new(big.Int).Mul(P.X, P.X)
+	y2 := new(big.Int).Mul(P.Y, P.Y)
+
+	// a*x^2 + y^2
+	lhs := new(big.Int).Mul(ed.A, x2)
+	lhs.Add(lhs, y2).Mod(lhs, ed.P)
+
+	// 1 + d*x^2*y^2
+	rhs := new(big.Int).Mul(x2, y2)
+	rhs.Mul(rhs, ed.D).Add(rhs, big.NewInt(1)).Mod(rhs, ed.P)
+
+	return lhs.Cmp(rhs) == 0
+}
+
+// Equal reports whether P and Q are the same point
+func (P *EdwardsPoint) Equal(Q *EdwardsPoint) bool {
+	return P.X.Cmp(Q.X) == 0 && P.Y.Cmp(Q.Y) == 0
+}
+
+// Add returns P + Q
+func (ed *EdwardsParams) Add(P, Q *EdwardsPoint) *EdwardsPoint {
+	return ed.toAffine(ed.addExtended(ed.toExtended(P), ed.toExtended(Q)))
+}
+
+// Negate returns -P = (-x, y)
+func (ed *EdwardsParams) Negate(P *EdwardsPoint) *EdwardsPoint {
+	x := new(big.Int).Neg(P.X)
+	return &EdwardsPoint{X: x.Mod(x, ed.P), Y: new(big.Int).Set(P.Y)}
+}
+
+// ScalarMult returns k*P. The scalar is not reduced modulo N, so points
+// outside the subgroup generated by the base point are multiplied correctly.
+func (ed *EdwardsParams) ScalarMult(k *big.Int, P *EdwardsPoint) *EdwardsPoint {
+	if k.Sign() < 0 {
+		return ed.ScalarMult(new(big.Int).Neg(k), ed.Negate(P))
+	}
+
+	Q := ed.toExtended(P)
+	R := ed.toExtended(ed.Identity())
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		R = ed.addExtended(R, R)
+		if k.Bit(i) == 1 {
+			R = ed.addExtended(R, Q)
+		}
+	}
+	return ed.toAffine(R)
+}
+
+// ScalarBaseMult returns k*B for the base point B
+func (ed *EdwardsParams) ScalarBaseMult(k *big.Int) *EdwardsPoint {
+	return ed.ScalarMult(k, ed.BasePoint)
+}
+
+// encodedSize returns the length of an encoded point, which has room for y and
+// the sign bit of x
+func (ed *EdwardsParams) encodedSize() int {
+	return (ed.P.BitLen() + 8) / 8
+}
+
+// Marshal encodes P as in RFC 8032 section 5.1.2: y in little-endian with the
+// least significant bit of x in the most significant bit of the last byte
+func (ed *EdwardsParams) Marshal(P *EdwardsPoint) []byte {
+	encoded := reverseBytes(P.Y.FillBytes(make([]byte, ed.encodedSize())))
+	encoded[len(encoded)-1] |= byte(P.X.Bit(0)) << 7
+	return encoded
+}
+
+// Unmarshal decodes a point encoded by Marshal, recovering x from the curve
+// equation as in RFC 8032 section 5.1.3. Encodings with y >= p, points with
+// x = 0 and the sign bit set, and values that are not on the curve are rejected.
+func (ed *EdwardsParams) Unmarshal(data []byte) (*EdwardsPoint, error) {
+	if len(data) != ed.encodedSize() {
+		return nil, errors.New("ecc: invalid Edwards point length")
+	}
+	b := reverseBytes(data)
+	sign := uint(b[0] >> 7)
+	b[0] &= 0x7f
+
+	y := new(big.Int).SetBytes(b)
+	if y.Cmp(ed.P) >= 0 {
+		return nil, errors.New("ecc: Edwards point is not canonically encoded")
+	}
+
+	// x^2 = (y^2 - 1) / (d*y^2 - a)
+	y2 := new(big.Int).Mul(y, y)
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	v := new(big.Int).Mul(ed.D, y2)
+	v.Sub(v, ed.A).Mod(v, ed.P)
+	if v.Sign() == 0 {
+		return nil, errors.New("ecc: Edwards point is not on the curve")
+	}
+	x2 := new(big.Int).ModInverse(v, ed.P)
+	x2.Mul(x2, u).Mod(x2, ed.P)
+
+	x := new(big.Int).ModSqrt(x2, ed.P)
+	if x == nil {
+		return nil, errors.New("ecc: Edwards point is not on the curve")
+	}
+	if x.Sign() == 0 && sign == 1 {
+		return nil, errors.New("ecc: Edwards point is not canonically encoded")
+	}
+	if x.Bit(0) != sign {
+		x.Sub(ed.P, x)
+	}
+	return &EdwardsPoint{X: x, Y: y}, nil
+}
+
+// toExtended converts an affine point to extended coordinates with Z = 1
+func (ed *EdwardsParams) toExtended(P *EdwardsPoint) *extendedPoint {
+	T := new(big.Int).Mul(P.X, P.Y)
+	return &extendedPoint{
+		X: new(big.Int).Set(P.X),
+		Y: new(big.Int).Set(P.Y),
+		Z: big.NewInt(1),
+		T: T.Mod(T, ed.P),
+	}
+}
+
+// toAffine converts a point in extended coordinates back to (X/Z, Y/Z)
+func (ed *EdwardsParams) toAffine(P *extendedPoint) *EdwardsPoint {
+	zInv := new(big.Int).ModInverse(P.Z, ed.P)
+	x := new(big.Int).Mul(P.X, zInv)
+	y := new(big.Int).Mul(P.Y, zInv)
+	return &EdwardsPoint{X: x.Mod(x, ed.P), Y: y.Mod(y, ed.P)}
+}
+
+// addExtended adds two points with the unified formula "add-2008-hwcd", which
+// is also used for doubling
+func (ed *EdwardsParams) addExtended(P, Q *extendedPoint) *extendedPoint {
+	p := ed.P
+	mul := func(x, y *big.Int) *big.Int {
+		z := new(big.Int).Mul(x, y)
+		return z.Mod(z, p)
+	}
+
+	A := mul(P.X, Q.X)
+	B := mul(P.Y, Q.Y)
+	C := mul(mul(P.T, Q.T), ed.D)
+	D := mul(P.Z, Q.Z)
+
+	// E = (X1 + Y1)(X2 + Y2) - A - B
+	E := mul(new(big.Int).Add(P.X, P.Y), new(big.Int).Add(Q.X, Q.Y))
+	E.Sub(E, A).Sub(E, B)
+	F := new(big.Int).Sub(D, C)
+	G := new(big.Int).Add(D, C)
+
+	// H = B - a*A
+	H := new(big.Int).Mul(ed.A, A)
+	H.Sub(B, H)
+
+	return &extendedPoint{
+		X: mul(E, F),
+		Y: mul(G, H),
+		Z: mul(F, G),
+		T: mul(E, H),
+	}
+}
@@ -0,0 +1,117 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestEdwards_BasePoint(t *testing.T) {
+	ed := GetEdwards25519Parameters()
+	if !ed.IsOnCurve(ed.BasePoint) {
+		t.Fatalf("Base point is not on edwards25519")
+	}
+	if !ed.ScalarBaseMult(ed.N).Equal(ed.Identity()) {
+		t.Fatalf("Base point does not have order N")
+	}
+
+	// RFC 8032 section 5.1: B is encoded as 0x58 followed by 0x66 bytes
+	expected := "5866666666666666666666666666666666666666666666666666666666666666"
+	if encoded := ed.Marshal(ed.BasePoint); hex.EncodeToString(encoded) != expected {
+		t.Fatalf("Unexpected base point encoding. Expected %s, Observed %x", expected, encoded)
+	}
+}
+
+func TestEdwards_UnifiedAddition(t *testing.T) {
+	ed := GetEdwards25519Parameters()
+	B := ed.BasePoint
+
+	// Doubling, adding the identity and adding the negation use the same formula
+	if !ed.Add(B, B).Equal(ed.ScalarBaseMult(big.NewInt(2))) {
+		t.Fatalf("B + B != 2B")
+	}
+	if !ed.Add(B, ed.Identity()).Equal(B) {
+		t.Fatalf("B + O != B")
+	}
+	if !ed.Add(B, ed.Negate(B)).Equal(ed.Identity()) {
+		t.Fatalf("B + (-B) != O")
+	}
+	P := ed.ScalarBaseMult(big.NewInt(12345))
+	Q := ed.ScalarBaseMult(big.NewInt(67890))
+	if !ed.Add(P, Q).Equal(ed.ScalarBaseMult(big.NewInt(12345 + 67890))) {
+		t.Fatalf("12345B + 67890B != 80235B")
+	}
+	if !ed.ScalarMult(big.NewInt(-5), B).Equal(ed.Negate(ed.ScalarBaseMult(big.NewInt(5)))) {
+		t.Fatalf("-5B != -(5B)")
+	}
+}
+
+func TestEdwards_MatchesX25519(t *testing.T) {
+	ed := GetEdwards25519Parameters()
+	scalar, _ := hex.DecodeString("a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4")
+	expected, err := X25519(scalar, X25519BasePoint)
+	if err != nil {
+		t.Fatalf("X25519 failed : %v", err)
+	}
+
+	// The birational map to Curve25519 is u = (1 + y) / (1 - y)
+	clamped := append([]byte(nil), scalar...)
+	curve25519.clamp(clamped)
+	P := ed.ScalarBaseMult(new(big.Int).SetBytes(reverseBytes(clamped)))
+	u := new(big.Int).Sub(big.NewInt(1), P.Y)
+	u.ModInverse(u.Mod(u, ed.P), ed.P)
+	u.Mul(u, new(big.Int).Add(big.NewInt(1), P.Y)).Mod(u, ed.P)
+
+	if observed := curve25519.encodeU(u); !bytes.Equal(observed, expected) {
+		t.Fatalf("Expected %x, Observed %x", expected, observed)
+	}
+}
+
+func TestEdwards_Encoding(t *testing.T) {
+	ed := GetEdwards25519Parameters()
+	for _, k := range []int64{1, 2, 3, 1000, 123456789} {
+		P := ed.ScalarBaseMult(big.NewInt(k))
+		decoded, err := ed.Unmarshal(ed.Marshal(P))
+		if err != nil {
+			t.Fatalf("Failed to decode %dB : %v", k, err)
+		}
+		if !decoded.Equal(P) {
+			t.Fatalf("%dB changed after round trip", k)
+		}
+	}
+
+	identity, err := ed.Unmarshal(ed.Marshal(ed.Identity()))
+	if err != nil || !identity.Equal(ed.Identity()) {
+		t.Fatalf("Failed to round trip the identity : %v", err)
+	}
+
+	invalid := []string{
+		// y = p
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		// x = 0 with the sign bit set
+		"0100000000000000000000000000000000000000000000000000000000000080",
+		// y = 2 is not the y-coordinate of a point
+		"0200000000000000000000000000000000000000000000000000000000000000",
+		// short
+		"5866666666666666666666666666666666666666666666666666666666666666"[:62],
+	}
+	for _, data := range invalid {
+		encoded, _ := hex.DecodeString(data)
+		if _, err := ed.Unmarshal(encoded); err == nil {
+			t.Fatalf("Expected error when decoding %s", data)
+		}
+	}
+}
+
+func TestEdwards_SmallOrderPoint(t *testing.T) {
+	ed := GetEdwards25519Parameters()
+	encoded, _ := hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	T, err := ed.Unmarshal(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode point of order 8 : %v", err)
+	}
+	if !ed.ScalarMult(ed.H, T).Equal(ed.Identity()) || ed.ScalarMult(big.NewInt(4), T).Equal(ed.Identity()) {
+		t.Fatalf("Point does not have order 8")
+	}
+}
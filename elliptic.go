@@ -0,0 +1,80 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// ellipticCurve adapts ECParams to the crypto/elliptic.Curve interface
+type ellipticCurve struct {
+	ec     *ECParams
+	params *elliptic.CurveParams
+}
+
+// EllipticCurve returns the curve as a crypto/elliptic.Curve backed by the
+// arithmetic of this package, so that curves missing from the standard library
+// such as secp256k1 and the Brainpool curves can be used with code written for
+// that interface. The point at infinity is (0, 0) as in crypto/elliptic.
+//
+// The returned CurveParams carry no coefficient a and describe a curve with
+// a = -3, so callers must use the methods of the returned curve rather than
+// the arithmetic of CurveParams itself.
+func (ec *ECParams) EllipticCurve() elliptic.Curve {
+	params := &elliptic.CurveParams{
+		P:       new(big.Int).Set(ec.P),
+		N:       new(big.Int).Set(ec.N),
+		B:       new(big.Int).Set(ec.B),
+		Gx:      new(big.Int).Set(ec.BasePoint.X),
+		Gy:      new(big.Int).Set(ec.BasePoint.Y),
+		BitSize: ec.BitSize(),
+	}
+	if curve := lookupCurve(ec); curve != nil {
+		params.Name = curve.Name
+	}
+	return &ellipticCurve{ec: ec, params: params}
+}
+
+func (curve *ellipticCurve) Params() *elliptic.CurveParams {
+	return curve.params
+}
+
+func (curve *ellipticCurve) IsOnCurve(x, y *big.Int) bool {
+	return curve.ec.IsOnCurve(&Point{X: x, Y: y})
+}
+
+func (curve *ellipticCurve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	P := curve.point("Add", x1, y1)
+	Q := curve.point("Add", x2, y2)
+	R := addPoints(P, Q, curve.ec)
+	return R.X, R.Y
+}
+
+func (curve *ellipticCurve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	R := doublePoint(curve.point("Double", x1, y1), curve.ec)
+	return R.X, R.Y
+}
+
+func (curve *ellipticCurve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	P := curve.point("ScalarMult", x1, y1)
+	R := ScalarMult(new(big.Int).SetBytes(k), P, curve.ec)
+	return R.X, R.Y
+}
+
+func (curve *ellipticCurve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	R := ScalarMult(new(big.Int).SetBytes(k), curve.ec.BasePoint, curve.ec)
+	return R.X, R.Y
+}
+
+// point converts the coordinates to a Point. Like the curves of crypto/elliptic
+// the adapter panics for points that are neither on the curve nor the point at
+// infinity.
+func (curve *ellipticCurve) point(method string, x, y *big.Int) *Point {
+	P := &Point{X: x, Y: y}
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return P
+	}
+	if !curve.ec.IsOnCurve(P) {
+		panic("ecc: " + method + " was called on an invalid point")
+	}
+	return P
+}
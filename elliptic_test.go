@@ -0,0 +1,100 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func TestEllipticCurve_MatchesStandardLibrary(t *testing.T) {
+	for _, curves := range []struct {
+		ours, std elliptic.Curve
+	}{
+		{GetSecp256r1Parameters().EllipticCurve(), elliptic.P256()},
+		{GetSecp384r1Parameters().EllipticCurve(), elliptic.P384()},
+	} {
+		if curves.ours.Params().Name != curves.std.Params().Name || curves.ours.Params().BitSize != curves.std.Params().BitSize {
+			t.Fatalf("Expected params %+v, Observed %+v", curves.std.Params(), curves.ours.Params())
+		}
+		for i := 0; i < 5; i++ {
+			k1 := make([]byte, 32)
+			k2 := make([]byte, 32)
+			rand.Read(k1)
+			rand.Read(k2)
+
+			x1, y1 := curves.ours.ScalarBaseMult(k1)
+			ex1, ey1 := curves.std.ScalarBaseMult(k1)
+			if x1.Cmp(ex1) != 0 || y1.Cmp(ey1) != 0 {
+				t.Fatalf("ScalarBaseMult mismatch. Expected %x, Observed %x", ex1, x1)
+			}
+			x2, y2 := curves.ours.ScalarMult(x1, y1, k2)
+			ex2, ey2 := curves.std.ScalarMult(ex1, ey1, k2)
+			if x2.Cmp(ex2) != 0 || y2.Cmp(ey2) != 0 {
+				t.Fatalf("ScalarMult mismatch. Expected %x, Observed %x", ex2, x2)
+			}
+			x3, y3 := curves.ours.Add(x1, y1, x2, y2)
+			ex3, ey3 := curves.std.Add(ex1, ey1, ex2, ey2)
+			if x3.Cmp(ex3) != 0 || y3.Cmp(ey3) != 0 {
+				t.Fatalf("Add mismatch. Expected %x, Observed %x", ex3, x3)
+			}
+			x4, y4 := curves.ours.Double(x3, y3)
+			ex4, ey4 := curves.std.Double(ex3, ey3)
+			if x4.Cmp(ex4) != 0 || y4.Cmp(ey4) != 0 {
+				t.Fatalf("Double mismatch. Expected %x, Observed %x", ex4, x4)
+			}
+			if !curves.ours.IsOnCurve(x4, y4) {
+				t.Fatalf("Result is not on the curve")
+			}
+		}
+	}
+}
+
+func TestEllipticCurve_Infinity(t *testing.T) {
+	curve := GetSecp256k1Parametes().EllipticCurve()
+	gx, gy := curve.Params().Gx, curve.Params().Gy
+
+	// G + (-G) = O and O + G = G
+	x, y := curve.Add(gx, gy, gx, new(big.Int).Sub(curve.Params().P, gy))
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Fatalf("Expected the point at infinity, Observed (%x, %x)", x, y)
+	}
+	x, y = curve.Add(x, y, gx, gy)
+	if x.Cmp(gx) != 0 || y.Cmp(gy) != 0 {
+		t.Fatalf("Expected G, Observed (%x, %x)", x, y)
+	}
+	x, y = curve.ScalarBaseMult(curve.Params().N.Bytes())
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Fatalf("Expected n * G to be the point at infinity")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected panic for a point off the curve")
+		}
+	}()
+	curve.Double(big.NewInt(1), big.NewInt(1))
+}
+
+func TestEllipticCurve_ECDSA(t *testing.T) {
+
+	// crypto/ecdsa signs with secp256k1 through the adapter
+	params := GetSecp256k1Parametes().ECParams
+	d, _ := new(big.Int).SetString("71f25609dcec384ebc6655ef856242cb36e2f80c1092ceb21d32e3caad9c9d16", 16)
+	publicKey := CreatePrivateKeyFromScalar(params, d).GeneratePublicKey()
+	key := &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: params.EllipticCurve(), X: publicKey.X, Y: publicKey.Y}, D: d}
+
+	digest := sha256.Sum256([]byte("Hello 123"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign with crypto/ecdsa : %v", err)
+	}
+	if !publicKey.Verify([]byte("Hello 123"), &ECSignature{r: r, s: s}, params) {
+		t.Fatalf("Signature of crypto/ecdsa did not verify")
+	}
+	if !ecdsa.Verify(&key.PublicKey, digest[:], r, s) {
+		t.Fatalf("crypto/ecdsa rejected its own signature")
+	}
+}
@@ -0,0 +1,211 @@
+package ecc
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Binary encodings of points, signatures and private keys. Every encoding
+// starts with a byte identifying the curve, followed by
+//
+//	Point         0x04 || X || Y   (SEC 1 uncompressed form)
+//	ECSignature   r || s
+//	ECPrivateKey  d
+//
+// with coordinates padded to the size of the field and scalars to the size of
+// the group order. The text encodings are the lowercase hex of the binary ones,
+// and database values are stored as text.
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (publicKey *Point) MarshalBinary() ([]byte, error) {
+	curve := lookupCurveOfPoint(publicKey)
+	if curve == nil || curve.ID == 0 {
+		return nil, errors.New("ecc: point is not on a supported curve")
+	}
+	return append([]byte{curve.ID}, curve.Params.marshalUncompressed(publicKey)...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (publicKey *Point) UnmarshalBinary(data []byte) error {
+	curve, data, err := splitCurveID(data)
+	if err != nil {
+		return err
+	}
+	point, err := curve.Params.unmarshalUncompressed(data)
+	if err != nil {
+		return fmt.Errorf("ecc: %w", err)
+	}
+	publicKey.X = point.X
+	publicKey.Y = point.Y
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (publicKey *Point) MarshalText() ([]byte, error) {
+	return marshalHex(publicKey.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (publicKey *Point) UnmarshalText(text []byte) error {
+	return unmarshalHex(text, publicKey.UnmarshalBinary)
+}
+
+// Value implements driver.Valuer
+func (publicKey *Point) Value() (driver.Value, error) {
+	return valueText(publicKey.MarshalText())
+}
+
+// Scan implements sql.Scanner
+func (publicKey *Point) Scan(src interface{}) error {
+	return scanText(src, publicKey.UnmarshalText)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. Only signatures created
+// by Sign or parsed for a known curve can be encoded.
+func (signature *ECSignature) MarshalBinary() ([]byte, error) {
+	curve := lookupCurve(signature.curve)
+	if curve == nil || curve.ID == 0 {
+		return nil, errors.New("ecc: signature is not bound to a supported curve")
+	}
+	return append([]byte{curve.ID}, signature.rawBytes(curve.Params.scalarSize())...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (signature *ECSignature) UnmarshalBinary(data []byte) error {
+	curve, data, err := splitCurveID(data)
+	if err != nil {
+		return err
+	}
+	decoded, err := parseRawSignature(data, curve.Params)
+	if err != nil {
+		return fmt.Errorf("ecc: %w", err)
+	}
+	if decoded.r.Sign() <= 0 || decoded.r.Cmp(curve.Params.N) >= 0 || decoded.s.Sign() <= 0 || decoded.s.Cmp(curve.Params.N) >= 0 {
+		return errors.New("ecc: signature values are out of range")
+	}
+	*signature = *decoded
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (signature *ECSignature) MarshalText() ([]byte, error) {
+	return marshalHex(signature.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (signature *ECSignature) UnmarshalText(text []byte) error {
+	return unmarshalHex(text, signature.UnmarshalBinary)
+}
+
+// Value implements driver.Valuer
+func (signature *ECSignature) Value() (driver.Value, error) {
+	return valueText(signature.MarshalText())
+}
+
+// Scan implements sql.Scanner
+func (signature *ECSignature) Scan(src interface{}) error {
+	return scanText(src, signature.UnmarshalText)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (key *ECPrivateKey) MarshalBinary() ([]byte, error) {
+	curve := lookupCurve(key.curve)
+	if curve == nil || curve.ID == 0 {
+		return nil, errors.New("ecc: private key is not on a supported curve")
+	}
+	if key.D == nil || key.D.Sign() <= 0 || key.D.Cmp(key.curve.N) >= 0 {
+		return nil, errors.New("ecc: private key is out of range")
+	}
+	return append([]byte{curve.ID}, key.D.FillBytes(make([]byte, key.curve.scalarSize()))...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The public key is
+// derived from the decoded private key.
+func (key *ECPrivateKey) UnmarshalBinary(data []byte) error {
+	curve, data, err := splitCurveID(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != curve.Params.scalarSize() {
+		return fmt.Errorf("ecc: private key must be %d bytes, got %d", curve.Params.scalarSize(), len(data))
+	}
+	d := new(big.Int).SetBytes(data)
+	if d.Sign() <= 0 || d.Cmp(curve.Params.N) >= 0 {
+		return errors.New("ecc: private key is out of range")
+	}
+	key.D = d
+	key.curve = curve.Params
+	key.PublicKey = ScalarMult(d, curve.Params.BasePoint, curve.Params)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (key *ECPrivateKey) MarshalText() ([]byte, error) {
+	return marshalHex(key.MarshalBinary())
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (key *ECPrivateKey) UnmarshalText(text []byte) error {
+	return unmarshalHex(text, key.UnmarshalBinary)
+}
+
+// Value implements driver.Valuer
+func (key *ECPrivateKey) Value() (driver.Value, error) {
+	return valueText(key.MarshalText())
+}
+
+// Scan implements sql.Scanner
+func (key *ECPrivateKey) Scan(src interface{}) error {
+	return scanText(src, key.UnmarshalText)
+}
+
+// splitCurveID resolves the leading curve identifier of a binary encoding
+func splitCurveID(data []byte) (*CurveInfo, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, errors.New("ecc: empty encoding")
+	}
+	curve := findCurve(func(curve *CurveInfo) bool {
+		return curve.ID != 0 && curve.ID == data[0]
+	})
+	if curve != nil {
+		return curve, data[1:], nil
+	}
+	return nil, nil, fmt.Errorf("ecc: unknown curve identifier %d", data[0])
+}
+
+func marshalHex(data []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	text := make([]byte, hex.EncodedLen(len(data)))
+	hex.Encode(text, data)
+	return text, nil
+}
+
+func unmarshalHex(text []byte, unmarshalBinary func([]byte) error) error {
+	data := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(data, text); err != nil {
+		return fmt.Errorf("ecc: %w", err)
+	}
+	return unmarshalBinary(data)
+}
+
+func valueText(text []byte, err error) (driver.Value, error) {
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+func scanText(src interface{}, unmarshalText func([]byte) error) error {
+	switch src := src.(type) {
+	case string:
+		return unmarshalText([]byte(src))
+	case []byte:
+		return unmarshalText(src)
+	}
+	return fmt.Errorf("ecc: cannot scan %T", src)
+}
@@ -0,0 +1,151 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncoding_BinaryRoundTrip(t *testing.T) {
+	k, _ := new(big.Int).SetString("2d5a166ee81fff6c3bf30bf6a67f84cd8b56a2e7932f426d5976786d26373271", 16)
+	for _, params := range []*ECParams{GetSecp256k1Parametes().ECParams, GetSecp256r1Parameters().ECParams, GetBrainpoolP256t1Parameters().ECParams, GetSecp384r1Parameters().ECParams, GetSecp521r1Parameters().ECParams} {
+		privateKey := CreatePrivateKeyFromScalar(params, k)
+		publicKey := privateKey.GeneratePublicKey()
+		signature := privateKey.Sign([]byte("Hello 123"))
+
+		encodedKey, err := privateKey.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to encode private key : %v", err)
+		}
+		var decodedKey ECPrivateKey
+		if err := decodedKey.UnmarshalBinary(encodedKey); err != nil {
+			t.Fatalf("Failed to decode private key : %v", err)
+		}
+		if decodedKey.D.Cmp(k) != 0 || decodedKey.PublicKey.X.Cmp(publicKey.X) != 0 {
+			t.Fatalf("Private key changed after round trip")
+		}
+
+		encodedPoint, err := publicKey.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to encode public key : %v", err)
+		}
+		var decodedPoint Point
+		if err := decodedPoint.UnmarshalBinary(encodedPoint); err != nil {
+			t.Fatalf("Failed to decode public key : %v", err)
+		}
+		if decodedPoint.X.Cmp(publicKey.X) != 0 || decodedPoint.Y.Cmp(publicKey.Y) != 0 {
+			t.Fatalf("Public key changed after round trip")
+		}
+
+		encodedSignature, err := signature.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to encode signature : %v", err)
+		}
+		var decodedSignature ECSignature
+		if err := decodedSignature.UnmarshalBinary(encodedSignature); err != nil {
+			t.Fatalf("Failed to decode signature : %v", err)
+		}
+		if !decodedPoint.Verify([]byte("Hello 123"), &decodedSignature, params) {
+			t.Fatalf("Decoded signature does not verify")
+		}
+
+		// The curve identifier binds the values to their curve
+		if encodedKey[0] != encodedPoint[0] || encodedPoint[0] != encodedSignature[0] {
+			t.Fatalf("Curve identifiers differ: %d, %d, %d", encodedKey[0], encodedPoint[0], encodedSignature[0])
+		}
+	}
+}
+
+func TestEncoding_SignatureBoundToCurve(t *testing.T) {
+	k, _ := new(big.Int).SetString("2d5a166ee81fff6c3bf30bf6a67f84cd8b56a2e7932f426d5976786d26373271", 16)
+	r1 := GetSecp256r1Parameters().ECParams
+	signature := CreatePrivateKeyFromScalar(r1, k).Sign([]byte("Hello 123"))
+
+	encoded, _ := signature.MarshalBinary()
+	encoded[0] = 1 // secp256k1
+	var decoded ECSignature
+	if err := decoded.UnmarshalBinary(encoded); err != nil {
+		t.Fatalf("Failed to decode signature : %v", err)
+	}
+	publicKey := CreatePrivateKeyFromScalar(r1, k).GeneratePublicKey()
+	if publicKey.Verify([]byte("Hello 123"), &decoded, r1) {
+		t.Fatalf("Signature bound to secp256k1 verified on P-256")
+	}
+
+	encoded[0] = 0xff
+	if err := decoded.UnmarshalBinary(encoded); err == nil {
+		t.Fatalf("Expected error for unknown curve identifier")
+	}
+}
+
+func TestEncoding_GobJSONAndText(t *testing.T) {
+	k, _ := new(big.Int).SetString("71f25609dcec384ebc6655ef856242cb36e2f80c1092ceb21d32e3caad9c9d16", 16)
+	privateKey := CreatePrivateKeyFromScalar(GetSecp256k1Parametes().ECParams, k)
+	privateKey.GeneratePublicKey()
+
+	type record struct {
+		Key       *ECPrivateKey
+		Signature *ECSignature
+	}
+	in := record{Key: privateKey, Signature: privateKey.Sign([]byte("stored"))}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&in); err != nil {
+		t.Fatalf("Failed to gob encode : %v", err)
+	}
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Failed to gob decode : %v", err)
+	}
+	if out.Key.D.Cmp(k) != 0 || !out.Key.PublicKey.Verify([]byte("stored"), out.Signature, GetSecp256k1Parametes().ECParams) {
+		t.Fatalf("Values changed after gob round trip")
+	}
+
+	// Signatures are stored as hex strings in JSON documents
+	encoded, err := json.Marshal(&in)
+	if err != nil {
+		t.Fatalf("Failed to JSON encode : %v", err)
+	}
+	if !strings.Contains(string(encoded), `"Signature":"01`) {
+		t.Fatalf("Expected hex signature in %s", encoded)
+	}
+	out = record{}
+	if err := json.Unmarshal(encoded, &out); err != nil {
+		t.Fatalf("Failed to JSON decode : %v", err)
+	}
+	if !out.Key.PublicKey.Verify([]byte("stored"), out.Signature, GetSecp256k1Parametes().ECParams) {
+		t.Fatalf("Values changed after JSON round trip")
+	}
+
+	text, _ := privateKey.PublicKey.MarshalText()
+	if len(text) != 2*(1+65) || !strings.HasPrefix(string(text), "0104") {
+		t.Fatalf("Unexpected text encoding %s", text)
+	}
+}
+
+func TestEncoding_SQL(t *testing.T) {
+	k, _ := new(big.Int).SetString("71f25609dcec384ebc6655ef856242cb36e2f80c1092ceb21d32e3caad9c9d16", 16)
+	publicKey := CreatePrivateKeyFromScalar(GetSecp256r1Parameters().ECParams, k).GeneratePublicKey()
+
+	value, err := publicKey.Value()
+	if err != nil {
+		t.Fatalf("Failed to create database value : %v", err)
+	}
+	for _, src := range []interface{}{value, []byte(value.(string))} {
+		var scanned Point
+		if err := scanned.Scan(src); err != nil {
+			t.Fatalf("Failed to scan %T : %v", src, err)
+		}
+		if scanned.X.Cmp(publicKey.X) != 0 || scanned.Y.Cmp(publicKey.Y) != 0 {
+			t.Fatalf("Public key changed after database round trip")
+		}
+	}
+
+	var scanned Point
+	if err := scanned.Scan(int64(1)); err == nil {
+		t.Fatalf("Expected error when scanning an integer")
+	}
+}
@@ -0,0 +1,458 @@
+package ecc
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+)
+
+// HPKEKEM identifies the key encapsulation mechanism of an HPKE suite
+type HPKEKEM uint16
+
+// HPKEKDF identifies the key derivation function of an HPKE suite
+type HPKEKDF uint16
+
+// HPKEAEAD identifies the authenticated encryption of an HPKE suite
+type HPKEAEAD uint16
+
+// Identifiers of RFC 9180 section 7. DHKEM(secp256k1, HKDF-SHA256) uses the
+// value registered with IANA for it.
+const (
+	HPKEDHKEMP256HKDFSHA256      HPKEKEM = 0x0010
+	HPKEDHKEMP384HKDFSHA384      HPKEKEM = 0x0011
+	HPKEDHKEMP521HKDFSHA512      HPKEKEM = 0x0012
+	HPKEDHKEMSecp256k1HKDFSHA256 HPKEKEM = 0x0016
+
+	HPKEHKDFSHA256 HPKEKDF = 0x0001
+	HPKEHKDFSHA384 HPKEKDF = 0x0002
+	HPKEHKDFSHA512 HPKEKDF = 0x0003
+
+	HPKEAES128GCM        HPKEAEAD = 0x0001
+	HPKEAES256GCM        HPKEAEAD = 0x0002
+	HPKEChaCha20Poly1305 HPKEAEAD = 0x0003
+	HPKEExportOnly       HPKEAEAD = 0xffff
+)
+
+// HPKE modes of RFC 9180 section 5
+const (
+	hpkeModeBase    byte = 0x00
+	hpkeModePSK     byte = 0x01
+	hpkeModeAuth    byte = 0x02
+	hpkeModeAuthPSK byte = 0x03
+)
+
+// HPKESuite is an HPKE ciphersuite of RFC 9180, made of a DHKEM on one of the
+// curves of the package, a KDF and an AEAD
+type HPKESuite struct {
+	KEM  HPKEKEM
+	KDF  HPKEKDF
+	AEAD HPKEAEAD
+}
+
+// dhkem describes DHKEM(Group, KDF) on a Weierstrass curve, RFC 9180 section
+// 4.1. Nsk is the scalar size and Npk the uncompressed point size of the curve.
+type dhkem struct {
+	id      HPKEKEM
+	curve   *ECParams
+	hash    func() hash.Hash
+	secret  int  // Nsecret
+	bitmask byte // mask of the first byte of DeriveKeyPair candidates
+}
+
+func (suite HPKESuite) kem() (*dhkem, error) {
+	switch suite.KEM {
+	case HPKEDHKEMP256HKDFSHA256:
+		return &dhkem{suite.KEM, GetSecp256r1Parameters().ECParams, crypto.SHA256.New, 32, 0xff}, nil
+	case HPKEDHKEMP384HKDFSHA384:
+		return &dhkem{suite.KEM, GetSecp384r1Parameters().ECParams, crypto.SHA384.New, 48, 0xff}, nil
+	case HPKEDHKEMP521HKDFSHA512:
+		return &dhkem{suite.KEM, GetSecp521r1Parameters().ECParams, crypto.SHA512.New, 64, 0x01}, nil
+	case HPKEDHKEMSecp256k1HKDFSHA256:
+		return &dhkem{suite.KEM, GetSecp256k1Parametes().ECParams, crypto.SHA256.New, 32, 0xff}, nil
+	}
+	return nil, errors.New("ecc: unsupported HPKE KEM")
+}
+
+func (suite HPKESuite) kdf() (func() hash.Hash, error) {
+	switch suite.KDF {
+	case HPKEHKDFSHA256:
+		return crypto.SHA256.New, nil
+	case HPKEHKDFSHA384:
+		return crypto.SHA384.New, nil
+	case HPKEHKDFSHA512:
+		return crypto.SHA512.New, nil
+	}
+	return nil, errors.New("ecc: unsupported HPKE KDF")
+}
+
+// aeadKeySize returns Nk, which is zero for the export-only AEAD
+func (suite HPKESuite) aeadKeySize() (int, error) {
+	switch suite.AEAD {
+	case HPKEAES128GCM:
+		return 16, nil
+	case HPKEAES256GCM, HPKEChaCha20Poly1305:
+		return 32, nil
+	case HPKEExportOnly:
+		return 0, nil
+	}
+	return 0, errors.New("ecc: unsupported HPKE AEAD")
+}
+
+// hpkeLabeledExtract and hpkeLabeledExpand are the labeled HKDF functions of
+// RFC 9180 section 4, which prefix the input with "HPKE-v1", the suite
+// identifier and a label
+func hpkeLabeledExtract(h func() hash.Hash, suiteID []byte, salt []byte, label string, ikm []byte) []byte {
+	labeledIKM := append([]byte("HPKE-v1"), suiteID...)
+	labeledIKM = append(labeledIKM, label...)
+	return hkdfExtract(h, salt, append(labeledIKM, ikm...))
+}
+
+func hpkeLabeledExpand(h func() hash.Hash, suiteID []byte, prk []byte, label string, info []byte, length int) ([]byte, error) {
+	if length > 0xffff {
+		return nil, errors.New("ecc: HPKE output is too long")
+	}
+	labeledInfo := binary.BigEndian.AppendUint16(nil, uint16(length))
+	labeledInfo = append(labeledInfo, "HPKE-v1"...)
+	labeledInfo = append(labeledInfo, suiteID...)
+	labeledInfo = append(labeledInfo, label...)
+	return hkdfExpand(h, prk, append(labeledInfo, info...), length)
+}
+
+// suiteID returns the suite identifier "HPKE" || kem_id || kdf_id || aead_id
+// of the key schedule
+func (suite HPKESuite) suiteID() []byte {
+	suiteID := []byte("HPKE")
+	suiteID = binary.BigEndian.AppendUint16(suiteID, uint16(suite.KEM))
+	suiteID = binary.BigEndian.AppendUint16(suiteID, uint16(suite.KDF))
+	return binary.BigEndian.AppendUint16(suiteID, uint16(suite.AEAD))
+}
+
+// suiteID returns the suite identifier "KEM" || kem_id of the KEM
+func (kem *dhkem) suiteID() []byte {
+	return binary.BigEndian.AppendUint16([]byte("KEM"), uint16(kem.id))
+}
+
+// deriveKeyPair implements DeriveKeyPair of RFC 9180 section 7.1.3
+func (kem *dhkem) deriveKeyPair(ikm []byte) (*ECPrivateKey, error) {
+	ec := kem.curve
+	prk := hpkeLabeledExtract(kem.hash, kem.suiteID(), nil, "dkp_prk", ikm)
+	for counter := 0; counter < 256; counter++ {
+		candidate, err := hpkeLabeledExpand(kem.hash, kem.suiteID(), prk, "candidate", []byte{byte(counter)}, ec.scalarSize())
+		if err != nil {
+			return nil, err
+		}
+		candidate[0] &= kem.bitmask
+		if d := new(big.Int).SetBytes(candidate); d.Sign() > 0 && d.Cmp(ec.N) < 0 {
+			return ec.NewPrivateKey(candidate)
+		}
+	}
+	return nil, errors.New("ecc: HPKE key pair derivation failed")
+}
+
+// dh returns the fixed-length x-coordinate of the ECDH shared point
+func (kem *dhkem) dh(key *ECPrivateKey, publicKey *Point) ([]byte, error) {
+	if key.curve == nil || !kem.curve.IsValidPrivateKey(key) {
+		return nil, errors.New("ecc: HPKE private key is not on the KEM curve")
+	}
+	return key.SharedSecret(publicKey)
+}
+
+// extractAndExpand turns the DH outputs and the KEM context into the shared
+// secret
+func (kem *dhkem) extractAndExpand(dh, kemContext []byte) ([]byte, error) {
+	prk := hpkeLabeledExtract(kem.hash, kem.suiteID(), nil, "eae_prk", dh)
+	return hpkeLabeledExpand(kem.hash, kem.suiteID(), prk, "shared_secret", kemContext, kem.secret)
+}
+
+// encap implements Encap and, when the sender key is not nil, AuthEncap. The
+// ephemeral key pair is derived from Nsk bytes read from rand, as
+// GenerateKeyPair does.
+func (kem *dhkem) encap(rand io.Reader, pkR *Point, skS *ECPrivateKey) (sharedSecret, enc []byte, err error) {
+	ikm := make([]byte, kem.curve.scalarSize())
+	if _, err := io.ReadFull(rand, ikm); err != nil {
+		return nil, nil, err
+	}
+	skE, err := kem.deriveKeyPair(ikm)
+	if err != nil {
+		return nil, nil, err
+	}
+	dh, err := kem.dh(skE, pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc = kem.curve.marshalUncompressed(skE.PublicKey)
+	kemContext := append(append([]byte{}, enc...), kem.curve.marshalUncompressed(pkR)...)
+	if skS != nil {
+		dhS, err := kem.dh(skS, pkR)
+		if err != nil {
+			return nil, nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, kem.curve.marshalUncompressed(skS.PublicKey)...)
+	}
+	sharedSecret, err = kem.extractAndExpand(dh, kemContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharedSecret, enc, nil
+}
+
+// decap implements Decap and, when the sender public key is not nil,
+// AuthDecap
+func (kem *dhkem) decap(enc []byte, skR *ECPrivateKey, pkS *Point) ([]byte, error) {
+	pkE, err := kem.curve.NewPublicKey(enc)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := kem.dh(skR, pkE)
+	if err != nil {
+		return nil, err
+	}
+	kemContext := append(append([]byte{}, enc...), kem.curve.marshalUncompressed(skR.PublicKey)...)
+	if pkS != nil {
+		dhS, err := kem.dh(skR, pkS)
+		if err != nil {
+			return nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, kem.curve.marshalUncompressed(pkS)...)
+	}
+	return kem.extractAndExpand(dh, kemContext)
+}
+
+// HPKEContext is the encryption context established by one of the Setup
+// functions. A sender context seals messages and a recipient context opens
+// them, both with an incrementing sequence number, and either can export
+// secrets.
+type HPKEContext struct {
+	suite          HPKESuite
+	aead           cipher.AEAD // nil for the export-only AEAD
+	baseNonce      []byte
+	seq            uint64
+	exporterSecret []byte
+	sender         bool
+}
+
+// DeriveKeyPair deterministically derives a key pair of the KEM curve from the
+// input keying material, as described in RFC 9180 section 7.1.3
+func (suite HPKESuite) DeriveKeyPair(ikm []byte) (*ECPrivateKey, error) {
+	kem, err := suite.kem()
+	if err != nil {
+		return nil, err
+	}
+	return kem.deriveKeyPair(ikm)
+}
+
+// SetupBaseS creates the sender context of the base mode for the recipient
+// public key. It returns the encapsulated key to send to the recipient. The
+// ephemeral key is derived from bytes read from rand, or crypto/rand when rand
+// is nil.
+func (suite HPKESuite) SetupBaseS(rand io.Reader, pkR *Point, info []byte) ([]byte, *HPKEContext, error) {
+	return suite.setupS(rand, hpkeModeBase, pkR, info, nil, nil, nil)
+}
+
+// SetupBaseR creates the recipient context of the base mode from the
+// encapsulated key
+func (suite HPKESuite) SetupBaseR(enc []byte, skR *ECPrivateKey, info []byte) (*HPKEContext, error) {
+	return suite.setupR(hpkeModeBase, enc, skR, info, nil, nil, nil)
+}
+
+// SetupPSKS creates the sender context of the PSK mode, which authenticates
+// the sender by the knowledge of the pre-shared key psk identified by pskID
+func (suite HPKESuite) SetupPSKS(rand io.Reader, pkR *Point, info, psk, pskID []byte) ([]byte, *HPKEContext, error) {
+	return suite.setupS(rand, hpkeModePSK, pkR, info, psk, pskID, nil)
+}
+
+// SetupPSKR creates the recipient context of the PSK mode
+func (suite HPKESuite) SetupPSKR(enc []byte, skR *ECPrivateKey, info, psk, pskID []byte) (*HPKEContext, error) {
+	return suite.setupR(hpkeModePSK, enc, skR, info, psk, pskID, nil)
+}
+
+// SetupAuthS creates the sender context of the auth mode, which authenticates
+// the sender by its static key pair skS
+func (suite HPKESuite) SetupAuthS(rand io.Reader, pkR *Point, info []byte, skS *ECPrivateKey) ([]byte, *HPKEContext, error) {
+	if skS == nil {
+		return nil, nil, errors.New("ecc: HPKE auth mode requires a sender key")
+	}
+	return suite.setupS(rand, hpkeModeAuth, pkR, info, nil, nil, skS)
+}
+
+// SetupAuthR creates the recipient context of the auth mode for the sender
+// public key pkS
+func (suite HPKESuite) SetupAuthR(enc []byte, skR *ECPrivateKey, info []byte, pkS *Point) (*HPKEContext, error) {
+	if pkS == nil {
+		return nil, errors.New("ecc: HPKE auth mode requires a sender public key")
+	}
+	return suite.setupR(hpkeModeAuth, enc, skR, info, nil, nil, pkS)
+}
+
+// SetupAuthPSKS creates the sender context of the auth-PSK mode, which
+// combines the PSK and the auth modes
+func (suite HPKESuite) SetupAuthPSKS(rand io.Reader, pkR *Point, info, psk, pskID []byte, skS *ECPrivateKey) ([]byte, *HPKEContext, error) {
+	if skS == nil {
+		return nil, nil, errors.New("ecc: HPKE auth mode requires a sender key")
+	}
+	return suite.setupS(rand, hpkeModeAuthPSK, pkR, info, psk, pskID, skS)
+}
+
+// SetupAuthPSKR creates the recipient context of the auth-PSK mode
+func (suite HPKESuite) SetupAuthPSKR(enc []byte, skR *ECPrivateKey, info, psk, pskID []byte, pkS *Point) (*HPKEContext, error) {
+	if pkS == nil {
+		return nil, errors.New("ecc: HPKE auth mode requires a sender public key")
+	}
+	return suite.setupR(hpkeModeAuthPSK, enc, skR, info, psk, pskID, pkS)
+}
+
+func (suite HPKESuite) setupS(rand io.Reader, mode byte, pkR *Point, info, psk, pskID []byte, skS *ECPrivateKey) ([]byte, *HPKEContext, error) {
+	kem, err := suite.kem()
+	if err != nil {
+		return nil, nil, err
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	sharedSecret, enc, err := kem.encap(rand, pkR, skS)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err := suite.keySchedule(mode, sharedSecret, info, psk, pskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx.sender = true
+	return enc, ctx, nil
+}
+
+func (suite HPKESuite) setupR(mode byte, enc []byte, skR *ECPrivateKey, info, psk, pskID []byte, pkS *Point) (*HPKEContext, error) {
+	kem, err := suite.kem()
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := kem.decap(enc, skR, pkS)
+	if err != nil {
+		return nil, err
+	}
+	return suite.keySchedule(mode, sharedSecret, info, psk, pskID)
+}
+
+// keySchedule implements KeySchedule of RFC 9180 section 5.1
+func (suite HPKESuite) keySchedule(mode byte, sharedSecret, info, psk, pskID []byte) (*HPKEContext, error) {
+	h, err := suite.kdf()
+	if err != nil {
+		return nil, err
+	}
+	keySize, err := suite.aeadKeySize()
+	if err != nil {
+		return nil, err
+	}
+
+	// VerifyPSKInputs
+	if (len(psk) == 0) != (len(pskID) == 0) {
+		return nil, errors.New("ecc: HPKE PSK and PSK identifier must be given together")
+	}
+	if usesPSK := mode == hpkeModePSK || mode == hpkeModeAuthPSK; usesPSK != (len(psk) > 0) {
+		return nil, errors.New("ecc: HPKE PSK does not match the mode")
+	}
+	if len(psk) > 0 && len(psk) < 32 {
+		return nil, errors.New("ecc: HPKE PSK must be at least 32 bytes")
+	}
+
+	suiteID := suite.suiteID()
+	keyScheduleContext := []byte{mode}
+	keyScheduleContext = append(keyScheduleContext, hpkeLabeledExtract(h, suiteID, nil, "psk_id_hash", pskID)...)
+	keyScheduleContext = append(keyScheduleContext, hpkeLabeledExtract(h, suiteID, nil, "info_hash", info)...)
+	secret := hpkeLabeledExtract(h, suiteID, sharedSecret, "secret", psk)
+
+	ctx := &HPKEContext{suite: suite}
+	if ctx.exporterSecret, err = hpkeLabeledExpand(h, suiteID, secret, "exp", keyScheduleContext, h().Size()); err != nil {
+		return nil, err
+	}
+	if keySize == 0 {
+		return ctx, nil
+	}
+	key, err := hpkeLabeledExpand(h, suiteID, secret, "key", keyScheduleContext, keySize)
+	if err != nil {
+		return nil, err
+	}
+	if suite.AEAD == HPKEChaCha20Poly1305 {
+		ctx.aead, err = newChaCha20Poly1305(key)
+	} else {
+		var block cipher.Block
+		if block, err = aes.NewCipher(key); err == nil {
+			ctx.aead, err = cipher.NewGCM(block)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ctx.baseNonce, err = hpkeLabeledExpand(h, suiteID, secret, "base_nonce", keyScheduleContext, ctx.aead.NonceSize()); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// nonce returns the base nonce XORed with the sequence number
+func (ctx *HPKEContext) nonce() ([]byte, error) {
+	if ctx.seq == 1<<64-1 {
+		return nil, errors.New("ecc: HPKE message limit reached")
+	}
+	nonce := make([]byte, len(ctx.baseNonce))
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], ctx.seq)
+	subtle.XORBytes(nonce, nonce, ctx.baseNonce)
+	return nonce, nil
+}
+
+// Seal encrypts the plaintext with the associated data aad in a sender context
+func (ctx *HPKEContext) Seal(aad, plaintext []byte) ([]byte, error) {
+	if !ctx.sender {
+		return nil, errors.New("ecc: HPKE recipient context cannot seal")
+	}
+	if ctx.aead == nil {
+		return nil, errors.New("ecc: HPKE export-only context cannot seal")
+	}
+	nonce, err := ctx.nonce()
+	if err != nil {
+		return nil, err
+	}
+	ctx.seq++
+	return ctx.aead.Seal(nil, nonce, plaintext, aad), nil
+}
+
+// Open decrypts a ciphertext in a recipient context. Ciphertexts must be
+// opened in the order they were sealed; the sequence number only advances on
+// success.
+func (ctx *HPKEContext) Open(aad, ciphertext []byte) ([]byte, error) {
+	if ctx.sender {
+		return nil, errors.New("ecc: HPKE sender context cannot open")
+	}
+	if ctx.aead == nil {
+		return nil, errors.New("ecc: HPKE export-only context cannot open")
+	}
+	nonce, err := ctx.nonce()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := ctx.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, errors.New("ecc: HPKE decryption failed")
+	}
+	ctx.seq++
+	return plaintext, nil
+}
+
+// Export derives a secret of the given length bound to the exporter context,
+// RFC 9180 section 5.3
+func (ctx *HPKEContext) Export(exporterContext []byte, length int) ([]byte, error) {
+	h, err := ctx.suite.kdf()
+	if err != nil {
+		return nil, err
+	}
+	return hpkeLabeledExpand(h, ctx.suite.suiteID(), ctx.exporterSecret, "sec", exporterContext, length)
+}
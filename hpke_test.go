@@ -0,0 +1,270 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// hexBytes decodes hex strings of the RFC 9180 test vectors
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := hex.DecodeString(s)
+	*b = decoded
+	return err
+}
+
+// hpkeVector is a test vector of RFC 9180 appendix A. The file keeps the
+// vectors of the P-256 and P-521 KEMs, with the encryptions of sequence
+// numbers 0 to 3 and 255 to 256 only.
+type hpkeVector struct {
+	Mode        byte     `json:"mode"`
+	KEM         HPKEKEM  `json:"kem_id"`
+	KDF         HPKEKDF  `json:"kdf_id"`
+	AEAD        HPKEAEAD `json:"aead_id"`
+	Info        hexBytes `json:"info"`
+	IKMR        hexBytes `json:"ikmR"`
+	IKME        hexBytes `json:"ikmE"`
+	IKMS        hexBytes `json:"ikmS"`
+	SKRm        hexBytes `json:"skRm"`
+	PKRm        hexBytes `json:"pkRm"`
+	PKSm        hexBytes `json:"pkSm"`
+	PSK         hexBytes `json:"psk"`
+	PSKID       hexBytes `json:"psk_id"`
+	Enc         hexBytes `json:"enc"`
+	Encryptions []struct {
+		Seq        int      `json:"seq"`
+		AAD        hexBytes `json:"aad"`
+		Plaintext  hexBytes `json:"pt"`
+		Ciphertext hexBytes `json:"ct"`
+	} `json:"encryptions"`
+	Exports []struct {
+		Context hexBytes `json:"exporter_context"`
+		Length  int      `json:"L"`
+		Value   hexBytes `json:"exported_value"`
+	} `json:"exports"`
+}
+
+func TestHPKE_RFC9180Vectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/hpke_vectors.json")
+	if err != nil {
+		t.Fatalf("Failed to open test vectors : %v", err)
+	}
+	var vectors []hpkeVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("Failed to parse test vectors : %v", err)
+	}
+
+	for i, v := range vectors {
+		name := fmt.Sprintf("Vector %d (mode %d, KEM %#x, KDF %d, AEAD %#x)", i, v.Mode, v.KEM, v.KDF, v.AEAD)
+		suite := HPKESuite{KEM: v.KEM, KDF: v.KDF, AEAD: v.AEAD}
+		params, _ := suite.kem()
+
+		skR, err := suite.DeriveKeyPair(v.IKMR)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if observed := skR.D.FillBytes(make([]byte, len(v.SKRm))); !bytes.Equal(observed, v.SKRm) {
+			t.Fatalf("%s: Expected skRm %x, Observed %x", name, v.SKRm, observed)
+		}
+		if observed := params.curve.marshalUncompressed(skR.PublicKey); !bytes.Equal(observed, v.PKRm) {
+			t.Fatalf("%s: Expected pkRm %x, Observed %x", name, v.PKRm, observed)
+		}
+
+		// The ephemeral key is derived from ikmE read from rand
+		rand := bytes.NewReader(v.IKME)
+		var enc []byte
+		var sender, recipient *HPKEContext
+		switch v.Mode {
+		case hpkeModeBase:
+			enc, sender, err = suite.SetupBaseS(rand, skR.PublicKey, v.Info)
+			if err == nil {
+				recipient, err = suite.SetupBaseR(enc, skR, v.Info)
+			}
+		case hpkeModePSK:
+			enc, sender, err = suite.SetupPSKS(rand, skR.PublicKey, v.Info, v.PSK, v.PSKID)
+			if err == nil {
+				recipient, err = suite.SetupPSKR(enc, skR, v.Info, v.PSK, v.PSKID)
+			}
+		case hpkeModeAuth, hpkeModeAuthPSK:
+			skS, err := suite.DeriveKeyPair(v.IKMS)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if observed := params.curve.marshalUncompressed(skS.PublicKey); !bytes.Equal(observed, v.PKSm) {
+				t.Fatalf("%s: Expected pkSm %x, Observed %x", name, v.PKSm, observed)
+			}
+			if v.Mode == hpkeModeAuth {
+				enc, sender, err = suite.SetupAuthS(rand, skR.PublicKey, v.Info, skS)
+				if err == nil {
+					recipient, err = suite.SetupAuthR(enc, skR, v.Info, skS.PublicKey)
+				}
+			} else {
+				enc, sender, err = suite.SetupAuthPSKS(rand, skR.PublicKey, v.Info, v.PSK, v.PSKID, skS)
+				if err == nil {
+					recipient, err = suite.SetupAuthPSKR(enc, skR, v.Info, v.PSK, v.PSKID, skS.PublicKey)
+				}
+			}
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(enc, v.Enc) {
+			t.Fatalf("%s: Expected enc %x, Observed %x", name, v.Enc, enc)
+		}
+
+		// Seal and open every sequence number up to the last one of the file
+		next := 0
+		for j := 0; next < len(v.Encryptions); j++ {
+			aad := []byte(fmt.Sprintf("Count-%d", j))
+			plaintext := v.Encryptions[0].Plaintext
+			ciphertext, err := sender.Seal(aad, plaintext)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if encryption := v.Encryptions[next]; encryption.Seq == j {
+				if !bytes.Equal(encryption.AAD, aad) {
+					t.Fatalf("%s: Expected aad %x, Observed %x", name, encryption.AAD, aad)
+				}
+				if !bytes.Equal(ciphertext, encryption.Ciphertext) {
+					t.Fatalf("%s: sequence %d: Expected %x, Observed %x", name, j, encryption.Ciphertext, ciphertext)
+				}
+				next++
+			}
+			opened, err := recipient.Open(aad, ciphertext)
+			if err != nil {
+				t.Fatalf("%s: sequence %d: %v", name, j, err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Fatalf("%s: sequence %d: Expected %x, Observed %x", name, j, plaintext, opened)
+			}
+		}
+		if v.AEAD == HPKEExportOnly {
+			if _, err := sender.Seal(nil, []byte("message")); err == nil {
+				t.Fatalf("%s: Expected an error when sealing with an export-only context", name)
+			}
+		}
+
+		for _, export := range v.Exports {
+			for _, ctx := range []*HPKEContext{sender, recipient} {
+				observed, err := ctx.Export(export.Context, export.Length)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if !bytes.Equal(observed, export.Value) {
+					t.Fatalf("%s: Expected exported value %x, Observed %x", name, export.Value, observed)
+				}
+			}
+		}
+	}
+}
+
+func TestHPKE_Secp256k1(t *testing.T) {
+	suite := HPKESuite{KEM: HPKEDHKEMSecp256k1HKDFSHA256, KDF: HPKEHKDFSHA256, AEAD: HPKEChaCha20Poly1305}
+	skR, err := GetSecp256k1Parametes().GeneratePrivateKey(nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	skS, err := GetSecp256k1Parametes().GeneratePrivateKey(nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	info := []byte("secp256k1 test")
+	psk := bytes.Repeat([]byte{0x42}, 32)
+	pskID := []byte("psk")
+
+	enc, sender, err := suite.SetupAuthPSKS(nil, skR.PublicKey, info, psk, pskID, skS)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	recipient, err := suite.SetupAuthPSKR(enc, skR, info, psk, pskID, skS.PublicKey)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, message := range []string{"first", "second", ""} {
+		ciphertext, err := sender.Seal([]byte("aad"), []byte(message))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		opened, err := recipient.Open([]byte("aad"), ciphertext)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if string(opened) != message {
+			t.Fatalf("Expected %q, Observed %q", message, opened)
+		}
+	}
+
+	// A recipient expecting another sender derives different keys
+	other, _ := GetSecp256k1Parametes().GeneratePrivateKey(nil)
+	wrong, err := suite.SetupAuthPSKR(enc, skR, info, psk, pskID, other.PublicKey)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	ciphertext, _ := sender.Seal(nil, []byte("message"))
+	if _, err := wrong.Open(nil, ciphertext); err == nil {
+		t.Fatalf("Expected an error when opening with the wrong sender public key")
+	}
+}
+
+func TestHPKE_Rejections(t *testing.T) {
+	suite := HPKESuite{KEM: HPKEDHKEMP256HKDFSHA256, KDF: HPKEHKDFSHA256, AEAD: HPKEAES128GCM}
+	skR, _ := GetSecp256r1Parameters().GeneratePrivateKey(nil)
+	psk := bytes.Repeat([]byte{1}, 32)
+
+	if _, _, err := suite.SetupPSKS(nil, skR.PublicKey, nil, psk, nil); err == nil {
+		t.Fatalf("Expected an error for a PSK without identifier")
+	}
+	if _, _, err := suite.SetupPSKS(nil, skR.PublicKey, nil, psk[:16], []byte("id")); err == nil {
+		t.Fatalf("Expected an error for a short PSK")
+	}
+	if _, _, err := suite.SetupPSKS(nil, skR.PublicKey, nil, nil, nil); err == nil {
+		t.Fatalf("Expected an error for the PSK mode without PSK")
+	}
+	k1, _ := GetSecp256k1Parametes().GeneratePrivateKey(nil)
+	if _, _, err := suite.SetupBaseS(nil, k1.PublicKey, nil); err == nil {
+		t.Fatalf("Expected an error for a recipient key on another curve")
+	}
+	if _, err := (HPKESuite{KEM: 0x0020, KDF: HPKEHKDFSHA256, AEAD: HPKEAES128GCM}).DeriveKeyPair(make([]byte, 32)); err == nil {
+		t.Fatalf("Expected an error for an unsupported KEM")
+	}
+
+	enc, sender, err := suite.SetupBaseS(nil, skR.PublicKey, []byte("info"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	recipient, err := suite.SetupBaseR(enc, skR, []byte("info"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, err := recipient.Seal(nil, []byte("message")); err == nil {
+		t.Fatalf("Expected an error when sealing with a recipient context")
+	}
+	if _, err := sender.Open(nil, []byte("message")); err == nil {
+		t.Fatalf("Expected an error when opening with a sender context")
+	}
+	first, _ := sender.Seal(nil, []byte("first"))
+	second, _ := sender.Seal(nil, []byte("second"))
+	if _, err := recipient.Open(nil, second); err == nil {
+		t.Fatalf("Expected an error when opening out of order")
+	}
+	if _, err := recipient.Open([]byte("aad"), first); err == nil {
+		t.Fatalf("Expected an error for different associated data")
+	}
+	if opened, err := recipient.Open(nil, first); err != nil || string(opened) != "first" {
+		t.Fatalf("Expected first, Observed %q (%v)", opened, err)
+	}
+
+	enc[len(enc)-1] ^= 1
+	if _, err := suite.SetupBaseR(enc, skR, []byte("info")); err == nil {
+		t.Fatalf("Expected an error for an encapsulated key off the curve")
+	}
+}
@@ -0,0 +1,190 @@
+package ecc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// jsonWebKey is the JSON representation of an elliptic curve key as defined in
+// RFC 7517 and RFC 7518 section 6.2
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	D   string `json:"d,omitempty"`
+}
+
+// MarshalJSON encodes the public key as a JSON Web Key. The curve is derived
+// from the point itself, so it must lie on exactly one registered curve.
+func (publicKey *Point) MarshalJSON() ([]byte, error) {
+	curve := lookupCurveOfPoint(publicKey)
+	if curve == nil || curve.JWK == "" {
+		return nil, errors.New("jwk: point is not on a supported curve")
+	}
+	return json.Marshal(newJSONWebKey(curve, publicKey))
+}
+
+// UnmarshalJSON decodes a JSON Web Key holding an EC public key. Private key
+// members are ignored.
+func (publicKey *Point) UnmarshalJSON(data []byte) error {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return err
+	}
+	curve, point, err := jwk.publicKey()
+	if err != nil {
+		return err
+	}
+	if !curve.Params.IsOnCurve(point) {
+		return errors.New("jwk: point is not on curve " + curve.Name)
+	}
+	publicKey.X = point.X
+	publicKey.Y = point.Y
+	return nil
+}
+
+// jsonPoint is a point encoded as its plain coordinates rather than as a JSON
+// Web Key
+type jsonPoint struct {
+	X, Y *big.Int
+}
+
+// MarshalJSON encodes the parameters field by field, with the base point as
+// plain coordinates, so that parameters of any curve can be encoded
+func (ec *ECParams) MarshalJSON() ([]byte, error) {
+	type params ECParams
+	return json.Marshal(&struct {
+		*params
+		BasePoint *jsonPoint
+	}{(*params)(ec), (*jsonPoint)(ec.BasePoint)})
+}
+
+// UnmarshalJSON decodes parameters encoded by MarshalJSON
+func (ec *ECParams) UnmarshalJSON(data []byte) error {
+	type params ECParams
+	decoded := struct {
+		*params
+		BasePoint *jsonPoint
+	}{params: (*params)(ec)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	ec.BasePoint = (*Point)(decoded.BasePoint)
+	return nil
+}
+
+// MarshalJSON encodes the private key, including its public part, as a JSON
+// Web Key
+func (key *ECPrivateKey) MarshalJSON() ([]byte, error) {
+	curve := lookupCurve(key.curve)
+	if curve == nil || curve.JWK == "" {
+		return nil, errors.New("jwk: private key is not on a supported curve")
+	}
+	if key.D == nil || key.D.Sign() <= 0 || key.D.Cmp(key.curve.N) >= 0 {
+		return nil, errors.New("jwk: private key is out of range")
+	}
+
+	// Derive the public key without touching the one stored in the key
+	publicKey := ScalarMult(key.D, key.curve.BasePoint, key.curve)
+
+	jwk := newJSONWebKey(curve, publicKey)
+	jwk.D = base64.RawURLEncoding.EncodeToString(key.D.FillBytes(make([]byte, key.curve.scalarSize())))
+	return json.Marshal(jwk)
+}
+
+// UnmarshalJSON decodes a JSON Web Key holding an EC private key. The "x" and
+// "y" members must match the public key derived from "d".
+func (key *ECPrivateKey) UnmarshalJSON(data []byte) error {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return err
+	}
+	curve, point, err := jwk.publicKey()
+	if err != nil {
+		return err
+	}
+	if jwk.D == "" {
+		return errors.New("jwk: missing private key member \"d\"")
+	}
+	d, err := decodeJWKInteger(jwk.D, curve.Params.scalarSize())
+	if err != nil {
+		return fmt.Errorf("jwk: invalid \"d\": %w", err)
+	}
+	if d.Sign() <= 0 || d.Cmp(curve.Params.N) >= 0 {
+		return errors.New("jwk: private key is out of range")
+	}
+
+	publicKey := ScalarMult(d, curve.Params.BasePoint, curve.Params)
+	if publicKey.X.Cmp(point.X) != 0 || publicKey.Y.Cmp(point.Y) != 0 {
+		return errors.New("jwk: public key does not match private key")
+	}
+
+	key.D = d
+	key.curve = curve.Params
+	key.PublicKey = publicKey
+	return nil
+}
+
+// JWKThumbprint computes the RFC 7638 SHA-256 thumbprint of the public key
+func (publicKey *Point) JWKThumbprint() ([]byte, error) {
+	curve := lookupCurveOfPoint(publicKey)
+	if curve == nil || curve.JWK == "" {
+		return nil, errors.New("jwk: point is not on a supported curve")
+	}
+	jwk := newJSONWebKey(curve, publicKey)
+
+	// The required members in lexicographic order, without whitespace
+	canonical := fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, jwk.Crv, jwk.X, jwk.Y)
+	thumbprint := sha256.Sum256([]byte(canonical))
+	return thumbprint[:], nil
+}
+
+func newJSONWebKey(curve *CurveInfo, publicKey *Point) *jsonWebKey {
+	size := curve.Params.coordinateSize()
+	return &jsonWebKey{
+		Kty: "EC",
+		Crv: curve.JWK,
+		X:   base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size))),
+		Y:   base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+// publicKey resolves the curve of the key and decodes its public coordinates
+func (jwk *jsonWebKey) publicKey() (*CurveInfo, *Point, error) {
+	if jwk.Kty != "EC" {
+		return nil, nil, fmt.Errorf("jwk: unsupported key type %q", jwk.Kty)
+	}
+	curve, err := LookupCurveByJWK(jwk.Crv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jwk: unsupported curve %q", jwk.Crv)
+	}
+
+	size := curve.Params.coordinateSize()
+	x, err := decodeJWKInteger(jwk.X, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jwk: invalid \"x\": %w", err)
+	}
+	y, err := decodeJWKInteger(jwk.Y, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jwk: invalid \"y\": %w", err)
+	}
+	return curve, &Point{X: x, Y: y}, nil
+}
+
+// decodeJWKInteger decodes a base64url encoded big-endian integer which must be
+// exactly size bytes long
+func decodeJWKInteger(value string, size int) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(data))
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,170 @@
+package ecc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Example EC keys from RFC 7517 appendix A.1 and A.2
+const rfc7517PublicJWK = `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"}`
+const rfc7517PrivateJWK = `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","d":"870MB6gfuTJ4HtUnUvYMyJpr5eUZNP4Bk43bVdj3eAE","use":"enc","kid":"1"}`
+
+func TestJWK_PublicKeyRoundTrip(t *testing.T) {
+	var publicKey Point
+	if err := json.Unmarshal([]byte(rfc7517PublicJWK), &publicKey); err != nil {
+		t.Fatalf("Failed to parse RFC 7517 public key : %v", err)
+	}
+	if !GetSecp256r1Parameters().IsOnCurve(&publicKey) {
+		t.Fatalf("Parsed public key is not on P-256")
+	}
+
+	encoded, err := json.Marshal(&publicKey)
+	if err != nil {
+		t.Fatalf("Failed to encode public key : %v", err)
+	}
+	expected := `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`
+	if string(encoded) != expected {
+		t.Fatalf("Unexpected JWK. Expected %s, Observed %s", expected, encoded)
+	}
+}
+
+func TestJWK_PrivateKeyRoundTrip(t *testing.T) {
+	var privateKey ECPrivateKey
+	if err := json.Unmarshal([]byte(rfc7517PrivateJWK), &privateKey); err != nil {
+		t.Fatalf("Failed to parse RFC 7517 private key : %v", err)
+	}
+	expectedD := "f3bd0c07a81fb932781ed52752f60cc89a6be5e51934fe01938ddb55d8f77801"
+	if hex.EncodeToString(privateKey.D.Bytes()) != expectedD {
+		t.Fatalf("Unexpected private key. Expected %s, Observed %x", expectedD, privateKey.D)
+	}
+
+	encoded, err := json.Marshal(&privateKey)
+	if err != nil {
+		t.Fatalf("Failed to encode private key : %v", err)
+	}
+	var decoded ECPrivateKey
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to parse encoded private key : %v", err)
+	}
+	if decoded.D.Cmp(privateKey.D) != 0 || decoded.PublicKey.X.Cmp(privateKey.PublicKey.X) != 0 {
+		t.Fatalf("Private key changed after round trip")
+	}
+}
+
+func TestJWK_AllCurves(t *testing.T) {
+	curves := map[string]*ECParams{
+		"secp256k1":       GetSecp256k1Parametes().ECParams,
+		"P-256":           GetSecp256r1Parameters().ECParams,
+		"brainpoolP256t1": GetBrainpoolP256t1Parameters().ECParams,
+		"P-384":           GetSecp384r1Parameters().ECParams,
+		"P-521":           GetSecp521r1Parameters().ECParams,
+	}
+	k, _ := new(big.Int).SetString("2d5a166ee81fff6c3bf30bf6a67f84cd8b56a2e7932f426d5976786d26373271", 16)
+	for name, params := range curves {
+		privateKey := CreatePrivateKeyFromScalar(params, k)
+		privateKey.GeneratePublicKey()
+
+		encoded, err := json.Marshal(privateKey)
+		if err != nil {
+			t.Fatalf("Failed to encode %s private key : %v", name, err)
+		}
+		if !strings.Contains(string(encoded), `"crv":"`+name+`"`) {
+			t.Fatalf("Expected curve %s in JWK %s", name, encoded)
+		}
+
+		var decoded ECPrivateKey
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("Failed to parse %s private key : %v", name, err)
+		}
+		if decoded.D.Cmp(privateKey.D) != 0 {
+			t.Fatalf("Private key of %s changed after round trip", name)
+		}
+
+		var publicKey Point
+		if err := json.Unmarshal(encoded, &publicKey); err != nil {
+			t.Fatalf("Failed to parse %s public key : %v", name, err)
+		}
+		if publicKey.X.Cmp(privateKey.PublicKey.X) != 0 || publicKey.Y.Cmp(privateKey.PublicKey.Y) != 0 {
+			t.Fatalf("Public key of %s changed after round trip", name)
+		}
+	}
+}
+
+func TestJWK_RejectsInvalidKeys(t *testing.T) {
+	invalid := []string{
+		// wrong key type
+		`{"kty":"RSA","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+		// unknown curve
+		`{"kty":"EC","crv":"P-999","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+		// point not on the curve
+		`{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyQ"}`,
+		// short coordinate
+		`{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7A","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+	}
+	for _, data := range invalid {
+		var publicKey Point
+		if err := json.Unmarshal([]byte(data), &publicKey); err == nil {
+			t.Fatalf("Expected error when parsing %s", data)
+		}
+	}
+
+	// "d" does not belong to "x" and "y"
+	mismatched := strings.Replace(rfc7517PrivateJWK, "870MB6gfuTJ4HtUnUvYMyJpr5eUZNP4Bk43bVdj3eAE", "jpsQnnGQmL-YBIffH1136cLSG3yJ-6j-V3oGmfa-9Fw", 1)
+	var privateKey ECPrivateKey
+	if err := json.Unmarshal([]byte(mismatched), &privateKey); err == nil {
+		t.Fatalf("Expected error for private key not matching public key")
+	}
+}
+
+func TestJWK_Thumbprint(t *testing.T) {
+	var publicKey Point
+	if err := json.Unmarshal([]byte(rfc7517PublicJWK), &publicKey); err != nil {
+		t.Fatalf("Failed to parse RFC 7517 public key : %v", err)
+	}
+	thumbprint, err := publicKey.JWKThumbprint()
+	if err != nil {
+		t.Fatalf("Failed to compute thumbprint : %v", err)
+	}
+	expected := "727f88fd634c0a57a1895a79d62ff4569384356d6ea447ab03cb046a6e619feb"
+	if hex.EncodeToString(thumbprint) != expected {
+		t.Fatalf("Unexpected thumbprint. Expected %s, Observed %x", expected, thumbprint)
+	}
+}
+
+func TestJWK_RejectsAmbiguousPoint(t *testing.T) {
+
+	// x = (b_t - b_r) / (a_r - a_t) lies on brainpoolP256r1 and brainpoolP256t1
+	x, _ := new(big.Int).SetString("91d2d6ceb0e2f9a1f2c0af9d09bfa3fa0869dbdef232f282f0c5926a17429b7f", 16)
+	y, _ := new(big.Int).SetString("9475aa0c644f9f240e310354fc2e4faa1ae35e8bd0231801cfb6a0e916f12875", 16)
+	P := &Point{X: x, Y: y}
+	if !GetBrainpoolP256r1Parameters().IsOnCurve(P) || !GetBrainpoolP256t1Parameters().IsOnCurve(P) {
+		t.Fatalf("Expected the point on both curves")
+	}
+	if _, err := json.Marshal(P); err == nil {
+		t.Fatalf("Expected error for a point on two curves")
+	}
+	if _, err := P.MarshalBinary(); err == nil {
+		t.Fatalf("Expected error for a point on two curves")
+	}
+}
+
+func TestJWK_ECParams(t *testing.T) {
+
+	// Parameters of a curve outside the registry encode their base point as
+	// plain coordinates
+	params := kasTestCurve()
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("Failed to encode parameters : %v", err)
+	}
+	var decoded ECParams
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode %s : %v", data, err)
+	}
+	if !sameCurve(params, &decoded) || decoded.H.Cmp(params.H) != 0 {
+		t.Fatalf("Expected %s, Observed %+v", data, decoded)
+	}
+}
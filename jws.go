@@ -0,0 +1,128 @@
+package ecc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// JWSHeader is the protected header of a JWS signed with SignJWS. Algorithm is
+// filled in from the curve of the signing key.
+type JWSHeader struct {
+	Algorithm string   `json:"alg"`
+	Type      string   `json:"typ,omitempty"`
+	KeyID     string   `json:"kid,omitempty"`
+	Critical  []string `json:"crit,omitempty"`
+}
+
+// SignJWS signs the payload and returns the JWS compact serialization (RFC 7515)
+// of the result. Secp256k1 keys produce ES256K signatures (RFC 8812),
+// Secp256r1, Secp384r1 and Secp521r1 keys ES256, ES384 and ES512 signatures,
+// hashed with the function of the algorithm rather than the Hash of the
+// parameters of the key. The header may be nil.
+func (key *ECPrivateKey) SignJWS(header *JWSHeader, payload []byte) (string, error) {
+	curve := lookupCurve(key.curve)
+	if curve == nil || curve.JWS == "" {
+		return "", errors.New("jws: no JWS algorithm is defined for the curve of the key")
+	}
+
+	protected := JWSHeader{}
+	if header != nil {
+		protected = *header
+	}
+	if protected.Algorithm != "" && protected.Algorithm != curve.JWS {
+		return "", fmt.Errorf("jws: algorithm %q does not match the %s key", protected.Algorithm, curve.Name)
+	}
+	protected.Algorithm = curve.JWS
+
+	encodedHeader, err := json.Marshal(&protected)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	// The algorithm fixes the hash function, so the key is bound to the
+	// registered parameters of its curve, whatever the Hash of its own
+	signer := &ECPrivateKey{D: key.D, curve: curve.Params, PublicKey: key.PublicKey}
+	signature := signer.Sign([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature.rawBytes(key.curve.scalarSize())), nil
+}
+
+// VerifyJWS verifies a JWS compact serialization with the public key and
+// returns its header and payload. Tokens using "none", an algorithm other than
+// the one defined for params, or unknown critical header parameters are
+// rejected. The signature is verified with the hash function of the algorithm.
+func (publicKey *Point) VerifyJWS(token string, params *ECParams) (*JWSHeader, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("jws: token must consist of three parts")
+	}
+
+	encodedHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("jws: invalid header encoding: %w", err)
+	}
+	var header JWSHeader
+	if err := json.Unmarshal(encodedHeader, &header); err != nil {
+		return nil, nil, fmt.Errorf("jws: invalid header: %w", err)
+	}
+	if header.Algorithm == "" || strings.EqualFold(header.Algorithm, "none") {
+		return nil, nil, errors.New("jws: unsigned tokens are not accepted")
+	}
+	curve := lookupCurve(params)
+	if curve == nil || curve.JWS == "" {
+		return nil, nil, errors.New("jws: no JWS algorithm is defined for the curve")
+	}
+	if header.Algorithm != curve.JWS {
+		return nil, nil, fmt.Errorf("jws: algorithm %q cannot be used with a %s key", header.Algorithm, curve.Name)
+	}
+	if len(header.Critical) > 0 {
+		return nil, nil, fmt.Errorf("jws: unsupported critical header parameters %v", header.Critical)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("jws: invalid payload encoding: %w", err)
+	}
+	rawSignature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("jws: invalid signature encoding: %w", err)
+	}
+	signature, err := parseRawSignature(rawSignature, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jws: %w", err)
+	}
+
+	if !params.IsOnCurve(publicKey) {
+		return nil, nil, errors.New("jws: public key is not on the curve")
+	}
+	if !publicKey.Verify([]byte(parts[0]+"."+parts[1]), signature, curve.Params) {
+		return nil, nil, errors.New("jws: invalid signature")
+	}
+	return &header, payload, nil
+}
+
+// rawBytes encodes the signature as the concatenation r || s with both values
+// padded to size bytes, as used by JWS and COSE
+func (signature *ECSignature) rawBytes(size int) []byte {
+	raw := make([]byte, 2*size)
+	signature.r.FillBytes(raw[:size])
+	signature.s.FillBytes(raw[size:])
+	return raw
+}
+
+// parseRawSignature decodes a signature encoded as r || s
+func parseRawSignature(raw []byte, params *ECParams) (*ECSignature, error) {
+	size := params.scalarSize()
+	if len(raw) != 2*size {
+		return nil, fmt.Errorf("signature must be %d bytes, got %d", 2*size, len(raw))
+	}
+	return &ECSignature{
+		r:     new(big.Int).SetBytes(raw[:size]),
+		s:     new(big.Int).SetBytes(raw[size:]),
+		curve: params,
+	}, nil
+}
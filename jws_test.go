@@ -0,0 +1,140 @@
+package ecc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestJWS_RFC7515Example(t *testing.T) {
+	// ES256 example from RFC 7515 appendix A.3
+	var publicKey Point
+	jwk := `{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}`
+	if err := json.Unmarshal([]byte(jwk), &publicKey); err != nil {
+		t.Fatalf("Failed to parse RFC 7515 public key : %v", err)
+	}
+	token := "eyJhbGciOiJFUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q"
+
+	header, payload, err := publicKey.VerifyJWS(token, GetSecp256r1Parameters().ECParams)
+	if err != nil {
+		t.Fatalf("Failed to verify RFC 7515 example : %v", err)
+	}
+	if header.Algorithm != "ES256" {
+		t.Fatalf("Unexpected algorithm. Expected ES256, Observed %s", header.Algorithm)
+	}
+	if !strings.HasPrefix(string(payload), `{"iss":"joe",`) {
+		t.Fatalf("Unexpected payload %q", payload)
+	}
+}
+
+func TestJWS_SignAndVerify(t *testing.T) {
+	curves := map[string]*ECParams{
+		"ES256K": GetSecp256k1Parametes().ECParams,
+		"ES256":  GetSecp256r1Parameters().ECParams,
+		"ES384":  GetSecp384r1Parameters().ECParams,
+		"ES512":  GetSecp521r1Parameters().ECParams,
+	}
+	k, _ := new(big.Int).SetString("71f25609dcec384ebc6655ef856242cb36e2f80c1092ceb21d32e3caad9c9d16", 16)
+	for alg, params := range curves {
+		privateKey := CreatePrivateKeyFromScalar(params, k)
+		publicKey := privateKey.GeneratePublicKey()
+
+		token, err := privateKey.SignJWS(&JWSHeader{Type: "JWT", KeyID: "test"}, []byte(`{"sub":"1234"}`))
+		if err != nil {
+			t.Fatalf("Failed to sign %s token : %v", alg, err)
+		}
+		header, payload, err := publicKey.VerifyJWS(token, params)
+		if err != nil {
+			t.Fatalf("Failed to verify %s token : %v", alg, err)
+		}
+		if header.Algorithm != alg || header.KeyID != "test" || string(payload) != `{"sub":"1234"}` {
+			t.Fatalf("Unexpected header %+v or payload %s", header, payload)
+		}
+
+		// Tamper with the payload
+		parts := strings.Split(token, ".")
+		parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"4321"}`))
+		if _, _, err := publicKey.VerifyJWS(strings.Join(parts, "."), params); err == nil {
+			t.Fatalf("Tampered %s token was accepted", alg)
+		}
+	}
+}
+
+// ES256 always hashes with SHA-256, also for keys whose parameters set
+// another hash function for Sign
+func TestJWS_HashOfAlgorithm(t *testing.T) {
+	params := *GetSecp256r1Parameters().ECParams
+	params.Hash = crypto.SHA512
+	k, _ := new(big.Int).SetString("71f25609dcec384ebc6655ef856242cb36e2f80c1092ceb21d32e3caad9c9d16", 16)
+	privateKey := CreatePrivateKeyFromScalar(&params, k)
+	publicKey := privateKey.GeneratePublicKey()
+
+	token, err := privateKey.SignJWS(nil, []byte("payload"))
+	if err != nil {
+		t.Fatalf("Failed to sign : %v", err)
+	}
+	if _, _, err := publicKey.VerifyJWS(token, &params); err != nil {
+		t.Fatalf("Failed to verify : %v", err)
+	}
+	if _, _, err := publicKey.VerifyJWS(token, GetSecp256r1Parameters().ECParams); err != nil {
+		t.Fatalf("Failed to verify with the default parameters : %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	rawSignature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	stdKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: publicKey.X, Y: publicKey.Y}
+	if !ecdsa.Verify(stdKey, digest[:], new(big.Int).SetBytes(rawSignature[:32]), new(big.Int).SetBytes(rawSignature[32:])) {
+		t.Fatalf("ES256 signature was rejected by crypto/ecdsa")
+	}
+}
+
+func TestJWS_RejectsAlgorithmMismatch(t *testing.T) {
+	k1 := GetSecp256k1Parametes()
+	k, _ := new(big.Int).SetString("71f25609dcec384ebc6655ef856242cb36e2f80c1092ceb21d32e3caad9c9d16", 16)
+	privateKey := CreatePrivateKeyFromScalar(k1.ECParams, k)
+	publicKey := privateKey.GeneratePublicKey()
+
+	token, err := privateKey.SignJWS(nil, []byte("payload"))
+	if err != nil {
+		t.Fatalf("Failed to sign token : %v", err)
+	}
+
+	// ES256K token checked against a P-256 curve
+	if _, _, err := publicKey.VerifyJWS(token, GetSecp256r1Parameters().ECParams); err == nil {
+		t.Fatalf("Expected error for algorithm / curve mismatch")
+	}
+
+	// Header claiming ES256 on a secp256k1 signature
+	parts := strings.Split(token, ".")
+	parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256"}`))
+	if _, _, err := publicKey.VerifyJWS(strings.Join(parts, "."), k1.ECParams); err == nil {
+		t.Fatalf("Expected error for ES256 header on secp256k1 key")
+	}
+
+	// Unsigned token
+	parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	parts[2] = ""
+	if _, _, err := publicKey.VerifyJWS(strings.Join(parts, "."), k1.ECParams); err == nil {
+		t.Fatalf("Expected error for alg none")
+	}
+
+	// Requesting a different algorithm when signing
+	if _, err := privateKey.SignJWS(&JWSHeader{Algorithm: "ES256"}, []byte("payload")); err == nil {
+		t.Fatalf("Expected error when signing secp256k1 key with ES256")
+	}
+
+	// Brainpool curves have no registered JWS algorithm
+	bp := CreatePrivateKeyFromScalar(GetBrainpoolP256t1Parameters().ECParams, k)
+	if _, err := bp.SignJWS(nil, []byte("payload")); err == nil {
+		t.Fatalf("Expected error when signing with brainpoolP256t1")
+	}
+}
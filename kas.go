@@ -0,0 +1,215 @@
+package ecc
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// KASScheme selects a C(2e, 2s) key agreement scheme of NIST SP 800-56A, where
+// both parties contribute a static and an ephemeral key pair
+type KASScheme int
+
+const (
+	// KASFullUnified is the full unified model, C(2e, 2s, ECC CDH), with the
+	// shared secret Z = Ze || Zs of the ephemeral and the static keys
+	KASFullUnified KASScheme = iota
+
+	// KASFullMQV is the full MQV scheme, C(2e, 2s, ECC MQV)
+	KASFullMQV
+)
+
+// KASOptions configures EstablishKey. A nil *KASOptions selects the full
+// unified model with SHA-256 and a 32-byte key.
+type KASOptions struct {
+	Scheme    KASScheme
+	Hash      crypto.Hash // hash of the one-step KDF and of HMAC, SHA-256 when zero
+	KeySize   int         // length of the derived key, 32 when zero
+	FixedInfo []byte      // optional context appended to the FixedInfo of the KDF
+}
+
+// KASParty is what a party learns about its peer: the identifier and the static
+// and ephemeral public keys
+type KASParty struct {
+	ID        []byte
+	Static    *Point
+	Ephemeral *Point
+}
+
+// KASResult is the outcome of EstablishKey. MacTag must be sent to the peer,
+// and the tag received from the peer must pass VerifyMacTag before Key is
+// used.
+type KASResult struct {
+	Key     []byte
+	MacTag  []byte
+	peerTag []byte
+}
+
+// VerifyMacTag reports whether the key confirmation tag of the peer is valid
+func (result *KASResult) VerifyMacTag(tag []byte) bool {
+	return hmac.Equal(tag, result.peerTag)
+}
+
+// isValidPublicKey performs the full public key validation of SP 800-56A
+// section 5.6.2.3.3: Q is on the curve, is not the point at infinity and,
+// for curves with a cofactor, has order N
+func (ec *ECParams) isValidPublicKey(Q *Point) bool {
+	if !ec.IsOnCurve(Q) || (Q.X.Sign() == 0 && Q.Y.Sign() == 0) {
+		return false
+	}
+	if ec.cofactor().Cmp(big.NewInt(1)) == 0 {
+		return true
+	}
+	R := ScalarMult(ec.N, Q, ec)
+	return R.X.Sign() == 0 && R.Y.Sign() == 0
+}
+
+// eccCDH computes the shared secret of the ECC CDH primitive of SP 800-56A
+// section 5.7.1.2, the x-coordinate of h * d * Q
+func eccCDH(ec *ECParams, d *big.Int, Q *Point) ([]byte, error) {
+	P := ScalarMult(new(big.Int).Mul(ec.cofactor(), d), Q, ec)
+	if P.X.Sign() == 0 && P.Y.Sign() == 0 {
+		return nil, errors.New("ecc: shared point is the point at infinity")
+	}
+	return P.X.FillBytes(make([]byte, ec.coordinateSize())), nil
+}
+
+// mqvAssociateValue computes avf(Q) = (x mod 2^ceil(f/2)) + 2^ceil(f/2) with
+// f the bit length of N
+func (ec *ECParams) mqvAssociateValue(Q *Point) *big.Int {
+	half := uint((ec.N.BitLen() + 1) / 2)
+	bit := new(big.Int).Lsh(big.NewInt(1), half)
+	avf := new(big.Int).Mod(Q.X, bit)
+	return avf.Add(avf, bit)
+}
+
+// publicPoint returns the public key of a private key on the curve ec, computing
+// it when missing. ec is passed in because keys built from D alone have no curve.
+func (key *ECPrivateKey) publicPoint(ec *ECParams) *Point {
+	if key.PublicKey == nil || key.PublicKey.X == nil {
+		return ScalarMult(key.D, ec.BasePoint, ec)
+	}
+	return key.PublicKey
+}
+
+// ECMQV computes the shared secret Z of the ECC MQV primitive of SP 800-56A
+// section 5.7.2.3 from the static key and the ephemeral key of this party and
+// the static and ephemeral public keys of the peer:
+//
+//	implicitsig = (de + avf(Qe) * ds) mod n
+//	P = h * implicitsig * (Qe' + avf(Qe') * Qs')
+//
+// Z is the x-coordinate of P. Both peer public keys are fully validated.
+func (key *ECPrivateKey) ECMQV(ephemeral *ECPrivateKey, peerStatic, peerEphemeral *Point) ([]byte, error) {
+	ec := key.curve
+	if ec == nil || !ec.IsValidPrivateKey(key) || !ec.IsValidPrivateKey(ephemeral) {
+		return nil, errors.New("ecc: invalid MQV private key")
+	}
+	if !ec.isValidPublicKey(peerStatic) || !ec.isValidPublicKey(peerEphemeral) {
+		return nil, errors.New("ecc: invalid MQV public key")
+	}
+
+	implicitSig := ec.mqvAssociateValue(ephemeral.publicPoint(ec))
+	implicitSig.Mul(implicitSig, key.D).Add(implicitSig, ephemeral.D).Mod(implicitSig, ec.N)
+
+	Q := addPoints(peerEphemeral, ScalarMult(ec.mqvAssociateValue(peerEphemeral), peerStatic, ec), ec)
+	return eccCDH(ec, implicitSig, Q)
+}
+
+// EstablishKey runs a C(2e, 2s) scheme of SP 800-56A with bilateral key
+// confirmation between this party, holding the static key and the ephemeral
+// key, and the peer. The initiator is party U of the standard and the
+// responder party V.
+//
+// The keying material is derived from Z with the one-step KDF of SP 800-56C,
+// with FixedInfo = len(IDU) || IDU || len(IDV) || IDV || opts.FixedInfo and
+// 32-bit lengths. Its first bytes, as long as the hash output, are the MAC key
+// of key confirmation and the rest is the returned key. The tags are
+//
+//	MacTagU = HMAC(MacKey, "KC_2_U" || IDU || IDV || QeU || QeV)
+//	MacTagV = HMAC(MacKey, "KC_2_V" || IDV || IDU || QeV || QeU)
+//
+// with the ephemeral public keys in the SEC 1 uncompressed form.
+func (key *ECPrivateKey) EstablishKey(initiator bool, ephemeral *ECPrivateKey, id []byte, peer *KASParty, opts *KASOptions) (*KASResult, error) {
+	if opts == nil {
+		opts = &KASOptions{}
+	}
+	h := opts.Hash
+	if h == 0 {
+		h = crypto.SHA256
+	}
+	keySize := opts.KeySize
+	if keySize == 0 {
+		keySize = 32
+	}
+	if peer == nil {
+		return nil, errors.New("ecc: missing key agreement peer")
+	}
+	ec := key.curve
+	if ec == nil || !ec.IsValidPrivateKey(key) || !ec.IsValidPrivateKey(ephemeral) {
+		return nil, errors.New("ecc: invalid key agreement private key")
+	}
+	if !ec.isValidPublicKey(peer.Static) || !ec.isValidPublicKey(peer.Ephemeral) {
+		return nil, errors.New("ecc: invalid key agreement public key")
+	}
+
+	var z []byte
+	switch opts.Scheme {
+	case KASFullUnified:
+		ze, err := eccCDH(ec, ephemeral.D, peer.Ephemeral)
+		if err != nil {
+			return nil, err
+		}
+		zs, err := eccCDH(ec, key.D, peer.Static)
+		if err != nil {
+			return nil, err
+		}
+		z = append(ze, zs...)
+	case KASFullMQV:
+		var err error
+		if z, err = key.ECMQV(ephemeral, peer.Static, peer.Ephemeral); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("ecc: unknown key agreement scheme")
+	}
+
+	// Party U is the initiator, party V the responder
+	idU, idV := id, peer.ID
+	ephemeralU, ephemeralV := ec.marshalUncompressed(ephemeral.publicPoint(ec)), ec.marshalUncompressed(peer.Ephemeral)
+	if !initiator {
+		idU, idV = idV, idU
+		ephemeralU, ephemeralV = ephemeralV, ephemeralU
+	}
+
+	var fixedInfo []byte
+	for _, field := range [][]byte{idU, idV} {
+		fixedInfo = binary.BigEndian.AppendUint32(fixedInfo, uint32(len(field)))
+		fixedInfo = append(fixedInfo, field...)
+	}
+	fixedInfo = append(fixedInfo, opts.FixedInfo...)
+	keyingMaterial, err := OneStepKDF(h, z, fixedInfo, h.Size()+keySize)
+	if err != nil {
+		return nil, err
+	}
+	macKey := keyingMaterial[:h.Size()]
+
+	macTag := func(label string, parts ...[]byte) []byte {
+		mac := hmac.New(h.New, macKey)
+		mac.Write([]byte(label))
+		for _, part := range parts {
+			mac.Write(part)
+		}
+		return mac.Sum(nil)
+	}
+	tagU := macTag("KC_2_U", idU, idV, ephemeralU, ephemeralV)
+	tagV := macTag("KC_2_V", idV, idU, ephemeralV, ephemeralU)
+
+	result := &KASResult{Key: keyingMaterial[h.Size():], MacTag: tagU, peerTag: tagV}
+	if !initiator {
+		result.MacTag, result.peerTag = tagV, tagU
+	}
+	return result, nil
+}
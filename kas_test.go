@@ -0,0 +1,245 @@
+package ecc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+)
+
+// kasTestCurve is y^2 = x^3 + x + 8 over GF(1009), which has 1004 = 4 * 251
+// points, so that the cofactor is taken into account
+func kasTestCurve() *ECParams {
+	return &ECParams{
+		P: big.NewInt(1009), A: big.NewInt(1), B: big.NewInt(8), N: big.NewInt(251), H: big.NewInt(4),
+		BasePoint: &Point{X: big.NewInt(506), Y: big.NewInt(72)},
+	}
+}
+
+// p256TestKeys returns the P-256 private keys with the hex encoded scalars
+func p256TestKeys(t *testing.T, scalars ...string) []*ECPrivateKey {
+	params := GetSecp256r1Parameters().ECParams
+	keys := make([]*ECPrivateKey, len(scalars))
+	for i, d := range scalars {
+		encoded, _ := hex.DecodeString(d)
+		key, err := params.NewPrivateKey(encoded)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// kasTestKeys returns the static and ephemeral keys of parties U and V
+func kasTestKeys(t *testing.T) (staticU, ephemeralU, staticV, ephemeralV *ECPrivateKey) {
+	keys := p256TestKeys(t,
+		"3333333333333333333333333333333333333333333333333333333333333333",
+		"c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		"a6e3c57dd01abe90086538398355dd4c3b17aa873382b0f24d6129493d8aad60",
+		"0f56db78ca460b055c500064824bed999a25aaf48ebb519ac201537b85479813",
+	)
+	return keys[0], keys[1], keys[2], keys[3]
+}
+
+// Regression value for the keys of kasTestKeys, not a CAVS vector
+func TestKAS_ECMQV(t *testing.T) {
+	staticU, ephemeralU, staticV, ephemeralV := kasTestKeys(t)
+	zU, err := staticU.ECMQV(ephemeralU, staticV.PublicKey, ephemeralV.PublicKey)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	zV, err := staticV.ECMQV(ephemeralV, staticU.PublicKey, ephemeralU.PublicKey)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	expected := "f0b14721c8aa1a4e8cdbc40e8b0e1ec5866bc04d2e00492ac36d42fcc24451ad"
+	if hex.EncodeToString(zU) != expected || hex.EncodeToString(zV) != expected {
+		t.Fatalf("Expected %s, Observed %x and %x", expected, zU, zV)
+	}
+}
+
+// TestKAS_CAVPECCCDH checks eccCDH and SharedSecret against the ECC CDH
+// Primitive vectors of the NIST CAVP
+func TestKAS_CAVPECCCDH(t *testing.T) {
+	f, err := os.Open("testdata/kas_ecc_cdh_vectors.txt")
+	if err != nil {
+		t.Fatalf("Failed to open test vectors : %v", err)
+	}
+	defer f.Close()
+
+	var params *ECParams
+	values := map[string]*big.Int{}
+	vectors := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "["):
+			curve, err := LookupCurve(strings.Trim(line, "[]"))
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			params = curve.Params
+			continue
+		}
+		name, value, _ := strings.Cut(line, " = ")
+		values[name], _ = new(big.Int).SetString(value, 16)
+		if name != "ZIUT" {
+			continue
+		}
+
+		vectors++
+		peer := &Point{X: values["QCAVSx"], Y: values["QCAVSy"]}
+		key, err := params.NewPrivateKey(values["dIUT"].FillBytes(make([]byte, params.scalarSize())))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if key.PublicKey.X.Cmp(values["QIUTx"]) != 0 || key.PublicKey.Y.Cmp(values["QIUTy"]) != 0 {
+			t.Fatalf("Expected QIUT %x, Observed %x", values["QIUTx"], key.PublicKey.X)
+		}
+		expected := values["ZIUT"].FillBytes(make([]byte, params.coordinateSize()))
+		z, err := eccCDH(params, key.D, peer)
+		if err != nil || !bytes.Equal(z, expected) {
+			t.Fatalf("Expected %x, Observed %x (%v)", expected, z, err)
+		}
+		if z, err := key.SharedSecret(peer); err != nil || !bytes.Equal(z, expected) {
+			t.Fatalf("Expected %x, Observed %x (%v)", expected, z, err)
+		}
+	}
+	if vectors != 4 {
+		t.Fatalf("Expected 4 vectors, Observed %d", vectors)
+	}
+}
+
+// Regression values for the keys of kasTestKeys, not CAVS vectors. The ECC CDH
+// primitive underneath the full unified model is checked by TestKAS_CAVPECCCDH.
+func TestKAS_EstablishKey(t *testing.T) {
+	staticU, ephemeralU, staticV, ephemeralV := kasTestKeys(t)
+	regressions := []struct {
+		scheme          KASScheme
+		key, tagU, tagV string
+	}{
+		{
+			scheme: KASFullUnified,
+			key:    "e09e1c4cb31b5843b9d70f3d08d3cf595d51213c6e8e6b0bc52040754610caf6",
+			tagU:   "b9b8712eca123ba5e6b60ecf13285b2cab784a99f9f0df67b8d4a1a52beb590d",
+			tagV:   "34c20db6791c4b73cf53fb5c134f1d1654220fba8551702ba6fe826adccf7eb7",
+		},
+		{
+			scheme: KASFullMQV,
+			key:    "03ad436a87cb1e10804f12eca9bafcf1e717119d374a2c3e35f0a122438664c1",
+			tagU:   "1588f576964d17a9297f2755c917b958113632299fc5b61ed1c4c3ab5cec2cd4",
+			tagV:   "e434d808ba91533ff4f6cdca45d8f937ca6d14893f007bbdb29a6fa2150a20a1",
+		},
+	}
+	for _, v := range regressions {
+		opts := &KASOptions{Scheme: v.scheme, FixedInfo: []byte("context")}
+		u, err := staticU.EstablishKey(true, ephemeralU, []byte("alice"), &KASParty{ID: []byte("bob"), Static: staticV.PublicKey, Ephemeral: ephemeralV.PublicKey}, opts)
+		if err != nil {
+			t.Fatalf("Scheme %d: %v", v.scheme, err)
+		}
+		v2, err := staticV.EstablishKey(false, ephemeralV, []byte("bob"), &KASParty{ID: []byte("alice"), Static: staticU.PublicKey, Ephemeral: ephemeralU.PublicKey}, opts)
+		if err != nil {
+			t.Fatalf("Scheme %d: %v", v.scheme, err)
+		}
+		if hex.EncodeToString(u.Key) != v.key || !bytes.Equal(u.Key, v2.Key) {
+			t.Fatalf("Scheme %d: Expected key %s, Observed %x and %x", v.scheme, v.key, u.Key, v2.Key)
+		}
+		if hex.EncodeToString(u.MacTag) != v.tagU {
+			t.Fatalf("Scheme %d: Expected MacTagU %s, Observed %x", v.scheme, v.tagU, u.MacTag)
+		}
+		if hex.EncodeToString(v2.MacTag) != v.tagV {
+			t.Fatalf("Scheme %d: Expected MacTagV %s, Observed %x", v.scheme, v.tagV, v2.MacTag)
+		}
+		if !u.VerifyMacTag(v2.MacTag) || !v2.VerifyMacTag(u.MacTag) {
+			t.Fatalf("Scheme %d: Key confirmation failed", v.scheme)
+		}
+		if u.VerifyMacTag(u.MacTag) {
+			t.Fatalf("Scheme %d: Expected the initiator to reject its own tag", v.scheme)
+		}
+
+		// A different identity changes the key and the tags
+		other, err := staticV.EstablishKey(false, ephemeralV, []byte("bob"), &KASParty{ID: []byte("mallory"), Static: staticU.PublicKey, Ephemeral: ephemeralU.PublicKey}, opts)
+		if err != nil {
+			t.Fatalf("Scheme %d: %v", v.scheme, err)
+		}
+		if bytes.Equal(other.Key, u.Key) || u.VerifyMacTag(other.MacTag) {
+			t.Fatalf("Scheme %d: Expected a different key for a different identity", v.scheme)
+		}
+	}
+}
+
+// Keys built from D alone have no curve and no public key, and must use the
+// curve of the static key
+func TestKAS_KeysWithoutCurve(t *testing.T) {
+	staticU, ephemeralU, staticV, ephemeralV := kasTestKeys(t)
+	bare := &ECPrivateKey{D: ephemeralU.D}
+
+	expected, _ := staticU.ECMQV(ephemeralU, staticV.PublicKey, ephemeralV.PublicKey)
+	z, err := staticU.ECMQV(bare, staticV.PublicKey, ephemeralV.PublicKey)
+	if err != nil || !bytes.Equal(z, expected) {
+		t.Fatalf("Expected %x, Observed %x (%v)", expected, z, err)
+	}
+
+	peer := &KASParty{ID: []byte("bob"), Static: staticV.PublicKey, Ephemeral: ephemeralV.PublicKey}
+	for _, scheme := range []KASScheme{KASFullUnified, KASFullMQV} {
+		opts := &KASOptions{Scheme: scheme}
+		want, _ := staticU.EstablishKey(true, ephemeralU, []byte("alice"), peer, opts)
+		got, err := staticU.EstablishKey(true, bare, []byte("alice"), peer, opts)
+		if err != nil || !bytes.Equal(got.Key, want.Key) || !bytes.Equal(got.MacTag, want.MacTag) {
+			t.Fatalf("Scheme %d: Expected %x, Observed %v (%v)", scheme, want.Key, got, err)
+		}
+	}
+}
+
+func TestKAS_Cofactor(t *testing.T) {
+	params := kasTestCurve()
+	for _, scheme := range []KASScheme{KASFullUnified, KASFullMQV} {
+		agreed := 0
+		for i := 0; i < 20; i++ {
+			keys := make([]*ECPrivateKey, 4)
+			for j := range keys {
+				keys[j], _ = params.GeneratePrivateKey(nil)
+			}
+			opts := &KASOptions{Scheme: scheme}
+			u, err := keys[0].EstablishKey(true, keys[1], nil, &KASParty{Static: keys[2].PublicKey, Ephemeral: keys[3].PublicKey}, opts)
+			if err != nil {
+				continue // Z may be the point at infinity on such a small curve
+			}
+			v, err := keys[2].EstablishKey(false, keys[3], nil, &KASParty{Static: keys[0].PublicKey, Ephemeral: keys[1].PublicKey}, opts)
+			if err != nil {
+				t.Fatalf("Scheme %d: %v", scheme, err)
+			}
+			if !bytes.Equal(u.Key, v.Key) || !u.VerifyMacTag(v.MacTag) {
+				t.Fatalf("Scheme %d: Expected the parties to agree", scheme)
+			}
+			agreed++
+		}
+		if agreed == 0 {
+			t.Fatalf("Scheme %d: Expected at least one key agreement to succeed", scheme)
+		}
+	}
+
+	// Points of order 2 and of order 2 * 251 fail the full validation
+	key, _ := params.GeneratePrivateKey(nil)
+	ephemeral, _ := params.GeneratePrivateKey(nil)
+	peer, _ := params.GeneratePrivateKey(nil)
+	small := &Point{X: big.NewInt(513), Y: big.NewInt(0)}
+	for _, Q := range []*Point{small, addPoints(peer.PublicKey, small, params)} {
+		if !params.IsOnCurve(Q) {
+			t.Fatalf("Expected %v to be on the curve", Q)
+		}
+		if _, err := key.ECMQV(ephemeral, Q, peer.PublicKey); err == nil {
+			t.Fatalf("Expected an error for the static public key %v", Q)
+		}
+		if _, err := key.EstablishKey(true, ephemeral, nil, &KASParty{Static: peer.PublicKey, Ephemeral: Q}, nil); err == nil {
+			t.Fatalf("Expected an error for the ephemeral public key %v", Q)
+		}
+	}
+}
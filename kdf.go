@@ -0,0 +1,189 @@
+package ecc
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// KDF selects the key derivation function applied to an ECDH shared secret
+type KDF int
+
+const (
+	// KDFHKDF is HKDF of RFC 5869, with optional salt and info
+	KDFHKDF KDF = iota
+
+	// KDFOneStep is the one-step key derivation function of NIST SP 800-56C
+	// with a hash function (option 1), also known as the Concat KDF, with
+	// FixedInfo taken from Info
+	KDFOneStep
+
+	// KDFX963 is the ANSI X9.63 key derivation function of SEC 1, with
+	// SharedInfo taken from Info
+	KDFX963
+)
+
+// KDFOptions configures DeriveSharedKey. A nil *KDFOptions selects HKDF with
+// SHA-256, no salt and no info.
+type KDFOptions struct {
+	KDF  KDF
+	Hash crypto.Hash // SHA-256 when zero
+	Salt []byte      // HKDF salt, only allowed with KDFHKDF
+	Info []byte      // HKDF info, FixedInfo or SharedInfo
+}
+
+// SharedSecret computes the ECDH shared secret Z of SP 800-56A, the x-coordinate
+// of the shared point as a big-endian integer of the size of a field element.
+// The public key must be on the curve of the private key and must not have
+// small order, and the shared point must not be the point at infinity.
+//
+// Z is not uniformly distributed and should not be used as a key directly;
+// DeriveSharedKey runs it through a key derivation function.
+func (key *ECPrivateKey) SharedSecret(publicKey *Point) ([]byte, error) {
+	ec := key.curve
+	if ec == nil || !ec.IsValidPrivateKey(key) {
+		return nil, errors.New("ecc: invalid private key")
+	}
+	if !ec.IsOnCurve(publicKey) || ec.hasSmallOrder(publicKey) {
+		return nil, errors.New("ecc: invalid public key")
+	}
+	S := key.ECDH(publicKey)
+	if S.X.Sign() == 0 && S.Y.Sign() == 0 {
+		return nil, errors.New("ecc: shared point is the point at infinity")
+	}
+	return S.X.FillBytes(make([]byte, ec.coordinateSize())), nil
+}
+
+// DeriveSharedKey computes the ECDH shared secret with the public key and
+// derives a key of the given length from it with the KDF of opts
+func (key *ECPrivateKey) DeriveSharedKey(publicKey *Point, length int, opts *KDFOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &KDFOptions{}
+	}
+	h := opts.Hash
+	if h == 0 {
+		h = crypto.SHA256
+	}
+	if opts.KDF != KDFHKDF && opts.Salt != nil {
+		return nil, errors.New("ecc: salt is only used by HKDF")
+	}
+	z, err := key.SharedSecret(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	switch opts.KDF {
+	case KDFHKDF:
+		return HKDF(h, z, opts.Salt, opts.Info, length)
+	case KDFOneStep:
+		return OneStepKDF(h, z, opts.Info, length)
+	case KDFX963:
+		return X963KDF(h, z, opts.Info, length)
+	}
+	return nil, errors.New("ecc: unknown key derivation function")
+}
+
+// checkKDF checks the hash function and the output length of a KDF
+func checkKDF(h crypto.Hash, length int) error {
+	if !h.Available() {
+		return errors.New("ecc: hash function of the KDF is not available")
+	}
+	if length <= 0 {
+		return errors.New("ecc: KDF output length must be positive")
+	}
+	return nil
+}
+
+// OneStepKDF derives length bytes from the shared secret z with the one-step
+// key derivation function of NIST SP 800-56C revision 2 section 4.1, option 1,
+// the concatenation of Hash(counter || z || fixedInfo) for a 32-bit counter
+// from 1
+func OneStepKDF(h crypto.Hash, z, fixedInfo []byte, length int) ([]byte, error) {
+	if err := checkKDF(h, length); err != nil {
+		return nil, err
+	}
+	return counterKDF(h.New, z, fixedInfo, length, true)
+}
+
+// X963KDF derives length bytes from the shared secret z with the key
+// derivation function of ANSI X9.63, the concatenation of
+// Hash(z || counter || sharedInfo) for a 32-bit counter from 1
+func X963KDF(h crypto.Hash, z, sharedInfo []byte, length int) ([]byte, error) {
+	if err := checkKDF(h, length); err != nil {
+		return nil, err
+	}
+	return x963KDF(h.New, z, sharedInfo, length)
+}
+
+// HKDF derives length bytes from the secret with HKDF of RFC 5869, extracting
+// with the salt and expanding with the info
+func HKDF(h crypto.Hash, secret, salt, info []byte, length int) ([]byte, error) {
+	if err := checkKDF(h, length); err != nil {
+		return nil, err
+	}
+	return hkdfExpand(h.New, hkdfExtract(h.New, salt, secret), info, length)
+}
+
+// x963KDF derives length bytes from the shared secret z with the key
+// derivation function of ANSI X9.63 (SEC 1 section 3.6.1)
+func x963KDF(h func() hash.Hash, z, sharedInfo []byte, length int) ([]byte, error) {
+	return counterKDF(h, z, sharedInfo, length, false)
+}
+
+// counterKDF concatenates the hashes of z, a 32-bit big-endian counter from 1
+// and info, with the counter before z when counterFirst is set (SP 800-56C)
+// and after it otherwise (X9.63)
+func counterKDF(h func() hash.Hash, z, info []byte, length int, counterFirst bool) ([]byte, error) {
+	hasher := h()
+	if uint64(length) > uint64(hasher.Size())*(1<<32-1) {
+		return nil, errors.New("ecc: KDF output is too long")
+	}
+	out := make([]byte, 0, length+hasher.Size())
+	var counter [4]byte
+	for i := uint32(1); len(out) < length; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		hasher.Reset()
+		if counterFirst {
+			hasher.Write(counter[:])
+			hasher.Write(z)
+		} else {
+			hasher.Write(z)
+			hasher.Write(counter[:])
+		}
+		hasher.Write(info)
+		out = hasher.Sum(out)
+	}
+	return out[:length], nil
+}
+
+// hkdfExtract computes the pseudorandom key HMAC-Hash(salt, ikm) of RFC 5869
+// section 2.2. An empty salt is replaced by a string of zeros of the hash size.
+func hkdfExtract(h func() hash.Hash, salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, h().Size())
+	}
+	mac := hmac.New(h, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// hkdfExpand expands the pseudorandom key prk into length bytes bound to info,
+// as described in RFC 5869 section 2.3
+func hkdfExpand(h func() hash.Hash, prk, info []byte, length int) ([]byte, error) {
+	mac := hmac.New(h, prk)
+	if length > 255*mac.Size() {
+		return nil, errors.New("ecc: HKDF output is too long")
+	}
+	out := make([]byte, 0, length+mac.Size())
+	var t []byte
+	for i := byte(1); len(out) < length; i++ {
+		mac.Reset()
+		mac.Write(t)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		t = mac.Sum(nil)
+		out = append(out, t...)
+	}
+	return out[:length], nil
+}
@@ -0,0 +1,184 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// Vectors of the NIST CAVS ANSI X9.63 KDF test file for SHA-256
+func TestKDF_X963(t *testing.T) {
+	vectors := []struct {
+		z, sharedInfo, key string
+	}{
+		{
+			z:   "96c05619d56c328ab95fe84b18264b08725b85e33fd34f08",
+			key: "443024c3dae66b95e6f5670601558f71",
+		},
+		{
+			z:          "22518b10e70f2a3f243810ae3254139efbee04aa57c7af7d",
+			sharedInfo: "75eef81aa3041e33b80971203d2c0c52",
+			key:        "c498af77161cc59f2962b9a713e2b215152d139766ce34a776df11866a69bf2e52a13d9c7c6fc878c50c5ea0bc7b00e0da2447cfd874f6cf92f30d0097111485500c90c3af8b487872d04685d14c8d1dc8d7fa08beb0ce0ababc11f0bd496269142d43525a78e5bc79a17f59676a5706dc54d54d4d1f0bd7e386128ec26afc21",
+		},
+	}
+	for i, v := range vectors {
+		z, _ := hex.DecodeString(v.z)
+		sharedInfo, _ := hex.DecodeString(v.sharedInfo)
+		expected, _ := hex.DecodeString(v.key)
+		key, err := X963KDF(crypto.SHA256, z, sharedInfo, len(expected))
+		if err != nil {
+			t.Fatalf("Vector %d: %v", i, err)
+		}
+		if !bytes.Equal(key, expected) {
+			t.Fatalf("Vector %d: Expected %x, Observed %x", i, expected, key)
+		}
+	}
+}
+
+// RFC 5869 appendix A.1 and A.3
+func TestKDF_HKDF(t *testing.T) {
+	vectors := []struct {
+		ikm, salt, info, prk, okm string
+	}{
+		{
+			ikm:  "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			salt: "000102030405060708090a0b0c",
+			info: "f0f1f2f3f4f5f6f7f8f9",
+			prk:  "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5",
+			okm:  "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		},
+		{
+			ikm: "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			prk: "19ef24a32c717b167f33a91d6f648bdf96596776afdb6377ac434c1c293ccb04",
+			okm: "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		},
+	}
+	for i, v := range vectors {
+		ikm, _ := hex.DecodeString(v.ikm)
+		salt, _ := hex.DecodeString(v.salt)
+		info, _ := hex.DecodeString(v.info)
+		prk := hkdfExtract(sha256.New, salt, ikm)
+		if hex.EncodeToString(prk) != v.prk {
+			t.Fatalf("Vector %d: Expected PRK %s, Observed %x", i, v.prk, prk)
+		}
+		okm, err := hkdfExpand(sha256.New, prk, info, len(v.okm)/2)
+		if err != nil {
+			t.Fatalf("Vector %d: %v", i, err)
+		}
+		if hex.EncodeToString(okm) != v.okm {
+			t.Fatalf("Vector %d: Expected OKM %s, Observed %x", i, v.okm, okm)
+		}
+	}
+	if _, err := hkdfExpand(sha256.New, make([]byte, 32), nil, 255*32+1); err == nil {
+		t.Fatalf("Expected an error for an HKDF output longer than 255 blocks")
+	}
+}
+
+// RFC 7518 appendix C, the Concat KDF of ECDH-ES for A128GCM
+func TestKDF_OneStep(t *testing.T) {
+	z, _ := hex.DecodeString("9e56d91d817135d372834283bf84269cfb316ea3da806a48f6daa7798cfe90c4")
+	var fixedInfo []byte
+	for _, field := range []string{"A128GCM", "Alice", "Bob"} {
+		fixedInfo = binary.BigEndian.AppendUint32(fixedInfo, uint32(len(field)))
+		fixedInfo = append(fixedInfo, field...)
+	}
+	fixedInfo = binary.BigEndian.AppendUint32(fixedInfo, 128)
+
+	key, err := OneStepKDF(crypto.SHA256, z, fixedInfo, 16)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if expected := "56aa8deaf8236d205c2228cd71a7101a"; hex.EncodeToString(key) != expected {
+		t.Fatalf("Expected %s, Observed %x", expected, key)
+	}
+	if _, err := OneStepKDF(crypto.SHA256, z, fixedInfo, 0); err == nil {
+		t.Fatalf("Expected an error for an empty output")
+	}
+}
+
+func TestKDF_SharedSecret(t *testing.T) {
+	for _, curve := range allCurves() {
+		params := curve.Params()
+		alice, _ := params.GeneratePrivateKey(nil)
+		bob, _ := params.GeneratePrivateKey(nil)
+		z1, err := alice.SharedSecret(bob.PublicKey)
+		if err != nil {
+			t.Fatalf("%s: %v", curve.Name(), err)
+		}
+		z2, err := bob.SharedSecret(alice.PublicKey)
+		if err != nil {
+			t.Fatalf("%s: %v", curve.Name(), err)
+		}
+		if !bytes.Equal(z1, z2) {
+			t.Fatalf("%s: Expected %x, Observed %x", curve.Name(), z1, z2)
+		}
+		if len(z1) != params.coordinateSize() {
+			t.Fatalf("%s: Expected %d bytes, Observed %d", curve.Name(), params.coordinateSize(), len(z1))
+		}
+		if expected := alice.ECDH(bob.PublicKey).X.FillBytes(make([]byte, len(z1))); !bytes.Equal(z1, expected) {
+			t.Fatalf("%s: Expected %x, Observed %x", curve.Name(), expected, z1)
+		}
+	}
+
+	params := GetSecp256r1Parameters().ECParams
+	key, _ := params.GeneratePrivateKey(nil)
+	if _, err := key.SharedSecret(&Point{X: big.NewInt(1), Y: big.NewInt(1)}); err == nil {
+		t.Fatalf("Expected an error for a public key off the curve")
+	}
+	if _, err := key.SharedSecret(&Point{X: big.NewInt(0), Y: big.NewInt(0)}); err == nil {
+		t.Fatalf("Expected an error for the point at infinity")
+	}
+	other, _ := GetSecp256k1Parametes().GeneratePrivateKey(nil)
+	if _, err := key.SharedSecret(other.PublicKey); err == nil {
+		t.Fatalf("Expected an error for a public key on another curve")
+	}
+}
+
+func TestKDF_DeriveSharedKey(t *testing.T) {
+	params := GetSecp384r1Parameters().ECParams
+	alice, _ := params.GeneratePrivateKey(nil)
+	bob, _ := params.GeneratePrivateKey(nil)
+	z, _ := alice.SharedSecret(bob.PublicKey)
+	salt, info := []byte("salt"), []byte("info")
+
+	hkdf, _ := HKDF(crypto.SHA384, z, salt, info, 48)
+	oneStep, _ := OneStepKDF(crypto.SHA256, z, info, 40)
+	x963, _ := X963KDF(crypto.SHA512, z, info, 80)
+	defaultKey, _ := HKDF(crypto.SHA256, z, nil, nil, 32)
+	vectors := []struct {
+		opts     *KDFOptions
+		length   int
+		expected []byte
+	}{
+		{nil, 32, defaultKey},
+		{&KDFOptions{Hash: crypto.SHA384, Salt: salt, Info: info}, 48, hkdf},
+		{&KDFOptions{KDF: KDFOneStep, Info: info}, 40, oneStep},
+		{&KDFOptions{KDF: KDFX963, Hash: crypto.SHA512, Info: info}, 80, x963},
+	}
+	for i, v := range vectors {
+		for _, key := range []*ECPrivateKey{alice, bob} {
+			peer := bob.PublicKey
+			if key == bob {
+				peer = alice.PublicKey
+			}
+			derived, err := key.DeriveSharedKey(peer, v.length, v.opts)
+			if err != nil {
+				t.Fatalf("Vector %d: %v", i, err)
+			}
+			if !bytes.Equal(derived, v.expected) {
+				t.Fatalf("Vector %d: Expected %x, Observed %x", i, v.expected, derived)
+			}
+		}
+	}
+
+	if _, err := alice.DeriveSharedKey(bob.PublicKey, 32, &KDFOptions{KDF: KDFX963, Salt: salt}); err == nil {
+		t.Fatalf("Expected an error for a salt with the X9.63 KDF")
+	}
+	if _, err := alice.DeriveSharedKey(bob.PublicKey, 255*32+1, nil); err == nil {
+		t.Fatalf("Expected an error for an HKDF output longer than 255 blocks")
+	}
+}
@@ -0,0 +1,109 @@
+package ecc
+
+import (
+	"io"
+	"strings"
+)
+
+// KeyAgreement is a Diffie-Hellman function on encoded keys. It is implemented
+// for X25519, X448 and for every Weierstrass curve, so protocols can be written once
+// and choose the curve by name with NewKeyAgreement.
+type KeyAgreement interface {
+	// Name returns the name of the function, e.g. "X25519" or "P-256"
+	Name() string
+
+	// GenerateKey returns a new private key and its public key, reading from
+	// crypto/rand when rand is nil
+	GenerateKey(rand io.Reader) (privateKey, publicKey []byte, err error)
+
+	// PublicKey returns the public key of a private key
+	PublicKey(privateKey []byte) ([]byte, error)
+
+	// SharedSecret computes the shared secret of a private key and the public
+	// key of the peer
+	SharedSecret(privateKey, peerPublicKey []byte) ([]byte, error)
+}
+
+// NewKeyAgreement returns the key agreement for "X25519", "X448" or for any
+// curve known to LookupCurve
+func NewKeyAgreement(name string) (KeyAgreement, error) {
+	for _, curve := range []*montgomeryCurve{curve25519, curve448} {
+		if strings.EqualFold(name, curve.name) {
+			return montgomeryKeyAgreement{curve}, nil
+		}
+	}
+	curve, err := LookupCurve(name)
+	if err != nil {
+		return nil, err
+	}
+	return curve.Params.KeyAgreement(), nil
+}
+
+// montgomeryKeyAgreement implements KeyAgreement with the little-endian
+// scalars and u-coordinates of RFC 7748
+type montgomeryKeyAgreement struct {
+	curve *montgomeryCurve
+}
+
+func (ka montgomeryKeyAgreement) Name() string {
+	return ka.curve.name
+}
+
+func (ka montgomeryKeyAgreement) GenerateKey(rand io.Reader) ([]byte, []byte, error) {
+	return ka.curve.generateKey(rand)
+}
+
+func (ka montgomeryKeyAgreement) PublicKey(privateKey []byte) ([]byte, error) {
+	return ka.curve.scalarMult(privateKey, ka.curve.base)
+}
+
+func (ka montgomeryKeyAgreement) SharedSecret(privateKey, peerPublicKey []byte) ([]byte, error) {
+	return ka.curve.scalarMult(privateKey, peerPublicKey)
+}
+
+// weierstrassKeyAgreement implements KeyAgreement for ECParams. Private keys
+// are fixed-length big-endian scalars, public keys SEC 1 uncompressed points
+// and shared secrets the fixed-length x-coordinate of the shared point.
+type weierstrassKeyAgreement struct {
+	ec *ECParams
+}
+
+// KeyAgreement returns ECDH on the curve as a KeyAgreement
+func (ec *ECParams) KeyAgreement() KeyAgreement {
+	return weierstrassKeyAgreement{ec}
+}
+
+func (ka weierstrassKeyAgreement) Name() string {
+	if curve := lookupCurve(ka.ec); curve != nil {
+		return curve.Name
+	}
+	return "custom"
+}
+
+func (ka weierstrassKeyAgreement) GenerateKey(rand io.Reader) ([]byte, []byte, error) {
+	privateKey, err := ka.ec.GeneratePrivateKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey.D.FillBytes(make([]byte, ka.ec.scalarSize())), ka.ec.marshalUncompressed(privateKey.PublicKey), nil
+}
+
+func (ka weierstrassKeyAgreement) PublicKey(privateKey []byte) ([]byte, error) {
+	key, err := ka.ec.NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return ka.ec.marshalUncompressed(key.PublicKey), nil
+}
+
+func (ka weierstrassKeyAgreement) SharedSecret(privateKey, peerPublicKey []byte) ([]byte, error) {
+	key, err := ka.ec.NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := ka.ec.NewPublicKey(peerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return key.SharedSecret(peer)
+}
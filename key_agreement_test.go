@@ -0,0 +1,63 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestKeyAgreement_AllCurves(t *testing.T) {
+	for _, name := range []string{"X25519", "X448", "P-256", "secp256k1", "P-521", "brainpoolP256r1"} {
+		ka, err := NewKeyAgreement(name)
+		if err != nil {
+			t.Fatalf("Failed to create key agreement %s : %v", name, err)
+		}
+		if ka.Name() != name {
+			t.Fatalf("Expected name %s, Observed %s", name, ka.Name())
+		}
+
+		alicePrivate, alicePublic, err := ka.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("%s: failed to generate key : %v", name, err)
+		}
+		bobPrivate, bobPublic, _ := ka.GenerateKey(nil)
+
+		derived, err := ka.PublicKey(alicePrivate)
+		if err != nil || !bytes.Equal(derived, alicePublic) {
+			t.Fatalf("%s: public key does not match generated key : %v", name, err)
+		}
+
+		shared1, err := ka.SharedSecret(alicePrivate, bobPublic)
+		if err != nil {
+			t.Fatalf("%s: failed to compute shared secret : %v", name, err)
+		}
+		shared2, _ := ka.SharedSecret(bobPrivate, alicePublic)
+		if !bytes.Equal(shared1, shared2) {
+			t.Fatalf("%s: shared secrets differ. %x, %x", name, shared1, shared2)
+		}
+
+		invalid := append([]byte(nil), bobPublic...)
+		invalid = invalid[:len(invalid)-1]
+		if _, err := ka.SharedSecret(alicePrivate, invalid); err == nil {
+			t.Fatalf("%s: expected error for a truncated public key", name)
+		}
+	}
+
+	if _, err := NewKeyAgreement("X999"); err == nil {
+		t.Fatalf("Expected error for an unknown key agreement")
+	}
+}
+
+func TestKeyAgreement_MatchesECDH(t *testing.T) {
+	params := GetSecp256r1Parameters().ECParams
+	alice, _ := params.GeneratePrivateKey(rand.Reader)
+	bob, _ := params.GeneratePrivateKey(rand.Reader)
+
+	shared, err := params.KeyAgreement().SharedSecret(alice.D.FillBytes(make([]byte, 32)), params.marshalUncompressed(bob.PublicKey))
+	if err != nil {
+		t.Fatalf("Failed to compute shared secret : %v", err)
+	}
+	if expected := alice.ECDH(bob.PublicKey).X.FillBytes(make([]byte, 32)); !bytes.Equal(shared, expected) {
+		t.Fatalf("Expected %x, Observed %x", expected, shared)
+	}
+}
@@ -0,0 +1,440 @@
+package ecc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// noiseMaxMessageSize is the maximum size of a Noise message
+const noiseMaxMessageSize = 65535
+
+// NoisePattern is a Noise handshake pattern: the pre-messages and the tokens
+// of every handshake message, the first one sent by the initiator
+type NoisePattern struct {
+	Name         string
+	initiatorPre []string
+	responderPre []string
+	messages     [][]string
+}
+
+// Interactive handshake patterns of the Noise specification, section 7.4
+var (
+	NoiseNN = &NoisePattern{
+		Name:     "NN",
+		messages: [][]string{{"e"}, {"e", "ee"}},
+	}
+	NoiseNK = &NoisePattern{
+		Name:         "NK",
+		responderPre: []string{"s"},
+		messages:     [][]string{{"e", "es"}, {"e", "ee"}},
+	}
+	NoiseXX = &NoisePattern{
+		Name:     "XX",
+		messages: [][]string{{"e"}, {"e", "ee", "s", "es"}, {"s", "se"}},
+	}
+	NoiseIK = &NoisePattern{
+		Name:         "IK",
+		responderPre: []string{"s"},
+		messages:     [][]string{{"e", "es", "s", "ss"}, {"e", "ee", "se"}},
+	}
+)
+
+// noiseDH maps the DH names of Noise protocol names to key agreements and
+// their public key size DHLEN. The Weierstrass curves use SEC 1 uncompressed
+// public keys and the x-coordinate of the shared point as DH output.
+var noiseDH = map[string]struct {
+	keyAgreement string
+	size         int
+}{
+	"25519":     {"X25519", 32},
+	"448":       {"X448", 56},
+	"secp256k1": {"secp256k1", 65},
+	"P256":      {"P-256", 65},
+}
+
+// NoiseConfig configures a Noise handshake with SHA-256 as hash function. The
+// P256 and secp256k1 DH functions are not defined by the Noise specification
+// and are this package's own choice: they send 65-byte SEC 1 uncompressed
+// public keys but use the 32-byte x-coordinate of the shared point as DH
+// output, whereas section 4.1 requires DHLEN to be the length of both. Other
+// implementations of these names will not interoperate; in particular
+// secp256k1 differs from the Lightning Network handshake of BOLT 8, which
+// sends compressed public keys and hashes the compressed shared point.
+type NoiseConfig struct {
+	Pattern      *NoisePattern
+	DH           string // "25519", "448", "secp256k1" or "P256"
+	Cipher       string // "ChaChaPoly" or "AESGCM"
+	Initiator    bool
+	Prologue     []byte
+	StaticKey    []byte    // private key of the local static key pair, when the pattern sends or pre-shares it
+	RemoteStatic []byte    // public key of the peer, when the pattern pre-shares it
+	Rand         io.Reader // source of the ephemeral keys, crypto/rand when nil
+}
+
+// NoiseCipherState is a CipherState of the Noise specification: an AEAD key
+// and a 64-bit nonce incremented after every message
+type NoiseCipherState struct {
+	cipher string
+	aead   cipher.AEAD
+	n      uint64
+}
+
+// newNoiseCipherState returns a CipherState with the 32-byte key k
+func newNoiseCipherState(cipherName string, k []byte) (*NoiseCipherState, error) {
+	cs := &NoiseCipherState{cipher: cipherName}
+	var err error
+	switch cipherName {
+	case "ChaChaPoly":
+		cs.aead, err = newChaCha20Poly1305(k)
+	case "AESGCM":
+		var block cipher.Block
+		if block, err = aes.NewCipher(k); err == nil {
+			cs.aead, err = cipher.NewGCM(block)
+		}
+	default:
+		err = errors.New("noise: unsupported cipher " + cipherName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// nonce encodes n after four zero bytes, little-endian for ChaChaPoly and
+// big-endian for AESGCM
+func (cs *NoiseCipherState) nonce() ([]byte, error) {
+	if cs.n == 1<<64-1 {
+		return nil, errors.New("noise: nonce exhausted")
+	}
+	nonce := make([]byte, 12)
+	if cs.cipher == "ChaChaPoly" {
+		binary.LittleEndian.PutUint64(nonce[4:], cs.n)
+	} else {
+		binary.BigEndian.PutUint64(nonce[4:], cs.n)
+	}
+	return nonce, nil
+}
+
+// Encrypt encrypts the plaintext with the associated data ad and increments
+// the nonce
+func (cs *NoiseCipherState) Encrypt(ad, plaintext []byte) ([]byte, error) {
+	nonce, err := cs.nonce()
+	if err != nil {
+		return nil, err
+	}
+	cs.n++
+	return cs.aead.Seal(nil, nonce, plaintext, ad), nil
+}
+
+// Decrypt decrypts the ciphertext with the associated data ad and increments
+// the nonce when it is authentic
+func (cs *NoiseCipherState) Decrypt(ad, ciphertext []byte) ([]byte, error) {
+	nonce, err := cs.nonce()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := cs.aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, errors.New("noise: message authentication failed")
+	}
+	cs.n++
+	return plaintext, nil
+}
+
+// NoiseHandshake is the HandshakeState of the Noise specification. Messages
+// are written and read alternately, starting with a message written by the
+// initiator, until Complete reports true; Transport then returns the cipher
+// states of the transport phase.
+type NoiseHandshake struct {
+	config  NoiseConfig
+	ka      KeyAgreement
+	dhLen   int
+	ck, h   []byte
+	cs      *NoiseCipherState // nil until the first MixKey
+	s, e    []byte            // local private keys
+	sPublic []byte
+	rs, re  []byte
+	turn    int
+	send    *NoiseCipherState
+	receive *NoiseCipherState
+}
+
+// NewNoiseHandshake initializes a handshake with the protocol name
+// Noise_<pattern>_<DH>_<cipher>_SHA256
+func NewNoiseHandshake(config *NoiseConfig) (*NoiseHandshake, error) {
+	if config.Pattern == nil {
+		return nil, errors.New("noise: missing handshake pattern")
+	}
+	dh, ok := noiseDH[config.DH]
+	if !ok {
+		return nil, errors.New("noise: unsupported DH function " + config.DH)
+	}
+	ka, err := NewKeyAgreement(dh.keyAgreement)
+	if err != nil {
+		return nil, err
+	}
+	if config.Cipher != "ChaChaPoly" && config.Cipher != "AESGCM" {
+		return nil, errors.New("noise: unsupported cipher " + config.Cipher)
+	}
+	hs := &NoiseHandshake{config: *config, ka: ka, dhLen: dh.size}
+	if hs.config.Rand == nil {
+		hs.config.Rand = cryptorand.Reader
+	}
+	if len(config.StaticKey) > 0 {
+		hs.s = config.StaticKey
+		if hs.sPublic, err = ka.PublicKey(config.StaticKey); err != nil {
+			return nil, err
+		}
+	}
+	hs.rs = config.RemoteStatic
+
+	// InitializeSymmetric
+	name := []byte("Noise_" + config.Pattern.Name + "_" + config.DH + "_" + config.Cipher + "_SHA256")
+	if len(name) <= sha256.Size {
+		hs.h = append(name, make([]byte, sha256.Size-len(name))...)
+	} else {
+		sum := sha256.Sum256(name)
+		hs.h = sum[:]
+	}
+	hs.ck = append([]byte{}, hs.h...)
+	hs.mixHash(config.Prologue)
+
+	// Pre-messages, the ones of the initiator first
+	for _, pre := range []struct {
+		tokens []string
+		local  bool
+	}{
+		{config.Pattern.initiatorPre, config.Initiator},
+		{config.Pattern.responderPre, !config.Initiator},
+	} {
+		for _, token := range pre.tokens {
+			key := hs.rs
+			if pre.local {
+				key = hs.sPublic
+			}
+			if token != "s" || len(key) != hs.dhLen {
+				return nil, errors.New("noise: missing pre-message static key")
+			}
+			hs.mixHash(key)
+		}
+	}
+	return hs, nil
+}
+
+func (hs *NoiseHandshake) mixHash(data []byte) {
+	h := sha256.New()
+	h.Write(hs.h)
+	h.Write(data)
+	hs.h = h.Sum(nil)
+}
+
+// hkdf returns the two outputs of HKDF(ck, ikm) of the Noise specification
+func (hs *NoiseHandshake) hkdf(ikm []byte) ([]byte, []byte) {
+	output, _ := hkdfExpand(sha256.New, hkdfExtract(sha256.New, hs.ck, ikm), nil, 2*sha256.Size)
+	return output[:sha256.Size], output[sha256.Size:]
+}
+
+func (hs *NoiseHandshake) mixKey(ikm []byte) error {
+	var k []byte
+	hs.ck, k = hs.hkdf(ikm)
+	cs, err := newNoiseCipherState(hs.config.Cipher, k)
+	if err != nil {
+		return err
+	}
+	hs.cs = cs
+	return nil
+}
+
+func (hs *NoiseHandshake) encryptAndHash(plaintext []byte) ([]byte, error) {
+	ciphertext := plaintext
+	if hs.cs != nil {
+		var err error
+		if ciphertext, err = hs.cs.Encrypt(hs.h, plaintext); err != nil {
+			return nil, err
+		}
+	}
+	hs.mixHash(ciphertext)
+	return ciphertext, nil
+}
+
+func (hs *NoiseHandshake) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	plaintext := ciphertext
+	if hs.cs != nil {
+		var err error
+		if plaintext, err = hs.cs.Decrypt(hs.h, ciphertext); err != nil {
+			return nil, err
+		}
+	}
+	hs.mixHash(ciphertext)
+	return plaintext, nil
+}
+
+// dh performs the DH of a token: ee, ss, or es and se, where the first letter
+// names the key of the initiator and the second the key of the responder
+func (hs *NoiseHandshake) dh(token string) error {
+	local, remote := token[0], token[1]
+	if !hs.config.Initiator {
+		local, remote = remote, local
+	}
+	privateKey, publicKey := hs.e, hs.re
+	if local == 's' {
+		privateKey = hs.s
+	}
+	if remote == 's' {
+		publicKey = hs.rs
+	}
+	if privateKey == nil || publicKey == nil {
+		return errors.New("noise: missing key for " + token)
+	}
+	shared, err := hs.ka.SharedSecret(privateKey, publicKey)
+	if err != nil {
+		return err
+	}
+	return hs.mixKey(shared)
+}
+
+// WriteMessage writes the next handshake message with the payload
+func (hs *NoiseHandshake) WriteMessage(payload []byte) ([]byte, error) {
+	if hs.Complete() {
+		return nil, errors.New("noise: handshake is complete")
+	}
+	if (hs.turn%2 == 0) != hs.config.Initiator {
+		return nil, errors.New("noise: not our turn to write")
+	}
+	var message []byte
+	for _, token := range hs.config.Pattern.messages[hs.turn] {
+		switch token {
+		case "e":
+			private, public, err := hs.ka.GenerateKey(hs.config.Rand)
+			if err != nil {
+				return nil, err
+			}
+			hs.e = private
+			message = append(message, public...)
+			hs.mixHash(public)
+		case "s":
+			if hs.sPublic == nil {
+				return nil, errors.New("noise: missing static key")
+			}
+			ciphertext, err := hs.encryptAndHash(hs.sPublic)
+			if err != nil {
+				return nil, err
+			}
+			message = append(message, ciphertext...)
+		default:
+			if err := hs.dh(token); err != nil {
+				return nil, err
+			}
+		}
+	}
+	ciphertext, err := hs.encryptAndHash(payload)
+	if err != nil {
+		return nil, err
+	}
+	message = append(message, ciphertext...)
+	if len(message) > noiseMaxMessageSize {
+		return nil, errors.New("noise: message is too long")
+	}
+	return message, hs.next()
+}
+
+// ReadMessage reads the next handshake message and returns its payload
+func (hs *NoiseHandshake) ReadMessage(message []byte) ([]byte, error) {
+	if hs.Complete() {
+		return nil, errors.New("noise: handshake is complete")
+	}
+	if (hs.turn%2 == 0) == hs.config.Initiator {
+		return nil, errors.New("noise: not our turn to read")
+	}
+	if len(message) > noiseMaxMessageSize {
+		return nil, errors.New("noise: message is too long")
+	}
+	for _, token := range hs.config.Pattern.messages[hs.turn] {
+		switch token {
+		case "e":
+			if len(message) < hs.dhLen {
+				return nil, errors.New("noise: message is too short")
+			}
+			hs.re = append([]byte{}, message[:hs.dhLen]...)
+			message = message[hs.dhLen:]
+			hs.mixHash(hs.re)
+		case "s":
+			size := hs.dhLen
+			if hs.cs != nil {
+				size += poly1305TagSize
+			}
+			if len(message) < size {
+				return nil, errors.New("noise: message is too short")
+			}
+			rs, err := hs.decryptAndHash(message[:size])
+			if err != nil {
+				return nil, err
+			}
+			hs.rs = rs
+			message = message[size:]
+		default:
+			if err := hs.dh(token); err != nil {
+				return nil, err
+			}
+		}
+	}
+	payload, err := hs.decryptAndHash(message)
+	if err != nil {
+		return nil, err
+	}
+	return payload, hs.next()
+}
+
+// next moves to the next message and splits the symmetric state after the
+// last one
+func (hs *NoiseHandshake) next() error {
+	hs.turn++
+	if !hs.Complete() {
+		return nil
+	}
+	k1, k2 := hs.hkdf(nil)
+	c1, err := newNoiseCipherState(hs.config.Cipher, k1)
+	if err != nil {
+		return err
+	}
+	c2, err := newNoiseCipherState(hs.config.Cipher, k2)
+	if err != nil {
+		return err
+	}
+	hs.send, hs.receive = c1, c2
+	if !hs.config.Initiator {
+		hs.send, hs.receive = c2, c1
+	}
+	return nil
+}
+
+// Complete reports whether all handshake messages have been processed
+func (hs *NoiseHandshake) Complete() bool {
+	return hs.turn == len(hs.config.Pattern.messages)
+}
+
+// Transport returns the cipher states used to send and receive transport
+// messages once the handshake is complete
+func (hs *NoiseHandshake) Transport() (send, receive *NoiseCipherState, err error) {
+	if !hs.Complete() {
+		return nil, nil, errors.New("noise: handshake is not complete")
+	}
+	return hs.send, hs.receive, nil
+}
+
+// HandshakeHash returns the handshake hash h, which can be used for channel
+// binding once the handshake is complete
+func (hs *NoiseHandshake) HandshakeHash() []byte {
+	return append([]byte{}, hs.h...)
+}
+
+// RemoteStatic returns the static public key of the peer, when the pattern
+// pre-shares or transmits it
+func (hs *NoiseHandshake) RemoteStatic() []byte {
+	return hs.rs
+}
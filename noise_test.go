@@ -0,0 +1,345 @@
+package ecc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// noisePatterns are the handshake patterns by name
+var noisePatterns = map[string]*NoisePattern{"NN": NoiseNN, "NK": NoiseNK, "XX": NoiseXX, "IK": NoiseIK}
+
+// noiseMessage is a message of a test vector with its expected ciphertext
+type noiseMessage struct {
+	Payload    string `json:"payload"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// readNoiseVectors reads the test vectors of github.com/flynn/noise for the
+// supported patterns, 25519 and SHA-256: blocks of key=value lines separated
+// by empty lines
+func readNoiseVectors(t *testing.T) []map[string]string {
+	f, err := os.Open("testdata/noise_vectors.txt")
+	if err != nil {
+		t.Fatalf("Failed to open test vectors : %v", err)
+	}
+	defer f.Close()
+
+	var vectors []map[string]string
+	vector := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			vectors = append(vectors, vector)
+			vector = map[string]string{}
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		vector[key] = value
+	}
+	if len(vector) > 0 {
+		vectors = append(vectors, vector)
+	}
+	return vectors
+}
+
+// noiseGeneratedVector is a vector of noise_generated_vectors.json, in the
+// JSON format of the cacophony test suite
+type noiseGeneratedVector struct {
+	ProtocolName     string         `json:"protocol_name"`
+	InitPrologue     string         `json:"init_prologue"`
+	InitStatic       string         `json:"init_static"`
+	InitEphemeral    string         `json:"init_ephemeral"`
+	InitRemoteStatic string         `json:"init_remote_static"`
+	RespPrologue     string         `json:"resp_prologue"`
+	RespStatic       string         `json:"resp_static"`
+	RespEphemeral    string         `json:"resp_ephemeral"`
+	HandshakeHash    string         `json:"handshake_hash"`
+	Messages         []noiseMessage `json:"messages"`
+}
+
+// readGeneratedNoiseVectors reads the vectors of the 448, P256 and secp256k1
+// suites, for which no published vectors exist. They were generated with the
+// handshake of github.com/flynn/noise.
+func readGeneratedNoiseVectors(t *testing.T) []noiseGeneratedVector {
+	data, err := os.ReadFile("testdata/noise_generated_vectors.json")
+	if err != nil {
+		t.Fatalf("Failed to open test vectors : %v", err)
+	}
+	var file struct {
+		Vectors []noiseGeneratedVector `json:"vectors"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Failed to parse test vectors : %v", err)
+	}
+	return file.Vectors
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Invalid hex %q : %v", s, err)
+	}
+	return decoded
+}
+
+// runNoiseVector sends the messages of a vector, handshake messages and then
+// transport messages in turn from the initiator, and checks their ciphertexts
+func runNoiseVector(t *testing.T, name string, initiator, responder *NoiseConfig, messages []noiseMessage) (*NoiseHandshake, *NoiseHandshake) {
+	hsI, err := NewNoiseHandshake(initiator)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	hsR, err := NewNoiseHandshake(responder)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+
+	var sendI, receiveI, sendR, receiveR *NoiseCipherState
+	for j, m := range messages {
+		payload := mustDecodeHex(t, m.Payload)
+		expected := mustDecodeHex(t, m.Ciphertext)
+
+		var message, received []byte
+		var err error
+		switch {
+		case !hsI.Complete() && j%2 == 0:
+			if message, err = hsI.WriteMessage(payload); err == nil {
+				received, err = hsR.ReadMessage(message)
+			}
+		case !hsI.Complete():
+			if message, err = hsR.WriteMessage(payload); err == nil {
+				received, err = hsI.ReadMessage(message)
+			}
+		default:
+			if sendI == nil {
+				sendI, receiveI, _ = hsI.Transport()
+				sendR, receiveR, _ = hsR.Transport()
+			}
+			// Transport messages alternate as well, starting with the initiator
+			sender, receiver := sendI, receiveR
+			if (j-len(initiator.Pattern.messages))%2 == 1 {
+				sender, receiver = sendR, receiveI
+			}
+			if message, err = sender.Encrypt(nil, payload); err == nil {
+				received, err = receiver.Decrypt(nil, message)
+			}
+		}
+		if err != nil {
+			t.Fatalf("%s, message %d: %v", name, j, err)
+		}
+		if !bytes.Equal(message, expected) {
+			t.Fatalf("%s, message %d: Expected %x, Observed %x", name, j, expected, message)
+		}
+		if !bytes.Equal(received, payload) {
+			t.Fatalf("%s, message %d: Expected payload %x, Observed %x", name, j, payload, received)
+		}
+	}
+	if !hsI.Complete() || !hsR.Complete() {
+		t.Fatalf("%s: Expected the handshake to be complete", name)
+	}
+	if !bytes.Equal(hsI.HandshakeHash(), hsR.HandshakeHash()) {
+		t.Fatalf("%s: Expected equal handshake hashes", name)
+	}
+	return hsI, hsR
+}
+
+func TestNoise_Vectors(t *testing.T) {
+	vectors := readNoiseVectors(t)
+	if len(vectors) == 0 {
+		t.Fatalf("No test vectors")
+	}
+	for i, v := range vectors {
+		name := v["handshake"]
+		parts := strings.Split(name, "_")
+		ka, _ := NewKeyAgreement("X25519")
+
+		initiator := &NoiseConfig{
+			Pattern: noisePatterns[parts[1]], DH: parts[2], Cipher: parts[3], Initiator: true,
+			Prologue: mustDecodeHex(t, v["prologue"]),
+			Rand:     bytes.NewReader(mustDecodeHex(t, v["gen_init_ephemeral"])),
+		}
+		responder := &NoiseConfig{
+			Pattern: initiator.Pattern, DH: parts[2], Cipher: parts[3],
+			Prologue: initiator.Prologue,
+			Rand:     bytes.NewReader(mustDecodeHex(t, v["gen_resp_ephemeral"])),
+		}
+		if s, ok := v["init_static"]; ok {
+			initiator.StaticKey = mustDecodeHex(t, s)
+		}
+		if s, ok := v["resp_static"]; ok {
+			responder.StaticKey = mustDecodeHex(t, s)
+			if initiator.Pattern.responderPre != nil {
+				initiator.RemoteStatic, _ = ka.PublicKey(responder.StaticKey)
+			}
+		}
+
+		var messages []noiseMessage
+		for j := 0; ; j++ {
+			payload, ok := v[fmt.Sprintf("msg_%d_payload", j)]
+			if !ok {
+				break
+			}
+			messages = append(messages, noiseMessage{payload, v[fmt.Sprintf("msg_%d_ciphertext", j)]})
+		}
+		runNoiseVector(t, fmt.Sprintf("Vector %d (%s)", i, name), initiator, responder, messages)
+	}
+}
+
+func TestNoise_GeneratedVectors(t *testing.T) {
+	for i, v := range readGeneratedNoiseVectors(t) {
+		parts := strings.Split(v.ProtocolName, "_")
+		initiator := &NoiseConfig{
+			Pattern: noisePatterns[parts[1]], DH: parts[2], Cipher: parts[3], Initiator: true,
+			Prologue:     mustDecodeHex(t, v.InitPrologue),
+			StaticKey:    mustDecodeHex(t, v.InitStatic),
+			RemoteStatic: mustDecodeHex(t, v.InitRemoteStatic),
+			Rand:         bytes.NewReader(mustDecodeHex(t, v.InitEphemeral)),
+		}
+		responder := &NoiseConfig{
+			Pattern: initiator.Pattern, DH: parts[2], Cipher: parts[3],
+			Prologue:  mustDecodeHex(t, v.RespPrologue),
+			StaticKey: mustDecodeHex(t, v.RespStatic),
+			Rand:      bytes.NewReader(mustDecodeHex(t, v.RespEphemeral)),
+		}
+		name := fmt.Sprintf("Vector %d (%s)", i, v.ProtocolName)
+		hsI, _ := runNoiseVector(t, name, initiator, responder, v.Messages)
+		if expected := mustDecodeHex(t, v.HandshakeHash); !bytes.Equal(hsI.HandshakeHash(), expected) {
+			t.Fatalf("%s: Expected handshake hash %x, Observed %x", name, expected, hsI.HandshakeHash())
+		}
+	}
+}
+
+func TestNoise_VectorCoverage(t *testing.T) {
+	suites := map[string]bool{}
+	for _, v := range readNoiseVectors(t) {
+		suites[v["handshake"]] = true
+	}
+	for _, v := range readGeneratedNoiseVectors(t) {
+		suites[v.ProtocolName] = true
+	}
+	for pattern := range noisePatterns {
+		for dh := range noiseDH {
+			for _, cipherName := range []string{"ChaChaPoly", "AESGCM"} {
+				if name := "Noise_" + pattern + "_" + dh + "_" + cipherName + "_SHA256"; !suites[name] {
+					t.Fatalf("Missing test vector for %s", name)
+				}
+			}
+		}
+	}
+}
+
+// noiseHandshake runs a handshake and returns both handshake states
+func noiseHandshake(t *testing.T, initiator, responder *NoiseConfig) (*NoiseHandshake, *NoiseHandshake) {
+	hsI, err := NewNoiseHandshake(initiator)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	hsR, err := NewNoiseHandshake(responder)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	writer, reader := hsI, hsR
+	for !hsI.Complete() {
+		message, err := writer.WriteMessage([]byte("payload"))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		payload, err := reader.ReadMessage(message)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if string(payload) != "payload" {
+			t.Fatalf("Expected payload, Observed %q", payload)
+		}
+		writer, reader = reader, writer
+	}
+	return hsI, hsR
+}
+
+func TestNoise_Curves(t *testing.T) {
+	for _, dh := range []string{"secp256k1", "P256", "448"} {
+		ka, _ := NewKeyAgreement(noiseDH[dh].keyAgreement)
+		for _, pattern := range []*NoisePattern{NoiseNN, NoiseNK, NoiseXX, NoiseIK} {
+			for _, cipherName := range []string{"ChaChaPoly", "AESGCM"} {
+				staticI, publicI, _ := ka.GenerateKey(nil)
+				staticR, publicR, _ := ka.GenerateKey(nil)
+				initiator := &NoiseConfig{Pattern: pattern, DH: dh, Cipher: cipherName, Initiator: true, Prologue: []byte("prologue")}
+				responder := &NoiseConfig{Pattern: pattern, DH: dh, Cipher: cipherName, Prologue: []byte("prologue")}
+				if pattern == NoiseXX || pattern == NoiseIK {
+					initiator.StaticKey = staticI
+				}
+				if pattern != NoiseNN {
+					responder.StaticKey = staticR
+				}
+				if pattern.responderPre != nil {
+					initiator.RemoteStatic = publicR
+				}
+
+				hsI, hsR := noiseHandshake(t, initiator, responder)
+				if pattern != NoiseNN && !bytes.Equal(hsI.RemoteStatic(), publicR) {
+					t.Fatalf("%s %s: Expected remote static %x, Observed %x", dh, pattern.Name, publicR, hsI.RemoteStatic())
+				}
+				if initiator.StaticKey != nil && !bytes.Equal(hsR.RemoteStatic(), publicI) {
+					t.Fatalf("%s %s: Expected remote static %x, Observed %x", dh, pattern.Name, publicI, hsR.RemoteStatic())
+				}
+				sendI, _, _ := hsI.Transport()
+				_, receiveR, _ := hsR.Transport()
+				ciphertext, _ := sendI.Encrypt([]byte("ad"), []byte("transport"))
+				plaintext, err := receiveR.Decrypt([]byte("ad"), ciphertext)
+				if err != nil || string(plaintext) != "transport" {
+					t.Fatalf("%s %s %s: Expected transport, Observed %q (%v)", dh, pattern.Name, cipherName, plaintext, err)
+				}
+			}
+		}
+	}
+}
+
+func TestNoise_Rejections(t *testing.T) {
+	ka, _ := NewKeyAgreement("P-256")
+	staticR, _, _ := ka.GenerateKey(nil)
+	_, otherPublic, _ := ka.GenerateKey(nil)
+
+	// An NK initiator with the wrong responder key fails on the second message
+	hsI, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseNK, DH: "P256", Cipher: "AESGCM", Initiator: true, RemoteStatic: otherPublic})
+	hsR, _ := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseNK, DH: "P256", Cipher: "AESGCM", StaticKey: staticR})
+	message, _ := hsI.WriteMessage(nil)
+	if _, err := hsR.ReadMessage(message); err == nil {
+		t.Fatalf("Expected an error for a message encrypted to another static key")
+	}
+
+	// Tampered messages and messages out of turn
+	hsI, _ = NewNoiseHandshake(&NoiseConfig{Pattern: NoiseNN, DH: "secp256k1", Cipher: "ChaChaPoly", Initiator: true})
+	hsR, _ = NewNoiseHandshake(&NoiseConfig{Pattern: NoiseNN, DH: "secp256k1", Cipher: "ChaChaPoly"})
+	if _, err := hsR.WriteMessage(nil); err == nil {
+		t.Fatalf("Expected an error when the responder writes first")
+	}
+	message, _ = hsI.WriteMessage(nil)
+	if _, err := hsR.ReadMessage(message); err != nil {
+		t.Fatalf("%v", err)
+	}
+	message, _ = hsR.WriteMessage([]byte("payload"))
+	message[len(message)-1] ^= 1
+	if _, err := hsI.ReadMessage(message); err == nil {
+		t.Fatalf("Expected an error for a tampered message")
+	}
+	if _, _, err := hsI.Transport(); err == nil {
+		t.Fatalf("Expected an error before the handshake is complete")
+	}
+
+	if _, err := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseIK, DH: "P256", Cipher: "AESGCM", Initiator: true}); err == nil {
+		t.Fatalf("Expected an error for IK without the responder static key")
+	}
+	if _, err := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseNN, DH: "brainpoolP256r1", Cipher: "AESGCM"}); err == nil {
+		t.Fatalf("Expected an error for an unsupported DH function")
+	}
+	if _, err := NewNoiseHandshake(&NoiseConfig{Pattern: NoiseNN, DH: "25519", Cipher: "AES"}); err == nil {
+		t.Fatalf("Expected an error for an unsupported cipher")
+	}
+}
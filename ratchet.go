@@ -0,0 +1,557 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The Double Ratchet algorithm of the Signal protocol, with ECDH on a
+// Weierstrass curve as the DH function. Every message is encrypted with a new
+// key of the symmetric-key ratchet, and the DH ratchet replaces the ratchet
+// keys whenever the turn changes, which gives forward secrecy and
+// break-in recovery.
+//
+//	KDF_RK(rk, dh)  HKDF with salt rk and the info of RatchetOptions, giving the
+//	                next root key, a chain key and, with header encryption, the
+//	                next header key, of 32 bytes each
+//	KDF_CK(ck)      HMAC(ck, 0x01) is the message key, HMAC(ck, 0x02) the next
+//	                chain key
+//	ENCRYPT(mk)     AES-256-GCM with the key and nonce of HKDF(mk) and the
+//	                associated data AD || header
+//	HENCRYPT(hk)    AES-256-GCM with a random nonce prepended to the header
+//
+// A message is the header, DH || PN || N with the SEC 1 uncompressed ratchet
+// key and 32-bit big-endian counters, or the encrypted header, followed by
+// the ciphertext of the payload.
+
+const (
+	ratchetKeySize        = 32
+	ratchetDefaultMaxSkip = 1000
+	ratchetMessageKeyInfo = "ecc double ratchet message key"
+)
+
+// RatchetOptions configures a ratchet session. A nil *RatchetOptions selects
+// SHA-256, no info, a limit of 1000 skipped message keys and no header
+// encryption.
+type RatchetOptions struct {
+	Hash crypto.Hash // hash of HKDF and HMAC, SHA-256 when zero
+	Info []byte      // application specific info of KDF_RK
+
+	// MaxSkip limits the number of message keys skipped in a single chain and
+	// the number of skipped message keys stored; the oldest keys are dropped
+	// first. 1000 when zero.
+	MaxSkip int
+
+	// HeaderKey and NextHeaderKey are the shared header keys of 32 bytes, both
+	// agreed with the peer like the shared key. Headers are encrypted when they
+	// are set.
+	HeaderKey     []byte
+	NextHeaderKey []byte
+
+	// Rand is the source of the ratchet keys and of the header nonces,
+	// crypto/rand when nil
+	Rand io.Reader
+}
+
+// RatchetSession is one side of a Double Ratchet conversation. Sessions are
+// not safe for concurrent use, and MarshalBinary must be called again after
+// every Encrypt and Decrypt to persist the session.
+type RatchetSession struct {
+	curve   *ECParams
+	hash    crypto.Hash
+	info    []byte
+	maxSkip int
+	rand    io.Reader
+
+	self         *ECPrivateKey // DHs
+	remote       *Point        // DHr, nil until the first message is received
+	rootKey      []byte
+	sendChain    []byte // nil until the first message is received by the responder
+	receiveChain []byte
+	sendN        uint32 // Ns
+	receiveN     uint32 // Nr
+	previousN    uint32 // PN
+
+	headerEncryption  bool
+	sendHeader        []byte // HKs
+	receiveHeader     []byte // HKr
+	nextSendHeader    []byte // NHKs
+	nextReceiveHeader []byte // NHKr
+
+	skipped []ratchetSkippedKey // oldest first
+}
+
+// ratchetSkippedKey is the message key of a message that has not arrived yet,
+// identified by the ratchet key of the chain, or by the header key with header
+// encryption, and the message number
+type ratchetSkippedKey struct {
+	chain []byte
+	n     uint32
+	key   []byte
+}
+
+// NewRatchetInitiator starts the session of the party that sends the first
+// message, with the shared key agreed with the peer, e.g. with X3DH, and the
+// ratchet public key of the peer, e.g. its signed prekey
+func NewRatchetInitiator(sharedKey []byte, remote *Point, params *ECParams, opts *RatchetOptions) (*RatchetSession, error) {
+	if opts == nil {
+		opts = &RatchetOptions{}
+	}
+	session, err := newRatchetSession(sharedKey, params, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !params.isValidPublicKey(remote) {
+		return nil, errors.New("ecc: invalid ratchet public key")
+	}
+	if session.self, err = params.GeneratePrivateKey(session.random()); err != nil {
+		return nil, err
+	}
+	session.remote = remote
+	session.sendHeader, session.nextReceiveHeader = opts.HeaderKey, opts.NextHeaderKey
+	if session.sendChain, session.nextSendHeader, err = session.ratchetRoot(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// NewRatchetResponder starts the session of the party that receives the first
+// message, with the shared key agreed with the peer and the ratchet key pair
+// whose public key the initiator used. The responder sends after receiving the
+// first message.
+func NewRatchetResponder(sharedKey []byte, key *ECPrivateKey, opts *RatchetOptions) (*RatchetSession, error) {
+	if key == nil || key.curve == nil || !key.curve.IsValidPrivateKey(key) {
+		return nil, errors.New("ecc: invalid ratchet private key")
+	}
+	if opts == nil {
+		opts = &RatchetOptions{}
+	}
+	session, err := newRatchetSession(sharedKey, key.curve, opts)
+	if err != nil {
+		return nil, err
+	}
+	session.self = &ECPrivateKey{D: key.D, curve: key.curve, PublicKey: key.publicPoint(key.curve)}
+	session.nextSendHeader, session.nextReceiveHeader = opts.NextHeaderKey, opts.HeaderKey
+	return session, nil
+}
+
+func newRatchetSession(sharedKey []byte, params *ECParams, opts *RatchetOptions) (*RatchetSession, error) {
+	if len(sharedKey) != ratchetKeySize {
+		return nil, fmt.Errorf("ecc: ratchet shared key must be %d bytes, got %d", ratchetKeySize, len(sharedKey))
+	}
+	if lookupCurve(params) == nil {
+		return nil, errors.New("ecc: ratchet curve is not supported")
+	}
+	session := &RatchetSession{
+		curve:   params,
+		hash:    opts.Hash,
+		info:    opts.Info,
+		maxSkip: opts.MaxSkip,
+		rand:    opts.Rand,
+		rootKey: bytes.Clone(sharedKey),
+	}
+	if session.hash == 0 {
+		session.hash = crypto.SHA256
+	}
+	if !session.hash.Available() {
+		return nil, errors.New("ecc: hash function of the ratchet is not available")
+	}
+	if session.maxSkip == 0 {
+		session.maxSkip = ratchetDefaultMaxSkip
+	}
+	if session.maxSkip < 0 {
+		return nil, errors.New("ecc: negative ratchet MaxSkip")
+	}
+	if opts.HeaderKey != nil || opts.NextHeaderKey != nil {
+		if len(opts.HeaderKey) != ratchetKeySize || len(opts.NextHeaderKey) != ratchetKeySize {
+			return nil, fmt.Errorf("ecc: ratchet header keys must be %d bytes", ratchetKeySize)
+		}
+		session.headerEncryption = true
+	}
+	return session, nil
+}
+
+// Encrypt encrypts the plaintext as the next message of the session, with the
+// associated data authenticated along with the header
+func (session *RatchetSession) Encrypt(plaintext, ad []byte) ([]byte, error) {
+	if session.sendChain == nil {
+		return nil, errors.New("ecc: ratchet session cannot send before receiving a message")
+	}
+	messageKey, nextChain := session.ratchetChain(session.sendChain)
+	header := session.curve.marshalUncompressed(session.self.publicPoint(session.curve))
+	header = binary.BigEndian.AppendUint32(header, session.previousN)
+	header = binary.BigEndian.AppendUint32(header, session.sendN)
+
+	if session.headerEncryption {
+		aead, err := newRatchetAEAD(session.sendHeader)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(session.random(), nonce); err != nil {
+			return nil, err
+		}
+		header = aead.Seal(nonce, nonce, header, nil)
+	}
+	aead, nonce, err := session.messageAEAD(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	session.sendChain = nextChain
+	session.sendN++
+	return aead.Seal(header, nonce, plaintext, append(bytes.Clone(ad), header...)), nil
+}
+
+// Decrypt decrypts a message of the peer with the associated data it was
+// encrypted with. Messages may arrive out of order: the keys of skipped
+// messages are stored, up to the MaxSkip limit. The session is only updated
+// when the message is authentic.
+func (session *RatchetSession) Decrypt(message, ad []byte) ([]byte, error) {
+	headerSize := 1 + 2*session.curve.coordinateSize() + 8
+	if session.headerEncryption {
+		headerSize += 12 + 16
+	}
+	if len(message) < headerSize+16 {
+		return nil, errors.New("ecc: ratchet message is too short")
+	}
+	encodedHeader, ciphertext := message[:headerSize], message[headerSize:]
+	ad = append(bytes.Clone(ad), encodedHeader...)
+
+	// Work on a copy that replaces the session on success
+	state := session.clone()
+	var chain []byte
+	var header []byte
+	newChain := false
+	if state.headerEncryption {
+		for _, key := range [][]byte{state.receiveHeader, state.nextReceiveHeader} {
+			if header = openRatchetHeader(key, encodedHeader); header != nil {
+				chain, newChain = key, bytes.Equal(key, state.nextReceiveHeader)
+				break
+			}
+		}
+		if header == nil {
+			// A skipped message of an older chain
+			for _, skipped := range state.skipped {
+				if header = openRatchetHeader(skipped.chain, encodedHeader); header != nil {
+					chain = skipped.chain
+					break
+				}
+			}
+		}
+		if header == nil {
+			return nil, errors.New("ecc: ratchet message authentication failed")
+		}
+	} else {
+		header, chain = encodedHeader, encodedHeader[:headerSize-8]
+		newChain = state.remote == nil || !bytes.Equal(chain, state.curve.marshalUncompressed(state.remote))
+	}
+	remote, err := state.curve.NewPublicKey(header[:len(header)-8])
+	if err != nil {
+		return nil, err
+	}
+	previousN := binary.BigEndian.Uint32(header[len(header)-8:])
+	n := binary.BigEndian.Uint32(header[len(header)-4:])
+
+	messageKey := state.takeSkippedKey(chain, n)
+	if messageKey == nil {
+		if newChain {
+			if err := state.skipMessageKeys(previousN); err != nil {
+				return nil, err
+			}
+			if err := state.ratchetDH(remote); err != nil {
+				return nil, err
+			}
+		} else if !bytes.Equal(chain, state.currentReceiveChain()) {
+			return nil, errors.New("ecc: ratchet message key is not available")
+		}
+		if err := state.skipMessageKeys(n); err != nil {
+			return nil, err
+		}
+		messageKey, state.receiveChain = state.ratchetChain(state.receiveChain)
+		state.receiveN++
+	}
+
+	aead, nonce, err := state.messageAEAD(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, errors.New("ecc: ratchet message authentication failed")
+	}
+	*session = *state
+	return plaintext, nil
+}
+
+// currentReceiveChain returns the identifier of the receiving chain: the
+// ratchet key of the peer, or the receiving header key with header encryption
+func (session *RatchetSession) currentReceiveChain() []byte {
+	if session.headerEncryption {
+		return session.receiveHeader
+	}
+	if session.remote == nil {
+		return nil
+	}
+	return session.curve.marshalUncompressed(session.remote)
+}
+
+// skipMessageKeys stores the message keys of the receiving chain up to message
+// number until
+func (session *RatchetSession) skipMessageKeys(until uint32) error {
+	if session.receiveChain == nil {
+		return nil
+	}
+	if until < session.receiveN {
+		return errors.New("ecc: ratchet message number is out of order")
+	}
+	if uint64(until-session.receiveN) > uint64(session.maxSkip) {
+		return errors.New("ecc: too many skipped ratchet messages")
+	}
+	chain := session.currentReceiveChain()
+	for session.receiveN < until {
+		var key []byte
+		key, session.receiveChain = session.ratchetChain(session.receiveChain)
+		session.skipped = append(session.skipped, ratchetSkippedKey{chain: chain, n: session.receiveN, key: key})
+		session.receiveN++
+	}
+	if len(session.skipped) > session.maxSkip {
+		session.skipped = append([]ratchetSkippedKey(nil), session.skipped[len(session.skipped)-session.maxSkip:]...)
+	}
+	return nil
+}
+
+// takeSkippedKey removes and returns the stored key of a skipped message
+func (session *RatchetSession) takeSkippedKey(chain []byte, n uint32) []byte {
+	for i, skipped := range session.skipped {
+		if skipped.n == n && bytes.Equal(skipped.chain, chain) {
+			session.skipped = append(session.skipped[:i:i], session.skipped[i+1:]...)
+			return skipped.key
+		}
+	}
+	return nil
+}
+
+// ratchetDH performs a DH ratchet step with the new ratchet key of the peer
+func (session *RatchetSession) ratchetDH(remote *Point) error {
+	if !session.curve.isValidPublicKey(remote) {
+		return errors.New("ecc: invalid ratchet public key")
+	}
+	session.previousN, session.sendN, session.receiveN = session.sendN, 0, 0
+	session.sendHeader, session.receiveHeader = session.nextSendHeader, session.nextReceiveHeader
+	session.remote = remote
+
+	var err error
+	if session.receiveChain, session.nextReceiveHeader, err = session.ratchetRoot(); err != nil {
+		return err
+	}
+	if session.self, err = session.curve.GeneratePrivateKey(session.random()); err != nil {
+		return err
+	}
+	session.sendChain, session.nextSendHeader, err = session.ratchetRoot()
+	return err
+}
+
+// ratchetRoot runs KDF_RK on the shared secret of the ratchet keys, updating
+// the root key and returning the chain key and, with header encryption, the
+// next header key
+func (session *RatchetSession) ratchetRoot() (chain, headerKey []byte, err error) {
+	secret, err := session.self.SharedSecret(session.remote)
+	if err != nil {
+		return nil, nil, err
+	}
+	length := 2 * ratchetKeySize
+	if session.headerEncryption {
+		length += ratchetKeySize
+	}
+	out, err := HKDF(session.hash, secret, session.rootKey, session.info, length)
+	if err != nil {
+		return nil, nil, err
+	}
+	session.rootKey, chain = out[:ratchetKeySize], out[ratchetKeySize:2*ratchetKeySize]
+	if session.headerEncryption {
+		headerKey = out[2*ratchetKeySize:]
+	}
+	return chain, headerKey, nil
+}
+
+// ratchetChain runs KDF_CK, returning the message key and the next chain key
+func (session *RatchetSession) ratchetChain(chain []byte) (messageKey, nextChain []byte) {
+	mac := hmac.New(session.hash.New, chain)
+	mac.Write([]byte{1})
+	messageKey = mac.Sum(nil)
+	mac.Reset()
+	mac.Write([]byte{2})
+	return messageKey, mac.Sum(nil)
+}
+
+// messageAEAD derives the AES-256-GCM key and nonce of a message key
+func (session *RatchetSession) messageAEAD(messageKey []byte) (cipher.AEAD, []byte, error) {
+	out, err := HKDF(session.hash, messageKey, nil, []byte(ratchetMessageKeyInfo), ratchetKeySize+12)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := newRatchetAEAD(out[:ratchetKeySize])
+	return aead, out[ratchetKeySize:], err
+}
+
+func newRatchetAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// openRatchetHeader decrypts an encrypted header, returning nil when the key
+// is missing or wrong
+func openRatchetHeader(key, encryptedHeader []byte) []byte {
+	if key == nil {
+		return nil
+	}
+	aead, err := newRatchetAEAD(key)
+	if err != nil {
+		return nil
+	}
+	nonceSize := aead.NonceSize()
+	header, err := aead.Open(nil, encryptedHeader[:nonceSize], encryptedHeader[nonceSize:], nil)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (session *RatchetSession) random() io.Reader {
+	if session.rand == nil {
+		return cryptorand.Reader
+	}
+	return session.rand
+}
+
+// clone returns a copy of the session that can be updated independently. Keys
+// are never modified in place, so they are shared.
+func (session *RatchetSession) clone() *RatchetSession {
+	state := *session
+	state.skipped = append([]ratchetSkippedKey(nil), session.skipped...)
+	return &state
+}
+
+// SetRand sets the source of the ratchet keys and of the header nonces of a
+// session, e.g. after UnmarshalBinary; nil selects crypto/rand
+func (session *RatchetSession) SetRand(rand io.Reader) {
+	session.rand = rand
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding contains
+// every secret of the session and must be stored as such.
+//
+//	curve ID || hash || flags || MaxSkip || info || DHs || DHr || RK || CKs ||
+//	CKr || Ns || Nr || PN || HKs || HKr || NHKs || NHKr || count || skipped keys
+//
+// with 32-bit big-endian integers, byte strings prefixed with their 32-bit
+// length and skipped keys as chain || N || message key. Flag 1 is header
+// encryption. DHs is the private key and DHr is the SEC 1 uncompressed
+// public key.
+func (session *RatchetSession) MarshalBinary() ([]byte, error) {
+	curve := lookupCurve(session.curve)
+	if curve == nil || curve.ID == 0 {
+		return nil, errors.New("ecc: ratchet session is not on a supported curve")
+	}
+	var flags byte
+	if session.headerEncryption {
+		flags |= 1
+	}
+	data := []byte{curve.ID, byte(session.hash), flags}
+	data = binary.BigEndian.AppendUint32(data, uint32(session.maxSkip))
+	data = appendSSHString(data, session.info)
+	data = appendSSHString(data, session.self.D.FillBytes(make([]byte, session.curve.scalarSize())))
+	var remote []byte
+	if session.remote != nil {
+		remote = session.curve.marshalUncompressed(session.remote)
+	}
+	for _, field := range [][]byte{remote, session.rootKey, session.sendChain, session.receiveChain} {
+		data = appendSSHString(data, field)
+	}
+	for _, counter := range []uint32{session.sendN, session.receiveN, session.previousN} {
+		data = binary.BigEndian.AppendUint32(data, counter)
+	}
+	for _, field := range [][]byte{session.sendHeader, session.receiveHeader, session.nextSendHeader, session.nextReceiveHeader} {
+		data = appendSSHString(data, field)
+	}
+	data = binary.BigEndian.AppendUint32(data, uint32(len(session.skipped)))
+	for _, skipped := range session.skipped {
+		data = appendSSHString(data, skipped.chain)
+		data = binary.BigEndian.AppendUint32(data, skipped.n)
+		data = appendSSHString(data, skipped.key)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The source of
+// randomness of the decoded session is crypto/rand.
+func (session *RatchetSession) UnmarshalBinary(data []byte) error {
+	curve, data, err := splitCurveID(data)
+	if err != nil {
+		return err
+	}
+	if len(data) < 2 {
+		return errors.New("ecc: ratchet session is too short")
+	}
+	decoded := RatchetSession{curve: curve.Params, hash: crypto.Hash(data[0]), headerEncryption: data[1]&1 != 0}
+	if !decoded.hash.Available() {
+		return errors.New("ecc: hash function of the ratchet is not available")
+	}
+	r := &sshReader{data: data[2:]}
+	decoded.maxSkip = int(r.readUint32())
+	decoded.info = optionalBytes(r.readString())
+	d := r.readString()
+	remote := r.readString()
+	decoded.rootKey = optionalBytes(r.readString())
+	decoded.sendChain = optionalBytes(r.readString())
+	decoded.receiveChain = optionalBytes(r.readString())
+	decoded.sendN, decoded.receiveN, decoded.previousN = r.readUint32(), r.readUint32(), r.readUint32()
+	decoded.sendHeader = optionalBytes(r.readString())
+	decoded.receiveHeader = optionalBytes(r.readString())
+	decoded.nextSendHeader = optionalBytes(r.readString())
+	decoded.nextReceiveHeader = optionalBytes(r.readString())
+	count := r.readUint32()
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		skipped := ratchetSkippedKey{chain: bytes.Clone(r.readString()), n: r.readUint32(), key: bytes.Clone(r.readString())}
+		decoded.skipped = append(decoded.skipped, skipped)
+	}
+	if r.err != nil {
+		return fmt.Errorf("ecc: invalid ratchet session: %w", r.err)
+	}
+	if len(r.data) != 0 {
+		return errors.New("ecc: trailing data after ratchet session")
+	}
+	if len(decoded.rootKey) != ratchetKeySize {
+		return errors.New("ecc: invalid ratchet root key")
+	}
+
+	if decoded.self, err = decoded.curve.NewPrivateKey(d); err != nil {
+		return err
+	}
+	if len(remote) > 0 {
+		if decoded.remote, err = decoded.curve.NewPublicKey(remote); err != nil {
+			return err
+		}
+	}
+	*session = decoded
+	return nil
+}
+
+// optionalBytes copies a decoded byte string, keeping empty strings as nil
+func optionalBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return bytes.Clone(b)
+}
@@ -0,0 +1,199 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// ratchetTestKeys returns the P-256 ratchet keys of the initiator and the
+// responder
+func ratchetTestKeys(t *testing.T) (ratchetA, ratchetB *ECPrivateKey) {
+	keys := p256TestKeys(t,
+		"48e31d75c796f9169dd71bc05c493637588c350e40ffe083b5b836ed78a1011a",
+		"fe8aeccc4e0ad30a2041e40f037f2b362d351646b909c08ed09c33791d5ae6a5",
+	)
+	return keys[0], keys[1]
+}
+
+// Regression values for the keys of ratchetTestKeys and the shared key
+// 0x00 0x01 ... 0x1f, not published vectors. The messages follow the KDF
+// chains of the Double Ratchet specification.
+func TestRatchet_Regression(t *testing.T) {
+	ratchetA, ratchetB := ratchetTestKeys(t)
+	sharedKey := make([]byte, 32)
+	for i := range sharedKey {
+		sharedKey[i] = byte(i)
+	}
+	opts := &RatchetOptions{Info: []byte("ratchet test"), Rand: bytes.NewReader(ratchetA.D.FillBytes(make([]byte, 32)))}
+	alice, err := NewRatchetInitiator(sharedKey, ratchetB.PublicKey, ratchetB.curve, opts)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	bob, err := NewRatchetResponder(sharedKey, ratchetB, &RatchetOptions{Info: []byte("ratchet test")})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	header := "047bc6849d7a9c5f5ccdde38bc588c5a82a95b422f964bbb8cf1f5cafcd694b010a8109a08b38a3021ad3d4e7f75298778bd6f6e0cf2f71bb5b1a601b8aa7c0abc00000000"
+	for _, v := range []struct {
+		plaintext, message string
+	}{
+		{"hello", header + "000000004cf8d6fc44b1d36d0029c44ed0736a9b75f3f2991e"},
+		{"world", header + "00000001c57201432de5f98a4202c5cd4a93ce511f30c9a751"},
+	} {
+		message, err := alice.Encrypt([]byte(v.plaintext), []byte("ad"))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if hex.EncodeToString(message) != v.message {
+			t.Fatalf("Expected %s, Observed %x", v.message, message)
+		}
+		plaintext, err := bob.Decrypt(message, []byte("ad"))
+		if err != nil || string(plaintext) != v.plaintext {
+			t.Fatalf("Expected %s, Observed %q (%v)", v.plaintext, plaintext, err)
+		}
+	}
+}
+
+// ratchetTestSessions starts a session pair from an X3DH run on P-256
+func ratchetTestSessions(t *testing.T, headerEncryption bool) (alice, bob *RatchetSession, ad []byte) {
+	params := GetSecp256r1Parameters().ECParams
+	identityA, _ := params.GeneratePrivateKey(nil)
+	identityB, _ := params.GeneratePrivateKey(nil)
+	signed, _ := identityB.NewX3DHSignedPrekey(1, nil)
+	message, resultA, err := identityA.InitiateX3DH(identityB.NewX3DHPrekeyBundle(signed, nil), nil, &X3DHOptions{KeySize: 96})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	resultB, err := identityB.RespondX3DH(message, signed, nil, &X3DHOptions{KeySize: 96})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	optsA, optsB := &RatchetOptions{MaxSkip: 10}, &RatchetOptions{MaxSkip: 10}
+	if headerEncryption {
+		optsA.HeaderKey, optsA.NextHeaderKey = resultA.Key[32:64], resultA.Key[64:]
+		optsB.HeaderKey, optsB.NextHeaderKey = resultB.Key[32:64], resultB.Key[64:]
+	}
+	if alice, err = NewRatchetInitiator(resultA.Key[:32], signed.Key.PublicKey, params, optsA); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if bob, err = NewRatchetResponder(resultB.Key[:32], signed.Key, optsB); err != nil {
+		t.Fatalf("%v", err)
+	}
+	return alice, bob, resultA.AssociatedData
+}
+
+func TestRatchet_Conversation(t *testing.T) {
+	for _, headerEncryption := range []bool{false, true} {
+		alice, bob, ad := ratchetTestSessions(t, headerEncryption)
+		if _, err := bob.Encrypt([]byte("early"), ad); err == nil {
+			t.Fatalf("Expected an error when the responder sends first")
+		}
+
+		// Each round, one party sends three messages that are delivered in
+		// the order 2, 0, 1, with the session of the receiver going through
+		// its encoding in between
+		sender, receiver := alice, bob
+		for round := 0; round < 4; round++ {
+			var messages [][]byte
+			for i := 0; i < 3; i++ {
+				message, err := sender.Encrypt([]byte(fmt.Sprintf("round %d message %d", round, i)), ad)
+				if err != nil {
+					t.Fatalf("Round %d: %v", round, err)
+				}
+				messages = append(messages, message)
+			}
+			for _, i := range []int{2, 0, 1} {
+				encoded, err := receiver.MarshalBinary()
+				if err != nil {
+					t.Fatalf("Round %d: %v", round, err)
+				}
+				restored := new(RatchetSession)
+				if err := restored.UnmarshalBinary(encoded); err != nil {
+					t.Fatalf("Round %d: %v", round, err)
+				}
+				*receiver = *restored
+
+				plaintext, err := receiver.Decrypt(messages[i], ad)
+				expected := fmt.Sprintf("round %d message %d", round, i)
+				if err != nil || string(plaintext) != expected {
+					t.Fatalf("Round %d, header encryption %v: Expected %s, Observed %q (%v)", round, headerEncryption, expected, plaintext, err)
+				}
+				if _, err := receiver.Decrypt(messages[i], ad); err == nil {
+					t.Fatalf("Round %d: Expected an error for a replayed message", round)
+				}
+			}
+			sender, receiver = receiver, sender
+		}
+
+		// A message of an older chain that is delivered late
+		late, _ := alice.Encrypt([]byte("late"), ad)
+		reply, _ := alice.Encrypt([]byte("reply"), ad)
+		if _, err := bob.Decrypt(reply, ad); err != nil {
+			t.Fatalf("%v", err)
+		}
+		message, _ := bob.Encrypt([]byte("turn"), ad)
+		if _, err := alice.Decrypt(message, ad); err != nil {
+			t.Fatalf("%v", err)
+		}
+		message, _ = alice.Encrypt([]byte("next"), ad)
+		if _, err := bob.Decrypt(message, ad); err != nil {
+			t.Fatalf("%v", err)
+		}
+		if plaintext, err := bob.Decrypt(late, ad); err != nil || string(plaintext) != "late" {
+			t.Fatalf("Expected late, Observed %q (%v)", plaintext, err)
+		}
+	}
+}
+
+func TestRatchet_Rejections(t *testing.T) {
+	for _, headerEncryption := range []bool{false, true} {
+		alice, bob, ad := ratchetTestSessions(t, headerEncryption)
+		message, _ := alice.Encrypt([]byte("message"), ad)
+		before, _ := bob.MarshalBinary()
+
+		tampered := bytes.Clone(message)
+		tampered[len(tampered)-1] ^= 1
+		if _, err := bob.Decrypt(tampered, ad); err == nil {
+			t.Fatalf("Expected an error for a tampered message")
+		}
+		if _, err := bob.Decrypt(message, []byte("other")); err == nil {
+			t.Fatalf("Expected an error for other associated data")
+		}
+		tampered = bytes.Clone(message)
+		tampered[10] ^= 1
+		if _, err := bob.Decrypt(tampered, ad); err == nil {
+			t.Fatalf("Expected an error for a tampered header")
+		}
+		if after, _ := bob.MarshalBinary(); !bytes.Equal(before, after) {
+			t.Fatalf("Expected the session to be unchanged by rejected messages")
+		}
+		if plaintext, err := bob.Decrypt(message, ad); err != nil || string(plaintext) != "message" {
+			t.Fatalf("Expected message, Observed %q (%v)", plaintext, err)
+		}
+
+		// More skipped messages than MaxSkip
+		for i := 0; i < 11; i++ {
+			alice.Encrypt([]byte("lost"), ad)
+		}
+		message, _ = alice.Encrypt([]byte("message"), ad)
+		if _, err := bob.Decrypt(message, ad); err == nil {
+			t.Fatalf("Expected an error for too many skipped messages")
+		}
+	}
+
+	params := GetSecp256r1Parameters().ECParams
+	key, _ := params.GeneratePrivateKey(nil)
+	if _, err := NewRatchetInitiator(make([]byte, 16), key.PublicKey, params, nil); err == nil {
+		t.Fatalf("Expected an error for a short shared key")
+	}
+	if _, err := NewRatchetResponder(make([]byte, 32), key, &RatchetOptions{HeaderKey: make([]byte, 32)}); err == nil {
+		t.Fatalf("Expected an error for a missing next header key")
+	}
+	if err := new(RatchetSession).UnmarshalBinary([]byte{2, 5, 0, 0}); err == nil {
+		t.Fatalf("Expected an error for a truncated session")
+	}
+}
@@ -0,0 +1,80 @@
+package ecc
+
+import (
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+// rfc6979Nonces generates the deterministic signature nonces k of RFC 6979
+// section 3.2 for a private key and message hash
+type rfc6979Nonces struct {
+	n    *big.Int
+	hash func() hash.Hash
+	k, v []byte
+}
+
+func newRFC6979Nonces(d *big.Int, messageHash []byte, n *big.Int, hash func() hash.Hash) *rfc6979Nonces {
+	size := (n.BitLen() + 7) / 8
+	hashSize := hash().Size()
+
+	// int2octets(x) and bits2octets(h1)
+	x := d.FillBytes(make([]byte, size))
+	h := new(big.Int).Mod(bits2int(messageHash, n), n).FillBytes(make([]byte, size))
+
+	nonces := &rfc6979Nonces{n: n, hash: hash}
+
+	// Steps b and c : V = 0x01 0x01 ... and K = 0x00 0x00 ...
+	nonces.v = make([]byte, hashSize)
+	for i := range nonces.v {
+		nonces.v[i] = 0x01
+	}
+	nonces.k = make([]byte, hashSize)
+
+	// Steps d to g
+	nonces.k = nonces.mac(nonces.v, []byte{0x00}, x, h)
+	nonces.v = nonces.mac(nonces.v)
+	nonces.k = nonces.mac(nonces.v, []byte{0x01}, x, h)
+	nonces.v = nonces.mac(nonces.v)
+
+	return nonces
+}
+
+// next returns the next candidate k in the range 1 <= k < n
+func (nonces *rfc6979Nonces) next() *big.Int {
+	for {
+		// Step h
+		var t []byte
+		for len(t)*8 < nonces.n.BitLen() {
+			nonces.v = nonces.mac(nonces.v)
+			t = append(t, nonces.v...)
+		}
+		k := bits2int(t, nonces.n)
+
+		// Prepare the state for a further candidate before returning
+		nonces.k = nonces.mac(nonces.v, []byte{0x00})
+		nonces.v = nonces.mac(nonces.v)
+
+		if k.Sign() > 0 && k.Cmp(nonces.n) < 0 {
+			return k
+		}
+	}
+}
+
+func (nonces *rfc6979Nonces) mac(data ...[]byte) []byte {
+	mac := hmac.New(nonces.hash, nonces.k)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// bits2int converts a bit string into an integer keeping its leftmost
+// n.BitLen() bits
+func bits2int(data []byte, n *big.Int) *big.Int {
+	x := new(big.Int).SetBytes(data)
+	if excess := len(data)*8 - n.BitLen(); excess > 0 {
+		x.Rsh(x, uint(excess))
+	}
+	return x
+}
@@ -0,0 +1,33 @@
+package ecc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRFC6979_Secp256r1SHA256(t *testing.T) {
+	// Test vectors from RFC 6979 appendix A.2.5 (P-256, SHA-256)
+	x, _ := new(big.Int).SetString("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721", 16)
+	params := GetSecp256r1Parameters()
+	privateKey := CreatePrivateKeyFromScalar(params.ECParams, x)
+	publicKey := privateKey.GeneratePublicKey()
+
+	testCases := []struct {
+		message string
+		r, s    string
+	}{
+		{"sample", "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716", "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"},
+		{"test", "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367", "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"},
+	}
+	for _, tc := range testCases {
+		signature := privateKey.Sign([]byte(tc.message))
+		expectedR, _ := new(big.Int).SetString(tc.r, 16)
+		expectedS, _ := new(big.Int).SetString(tc.s, 16)
+		if signature.r.Cmp(expectedR) != 0 || signature.s.Cmp(expectedS) != 0 {
+			t.Fatalf("Unexpected signature of %q. Expected (%x, %x), Observed (%x, %x)", tc.message, expectedR, expectedS, signature.r, signature.s)
+		}
+		if !publicKey.Verify([]byte(tc.message), signature, params.ECParams) {
+			t.Fatalf("Signature of %q does not verify", tc.message)
+		}
+	}
+}
@@ -26,17 +26,7 @@ func GetSecp256k1Parametes() *Secp256k1 {
 
 }
 
-// GeneratePrivateKey generates at new 32 byte key
-func (E *Secp256k1) GeneratePrivateKey() (*ECPrivateKey, error) {
-
-	// generate random 32 bytes
-	randomBytes, err := GenerateRandomBytes(32)
-
-	// Initialize private key
-	privateKey := ECPrivateKey{D: new(big.Int).SetBytes(randomBytes), curve: E.ECParams, PublicKey: &Point{}}
-
-	// Do scalar multiplication
-	privateKey.GeneratePublicKey()
-
-	return &privateKey, err
+// Name returns the standard name of the curve
+func (E *Secp256k1) Name() string {
+	return "secp256k1"
 }
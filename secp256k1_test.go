@@ -1,6 +1,7 @@
 package ecc
 
 import (
+	"crypto/rand"
 	"fmt"
 	"math/big"
 	"testing"
@@ -13,7 +14,7 @@ func TestSecp256k1_GeneratePrivateKey(t *testing.T) {
 
 	// perform ECDH
 	params := GetSecp256k1Parametes()
-	priv, _ := params.GeneratePrivateKey()
+	priv, _ := params.GeneratePrivateKey(rand.Reader)
 	priv.D.SetBytes(k.Bytes()) // Set the bytes so we can get the expected output
 	publicKey := priv.GeneratePublicKey()
 
@@ -54,10 +55,10 @@ func TestSecp256k1_ECDH(t *testing.T) {
 
 	// perform ECDH
 	params := GetSecp256k1Parametes()
-	priv1, _ := params.GeneratePrivateKey()
+	priv1, _ := params.GeneratePrivateKey(rand.Reader)
 	publicKey1 := priv1.GeneratePublicKey()
 
-	priv2, _ := params.GeneratePrivateKey()
+	priv2, _ := params.GeneratePrivateKey(rand.Reader)
 	publicKey2 := priv2.GeneratePublicKey()
 
 	sharedKey1 := priv1.ECDH(publicKey2)
@@ -1,7 +1,6 @@
 package ecc
 
 import (
-	"errors"
 	"math/big"
 )
 
@@ -24,37 +23,7 @@ func GetSecp256r1Parameters() *Secp256r1 {
 	return &curveParams
 }
 
-func (E *Secp256r1) IsValidPrivateKey(key *ECPrivateKey) bool {
-	// If 1 < key < n then valid else invalid
-	if (E.N.Cmp(key.D) == 1) && (key.D.Cmp(new(big.Int).SetInt64(0)) == 1) {
-		return true
-	}
-	return false
-}
-
-// GeneratePrivateKey generates at new 32 byte key
-func (E *Secp256r1) GeneratePrivateKey() (*ECPrivateKey, error) {
-
-	// Iterate till you find a valid private key
-	const MAX_ITER = 100
-	iter := 0
-	for {
-		iter += 1
-
-		// generate random 32 bytes
-		randomBytes, err := GenerateRandomBytes(32)
-
-		// Initialize private key
-		privateKey := ECPrivateKey{D: new(big.Int).SetBytes(randomBytes), curve: E.ECParams, PublicKey: &Point{}}
-
-		// Return if valid private key
-		if E.IsValidPrivateKey(&privateKey) {
-			return &privateKey, err
-		}
-
-		// Quit if you cannot generate valid private key after MAX_ITER
-		if iter == MAX_ITER {
-			return &ECPrivateKey{}, errors.New("MAX ITERATION REACHED. Cannot generate private key")
-		}
-	}
+// Name returns the standard name of the curve
+func (E *Secp256r1) Name() string {
+	return "P-256"
 }
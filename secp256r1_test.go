@@ -1,6 +1,7 @@
 package ecc
 
 import (
+	"crypto/rand"
 	"fmt"
 	"math/big"
 	"testing"
@@ -13,7 +14,7 @@ func TestSecp256r1_GeneratePrivateKey(t *testing.T) {
 
 	// perform ECDH
 	params := GetSecp256r1Parameters()
-	priv, _ := params.GeneratePrivateKey()
+	priv, _ := params.GeneratePrivateKey(rand.Reader)
 	priv.D.SetBytes(k.Bytes()) // Set the bytes so we can get the expected output
 	publicKey := priv.GeneratePublicKey()
 
@@ -54,10 +55,10 @@ func TestSecp256r1_ECDH(t *testing.T) {
 
 	// perform ECDH
 	params := GetSecp256r1Parameters()
-	priv1, _ := params.GeneratePrivateKey()
+	priv1, _ := params.GeneratePrivateKey(rand.Reader)
 	publicKey1 := priv1.GeneratePublicKey()
 
-	priv2, _ := params.GeneratePrivateKey()
+	priv2, _ := params.GeneratePrivateKey(rand.Reader)
 	publicKey2 := priv2.GeneratePublicKey()
 
 	sharedKey1 := priv1.ECDH(publicKey2)
@@ -78,7 +79,7 @@ func TestSecp256r1_ECDH(t *testing.T) {
 
 func Test_5(t *testing.T) {
 	params := GetSecp256r1Parameters()
-	private, _ := params.GeneratePrivateKey()
+	private, _ := params.GeneratePrivateKey(rand.Reader)
 	k, _ := new(big.Int).SetString("68723157890145320692495568116166642669112879877694933024822127787343477052557", 10)
 	private.D.SetBytes(k.Bytes())
 	publicKey := private.GeneratePublicKey()
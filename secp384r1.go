@@ -0,0 +1,33 @@
+package ecc
+
+import (
+	"crypto"
+	_ "crypto/sha512" // registers SHA-384 for crypto.SHA384
+	"math/big"
+)
+
+type Secp384r1 struct {
+	*ECParams
+}
+
+// GetSecp384r1Parameters returns the parameters of NIST P-384 (FIPS 186-4
+// D.1.2.4). Signatures on the curve hash messages with SHA-384.
+func GetSecp384r1Parameters() *Secp384r1 {
+	p, _ := new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFFFF0000000000000000FFFFFFFF", 16)
+	n, _ := new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFC7634D81F4372DDF581A0DB248B0A77AECEC196ACCC52973", 16)
+	b, _ := new(big.Int).SetString("B3312FA7E23EE7E4988E056BE3F82D19181D9C6EFE8141120314088F5013875AC656398D8A2ED19D2A85C8EDD3EC2AEF", 16)
+	a, _ := new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFFFF0000000000000000FFFFFFFC", 16)
+	gx, _ := new(big.Int).SetString("AA87CA22BE8B05378EB1C71EF320AD746E1D3B628BA79B9859F741E082542A385502F25DBF55296C3A545E3872760AB7", 16)
+	gy, _ := new(big.Int).SetString("3617DE4A96262C6F5D9E98BF9292DC29F8F41DBD289A147CE9DA3113B5F0B8C00A60B1CE1D7E819D7A431D7C90EA0E5F", 16)
+
+	var curveParams = Secp384r1{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}, Hash: crypto.SHA384},
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *Secp384r1) Name() string {
+	return "P-384"
+}
@@ -0,0 +1,76 @@
+package ecc
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestSecp384r1_ECDH_CAVP(t *testing.T) {
+
+	// NIST CAVS ECC CDH primitive test vector, P-384 COUNT = 0
+	qx, _ := new(big.Int).SetString("a7c76b970c3b5fe8b05d2838ae04ab47697b9eaf52e764592efda27fe7513272734466b400091adbf2d68c58e0c50066", 16)
+	qy, _ := new(big.Int).SetString("ac68f19f2e1cb879aed43a9969b91a0839c4c38a49749b661efedf243451915ed0905a32b060992b468c64766fc8437a", 16)
+	d, _ := new(big.Int).SetString("3cc3122a68f0d95027ad38c067916ba0eb8c38894d22e1b15618b6818a661774ad463b205da88cf699ab4d43c9cf98a1", 16)
+	expectedX, _ := new(big.Int).SetString("9803807f2f6d2fd966cdd0290bd410c0190352fbec7ff6247de1302df86f25d34fe4a97bef60cff548355c015dbb3e5f", 16)
+	expectedY, _ := new(big.Int).SetString("ba26ca69ec2f5b5d9dad20cc9da711383a9dbe34ea3fa5a2af75b46502629ad54dd8b7d73a8abb06a3a3be47d650cc99", 16)
+	expectedZ, _ := new(big.Int).SetString("5f9d29dc5e31a163060356213669c8ce132e22f57c9a04f40ba7fcead493b457e5621e766c40a2e3d4d6a04b25e533f1", 16)
+
+	params := GetSecp384r1Parameters()
+	priv := CreatePrivateKeyFromScalar(params.ECParams, d)
+	if !params.IsValidPrivateKey(priv) {
+		t.Fatalf("Expected private key %x to be valid", d)
+	}
+	publicKey := priv.GeneratePublicKey()
+	if publicKey.X.Cmp(expectedX) != 0 || publicKey.Y.Cmp(expectedY) != 0 {
+		t.Fatalf("Public key mismatch. Expected (%x, %x), Observed (%x, %x)", expectedX, expectedY, publicKey.X, publicKey.Y)
+	}
+
+	sharedKey := priv.ECDH(&Point{X: qx, Y: qy})
+	if sharedKey.X.Cmp(expectedZ) != 0 {
+		t.Fatalf("Shared secret mismatch. Expected %x, Observed %x", expectedZ, sharedKey.X)
+	}
+}
+
+func TestSecp384r1_SignRFC6979(t *testing.T) {
+
+	// RFC 6979 A.2.6, ECDSA with P-384 and SHA-384, message "sample"
+	d, _ := new(big.Int).SetString("6B9D3DAD2E1B8C1C05B19875B6659F4DE23C3B667BF297BA9AA47740787137D896D5724E4C70A825F872C9EA60D2EDF5", 16)
+	expectedR, _ := new(big.Int).SetString("94EDBB92A5ECB8AAD4736E56C691916B3F88140666CE9FA73D64C4EA95AD133C81A648152E44ACF96E36DD1E80FABE46", 16)
+	expectedS, _ := new(big.Int).SetString("99EF4AEB15F178CEA1FE40DB2603138F130E740A19624526203B6351D0A3A94FA329C145786E679E7B82C71A38628AC8", 16)
+
+	params := GetSecp384r1Parameters()
+	priv := CreatePrivateKeyFromScalar(params.ECParams, d)
+	signature := priv.Sign([]byte("sample"))
+	if signature.r.Cmp(expectedR) != 0 || signature.s.Cmp(expectedS) != 0 {
+		t.Fatalf("Signature mismatch. Expected (%x, %x), Observed (%x, %x)", expectedR, expectedS, signature.r, signature.s)
+	}
+	if !priv.GeneratePublicKey().Verify([]byte("sample"), signature, params.ECParams) {
+		t.Fatalf("Signature did not verify")
+	}
+	if priv.PublicKey.Verify([]byte("samplf"), signature, params.ECParams) {
+		t.Fatalf("Signature verified for a different message")
+	}
+}
+
+func TestSecp384r1_GeneratePrivateKey(t *testing.T) {
+	params := GetSecp384r1Parameters()
+	priv1, err := params.GeneratePrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate private key : %v", err)
+	}
+	priv2, _ := params.GeneratePrivateKey(rand.Reader)
+	if !params.IsValidPrivateKey(priv1) || priv1.D.Cmp(priv2.D) == 0 {
+		t.Fatalf("Generated private keys are invalid")
+	}
+
+	sharedKey1 := priv1.ECDH(priv2.GeneratePublicKey())
+	sharedKey2 := priv2.ECDH(priv1.GeneratePublicKey())
+	if sharedKey1.X.Cmp(sharedKey2.X) != 0 || sharedKey1.Y.Cmp(sharedKey2.Y) != 0 {
+		t.Fatalf("Shared keys differ. Expected %x, Observed %x", sharedKey1.X, sharedKey2.X)
+	}
+
+	if params.IsValidPrivateKey(&ECPrivateKey{D: new(big.Int).Set(params.N)}) {
+		t.Fatalf("Expected n to be an invalid private key")
+	}
+}
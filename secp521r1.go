@@ -0,0 +1,62 @@
+package ecc
+
+import (
+	"crypto"
+	_ "crypto/sha512" // registers SHA-512 for crypto.SHA512
+	"math/big"
+)
+
+type Secp521r1 struct {
+	*ECParams
+}
+
+// GetSecp521r1Parameters returns the parameters of NIST P-521 (FIPS 186-4
+// D.1.2.5). Signatures on the curve hash messages with SHA-512. Scalars and
+// coordinates are 521 bits long and encoded in 66 bytes.
+func GetSecp521r1Parameters() *Secp521r1 {
+	p, _ := new(big.Int).SetString("01FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF", 16)
+	n, _ := new(big.Int).SetString("01FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFA51868783BF2F966B7FCC0148F709A5D03BB5C9B8899C47AEBB6FB71E91386409", 16)
+	b, _ := new(big.Int).SetString("0051953EB9618E1C9A1F929A21A0B68540EEA2DA725B99B315F3B8B489918EF109E156193951EC7E937B1652C0BD3BB1BF073573DF883D2C34F1EF451FD46B503F00", 16)
+	a, _ := new(big.Int).SetString("01FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFC", 16)
+	gx, _ := new(big.Int).SetString("00C6858E06B70404E9CD9E3ECB662395B4429C648139053FB521F828AF606B4D3DBAA14B5E77EFE75928FE1DC127A2FFA8DE3348B3C1856A429BF97E7E31C2E5BD66", 16)
+	gy, _ := new(big.Int).SetString("011839296A789A3BC0045C8A5FB42C7D1BD998F54449579B446817AFBD17273E662C97EE72995EF42640C550B9013FAD0761353C7086A272C24088BE94769FD16650", 16)
+
+	var curveParams = Secp521r1{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}, Hash: crypto.SHA512},
+	}
+	curveParams.reduce = func(x *big.Int) *big.Int {
+		return reduceMersenne521(x, p)
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *Secp521r1) Name() string {
+	return "P-521"
+}
+
+// reduceMersenne521 reduces x modulo the Mersenne prime p = 2^521 - 1 in place.
+// Since 2^521 = 1 (mod p), the bits above 521 are folded back onto the low
+// bits with an addition instead of a division.
+func reduceMersenne521(x, p *big.Int) *big.Int {
+	if x.Sign() < 0 {
+		// -x mod p = p - (|x| mod p)
+		x.Neg(x)
+		reduceMersenne521(x, p)
+		if x.Sign() != 0 {
+			x.Sub(p, x)
+		}
+		return x
+	}
+	high := new(big.Int)
+	for x.BitLen() > 521 {
+		high.Rsh(x, 521)
+		x.And(x, p)
+		x.Add(x, high)
+	}
+	if x.Cmp(p) >= 0 {
+		x.Sub(x, p)
+	}
+	return x
+}
@@ -0,0 +1,122 @@
+package ecc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"math/big"
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestSecp521r1_ECDH_CAVP(t *testing.T) {
+
+	// NIST CAVS ECC CDH primitive test vector, P-521 COUNT = 0
+	qx, _ := new(big.Int).SetString("00685a48e86c79f0f0875f7bc18d25eb5fc8c0b07e5da4f4370f3a9490340854334b1e1b87fa395464c60626124a4e70d0f785601d37c09870ebf176666877a2046d", 16)
+	qy, _ := new(big.Int).SetString("01ba52c56fc8776d9e8f5db4f0cc27636d0b741bbe05400697942e80b739884a83bde99e0f6716939e632bc8986fa18dccd443a348b6c3e522497955a4f3c302f676", 16)
+	d, _ := new(big.Int).SetString("017eecc07ab4b329068fba65e56a1f8890aa935e57134ae0ffcce802735151f4eac6564f6ee9974c5e6887a1fefee5743ae2241bfeb95d5ce31ddcb6f9edb4d6fc47", 16)
+	expectedX, _ := new(big.Int).SetString("00602f9d0cf9e526b29e22381c203c48a886c2b0673033366314f1ffbcba240ba42f4ef38a76174635f91e6b4ed34275eb01c8467d05ca80315bf1a7bbd945f550a5", 16)
+	expectedY, _ := new(big.Int).SetString("01b7c85f26f5d4b2d7355cf6b02117659943762b6d1db5ab4f1dbc44ce7b2946eb6c7de342962893fd387d1b73d7a8672d1f236961170b7eb3579953ee5cdc88cd2d", 16)
+	expectedZ, _ := new(big.Int).SetString("005fc70477c3e63bc3954bd0df3ea0d1f41ee21746ed95fc5e1fdf90930d5e136672d72cc770742d1711c3c3a4c334a0ad9759436a4d3c5bf6e74b9578fac148c831", 16)
+
+	params := GetSecp521r1Parameters()
+	priv := CreatePrivateKeyFromScalar(params.ECParams, d)
+	publicKey := priv.GeneratePublicKey()
+	if publicKey.X.Cmp(expectedX) != 0 || publicKey.Y.Cmp(expectedY) != 0 {
+		t.Fatalf("Public key mismatch. Expected (%x, %x), Observed (%x, %x)", expectedX, expectedY, publicKey.X, publicKey.Y)
+	}
+
+	sharedKey := priv.ECDH(&Point{X: qx, Y: qy})
+	if sharedKey.X.Cmp(expectedZ) != 0 {
+		t.Fatalf("Shared secret mismatch. Expected %x, Observed %x", expectedZ, sharedKey.X)
+	}
+}
+
+func TestSecp521r1_SignRFC6979(t *testing.T) {
+
+	// RFC 6979 A.2.7, ECDSA with P-521 and SHA-512
+	d, _ := new(big.Int).SetString("0FAD06DAA62BA3B25D2FB40133DA757205DE67F5BB0018FEE8C86E1B68C7E75CAA896EB32F1F47C70855836A6D16FCC1466F6D8FBEC67DB89EC0C08B0E996B83538", 16)
+	vectors := []struct {
+		message, r, s string
+	}{
+		{"sample",
+			"0C328FAFCBD79DD77850370C46325D987CB525569FB63C5D3BC53950E6D4C5F174E25A1EE9017B5D450606ADD152B534931D7D4E8455CC91F9B15BF05EC36E377FA",
+			"0617CCE7CF5064806C467F678D3B4080D6F1CC50AF26CA209417308281B68AF282623EAA63E5B5C0723D8B8C37FF0777B1A20F8CCB1DCCC43997F1EE0E44DA4A67A"},
+		{"test",
+			"13E99020ABF5CEE7525D16B69B229652AB6BDF2AFFCAEF38773B4B7D08725F10CDB93482FDCC54EDCEE91ECA4166B2A7C6265EF0CE2BD7051B7CEF945BABD47EE6D",
+			"1FBD0013C674AA79CB39849527916CE301C66EA7CE8B80682786AD60F98F7E78A19CA69EFF5C57400E3B3A0AD66CE0978214D13BAF4E9AC60752F7B155E2DE4DCE3"},
+	}
+
+	params := GetSecp521r1Parameters()
+	priv := CreatePrivateKeyFromScalar(params.ECParams, d)
+	publicKey := priv.GeneratePublicKey()
+	for _, vector := range vectors {
+		expectedR, _ := new(big.Int).SetString(vector.r, 16)
+		expectedS, _ := new(big.Int).SetString(vector.s, 16)
+		signature := priv.Sign([]byte(vector.message))
+		if signature.r.Cmp(expectedR) != 0 || signature.s.Cmp(expectedS) != 0 {
+			t.Fatalf("Signature mismatch for %q. Expected (%x, %x), Observed (%x, %x)", vector.message, expectedR, expectedS, signature.r, signature.s)
+		}
+		if !publicKey.Verify([]byte(vector.message), signature, params.ECParams) {
+			t.Fatalf("Signature for %q did not verify", vector.message)
+		}
+	}
+}
+
+func TestSecp521r1_GeneratePrivateKey(t *testing.T) {
+	params := GetSecp521r1Parameters()
+	for i := 0; i < 20; i++ {
+		priv, err := params.GeneratePrivateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate private key : %v", err)
+		}
+		if !params.IsValidPrivateKey(priv) {
+			t.Fatalf("Generated private key %x is not below n", priv.D)
+		}
+	}
+}
+
+func TestSecp521r1_MersenneReduction(t *testing.T) {
+	p := GetSecp521r1Parameters().P
+	rng := mathrand.New(mathrand.NewSource(1))
+	bound := new(big.Int).Lsh(big.NewInt(1), 1100)
+	for i := 0; i < 1000; i++ {
+		x := new(big.Int).Rand(rng, bound)
+		if i%2 == 1 {
+			x.Neg(x)
+		}
+		expected := new(big.Int).Mod(x, p)
+		observed := reduceMersenne521(new(big.Int).Set(x), p)
+		if observed.Cmp(expected) != 0 {
+			t.Fatalf("Reduction of %x failed. Expected %x, Observed %x", x, expected, observed)
+		}
+	}
+	for _, x := range []*big.Int{big.NewInt(0), new(big.Int).Set(p), new(big.Int).Neg(p)} {
+		if reduceMersenne521(new(big.Int).Set(x), p).Sign() != 0 {
+			t.Fatalf("Expected %x to reduce to 0", x)
+		}
+	}
+}
+
+func TestSign_TruncatesLongHashes(t *testing.T) {
+
+	// SHA-512 hashes are longer than the P-256 order and must be truncated to
+	// its leftmost 256 bits, as crypto/ecdsa does
+	params := *GetSecp256r1Parameters().ECParams
+	params.Hash = crypto.SHA512
+	d, _ := new(big.Int).SetString("2d5a166ee81fff6c3bf30bf6a67f84cd8b56a2e7932f426d5976786d26373271", 16)
+	priv := CreatePrivateKeyFromScalar(&params, d)
+	publicKey := priv.GeneratePublicKey()
+	signature := priv.Sign([]byte("truncated"))
+
+	digest := sha512.Sum512([]byte("truncated"))
+	stdKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: publicKey.X, Y: publicKey.Y}
+	if !ecdsa.Verify(stdKey, digest[:], signature.r, signature.s) {
+		t.Fatalf("Signature over a truncated SHA-512 hash was rejected by crypto/ecdsa")
+	}
+	if !publicKey.Verify([]byte("truncated"), signature, &params) {
+		t.Fatalf("Signature over a truncated SHA-512 hash did not verify")
+	}
+}
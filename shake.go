@@ -0,0 +1,142 @@
+package ecc
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// keccakRoundConstants are the constants of the iota step of Keccak-f[1600]
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations are the rotation offsets of the rho step, indexed by x + 5y
+var keccakRotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccakF1600 applies the Keccak-f[1600] permutation of FIPS 202 to a state
+// of 25 lanes indexed by x + 5y
+func keccakF1600(a *[25]uint64) {
+	var b [25]uint64
+	var c [5]uint64
+	for round := 0; round < 24; round++ {
+		// theta
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[x+y] ^= d
+			}
+		}
+
+		// rho and pi
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRotations[x+5*y])
+			}
+		}
+
+		// chi
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[x+y] = b[x+y] ^ (^b[(x+1)%5+y] & b[(x+2)%5+y])
+			}
+		}
+
+		// iota
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
+
+// shake is the SHAKE256 extendable-output function of FIPS 202. Data is
+// written first, after which any amount of output can be read.
+type shake struct {
+	state     [25]uint64
+	rate      int
+	buf       []byte // absorbed bytes not yet permuted, or squeezed bytes not yet read
+	squeezing bool
+}
+
+// newShake256 returns a SHAKE256 instance with the rate of 136 bytes
+func newShake256() *shake {
+	return &shake{rate: 136}
+}
+
+// shake256 returns size bytes of SHAKE256 output for the concatenated parts
+func shake256(size int, parts ...[]byte) []byte {
+	s := newShake256()
+	for _, part := range parts {
+		s.Write(part)
+	}
+	out := make([]byte, size)
+	s.Read(out)
+	return out
+}
+
+// Write absorbs p. It panics when called after Read.
+func (s *shake) Write(p []byte) (int, error) {
+	if s.squeezing {
+		panic("ecc: SHAKE written after reading output")
+	}
+	s.buf = append(s.buf, p...)
+	for len(s.buf) >= s.rate {
+		s.absorb(s.buf[:s.rate])
+		s.buf = append(s.buf[:0], s.buf[s.rate:]...)
+	}
+	return len(p), nil
+}
+
+// Read squeezes len(out) bytes of output. It never fails.
+func (s *shake) Read(out []byte) (int, error) {
+	if !s.squeezing {
+		// Domain separation bits 1111 followed by the pad10*1 padding
+		block := make([]byte, s.rate)
+		copy(block, s.buf)
+		block[len(s.buf)] ^= 0x1f
+		block[s.rate-1] ^= 0x80
+		s.absorb(block)
+		s.buf = s.squeeze()
+		s.squeezing = true
+	}
+
+	n := len(out)
+	for len(out) > 0 {
+		if len(s.buf) == 0 {
+			keccakF1600(&s.state)
+			s.buf = s.squeeze()
+		}
+		copied := copy(out, s.buf)
+		s.buf = s.buf[copied:]
+		out = out[copied:]
+	}
+	return n, nil
+}
+
+// absorb XORs a block of rate bytes into the state and permutes it
+func (s *shake) absorb(block []byte) {
+	for i := 0; i < s.rate/8; i++ {
+		s.state[i] ^= binary.LittleEndian.Uint64(block[8*i:])
+	}
+	keccakF1600(&s.state)
+}
+
+// squeeze returns the first rate bytes of the state
+func (s *shake) squeeze() []byte {
+	out := make([]byte, s.rate)
+	for i := 0; i < s.rate/8; i++ {
+		binary.LittleEndian.PutUint64(out[8*i:], s.state[i])
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestShake256_KnownAnswers(t *testing.T) {
+	vectors := []struct {
+		input, expected string
+	}{
+		// FIPS 202 example values for the empty message
+		{"", "46b9dd2b0ba88d13233b3feb743eeb243fcd52ea62b81b82b50c27646ed5762fd75dc4ddd8c0f200cb05019d67b592f6fc821c49479ab48640292eacb3b7c4be"},
+		{"616263", "483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739d5a15bef186a5386c75744c0527e1faa9f8726e462a12a4feb06bd8801e751e4"},
+	}
+	for _, vector := range vectors {
+		input, _ := hex.DecodeString(vector.input)
+		if observed := shake256(64, input); hex.EncodeToString(observed) != vector.expected {
+			t.Fatalf("Expected %s, Observed %x", vector.expected, observed)
+		}
+	}
+}
+
+func TestShake256_Streaming(t *testing.T) {
+	// Inputs and outputs crossing the 136-byte rate must not depend on how
+	// they are split into calls
+	input := make([]byte, 500)
+	for i := range input {
+		input[i] = byte(i * 7)
+	}
+	expected := shake256(300, input)
+
+	for _, split := range []int{0, 1, 135, 136, 137, 272, 499} {
+		s := newShake256()
+		s.Write(input[:split])
+		s.Write(input[split:])
+		observed := make([]byte, 300)
+		s.Read(observed[:split%300])
+		s.Read(observed[split%300:])
+		if !bytes.Equal(observed, expected) {
+			t.Fatalf("Output differs when splitting at %d", split)
+		}
+	}
+}
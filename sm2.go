@@ -0,0 +1,260 @@
+package ecc
+
+import (
+	"crypto/subtle"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// OIDSM2 is the object identifier of the SM2 recommended curve
+var OIDSM2 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+
+// DefaultSM2UserID is the distinguishing identifier used by most SM2
+// implementations when the signer has none of its own
+var DefaultSM2UserID = []byte("1234567812345678")
+
+type SM2 struct {
+	*ECParams
+}
+
+// GetSM2Parameters returns the recommended curve of GB/T 32918.5
+func GetSM2Parameters() *SM2 {
+	p, _ := new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF", 16)
+	n, _ := new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123", 16)
+	b, _ := new(big.Int).SetString("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93", 16)
+	a, _ := new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFC", 16)
+	gx, _ := new(big.Int).SetString("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7", 16)
+	gy, _ := new(big.Int).SetString("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0", 16)
+
+	var curveParams = SM2{
+		ECParams: &ECParams{P: p, N: n, A: a, B: b, BasePoint: &Point{X: gx, Y: gy}},
+	}
+
+	return &curveParams
+}
+
+// Name returns the standard name of the curve
+func (E *SM2) Name() string {
+	return "SM2"
+}
+
+// sm2UserHash computes Z_A = SM3(ENTL_A || ID_A || a || b || xG || yG || xA || yA)
+// of GB/T 32918.2 section 5.5, which binds signatures to the identity and the
+// public key of the signer
+func sm2UserHash(ec *ECParams, publicKey *Point, id []byte) ([]byte, error) {
+	if len(id) >= 1<<13 {
+		return nil, errors.New("ecc: SM2 user identity is too long")
+	}
+	size := ec.coordinateSize()
+	var entl [2]byte
+	binary.BigEndian.PutUint16(entl[:], uint16(len(id)*8))
+
+	h := NewSM3()
+	h.Write(entl[:])
+	h.Write(id)
+	for _, v := range []*big.Int{ec.A, ec.B, ec.BasePoint.X, ec.BasePoint.Y, publicKey.X, publicKey.Y} {
+		h.Write(v.FillBytes(make([]byte, size)))
+	}
+	return h.Sum(nil), nil
+}
+
+// sm2MessageHash computes e = SM3(Z_A || M) as an integer
+func sm2MessageHash(ec *ECParams, publicKey *Point, id, message []byte) (*big.Int, error) {
+	za, err := sm2UserHash(ec, publicKey, id)
+	if err != nil {
+		return nil, err
+	}
+	h := NewSM3()
+	h.Write(za)
+	h.Write(message)
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
+
+// SignSM2 signs a message with the SM2 signature algorithm of GB/T 32918.2,
+// using the identity id of the signer, or DefaultSM2UserID when id is nil. The
+// nonce is read from rand, or crypto/rand when rand is nil.
+func (key *ECPrivateKey) SignSM2(rand io.Reader, id, message []byte) (*ECSignature, error) {
+	ec := key.curve
+	if ec == nil || !ec.IsValidPrivateKey(key) {
+		return nil, errors.New("ecc: invalid SM2 private key")
+	}
+	if id == nil {
+		id = DefaultSM2UserID
+	}
+	publicKey := key.PublicKey
+	if publicKey == nil || publicKey.X == nil {
+		publicKey = ScalarMult(key.D, ec.BasePoint, ec)
+	}
+
+	// (1 + d)^-1 does not exist for d = n - 1
+	dInv := new(big.Int).Add(key.D, big.NewInt(1))
+	if dInv.ModInverse(dInv, ec.N) == nil {
+		return nil, errors.New("ecc: invalid SM2 private key")
+	}
+	e, err := sm2MessageHash(ec, publicKey, id, message)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		k, err := ec.randomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		R := ScalarMult(k, ec.BasePoint, ec)
+
+		// r = (e + x1) mod n, retrying when r = 0 or r + k = n
+		r := new(big.Int).Add(e, R.X)
+		r.Mod(r, ec.N)
+		if r.Sign() == 0 || new(big.Int).Add(r, k).Cmp(ec.N) == 0 {
+			continue
+		}
+
+		// s = (1 + d)^-1 * (k - r*d) mod n
+		s := new(big.Int).Mul(r, key.D)
+		s.Sub(k, s).Mul(s, dInv).Mod(s, ec.N)
+		if s.Sign() == 0 {
+			continue
+		}
+		return &ECSignature{r: r, s: s, curve: ec}, nil
+	}
+}
+
+// VerifySM2 checks an SM2 signature made by SignSM2 with the same identity
+func (publicKey *Point) VerifySM2(message, id []byte, signature *ECSignature, params *ECParams) bool {
+	if signature == nil || signature.r == nil || signature.s == nil {
+		return false
+	}
+	if signature.curve != nil && !sameCurve(signature.curve, params) {
+		return false
+	}
+	if signature.r.Sign() <= 0 || signature.r.Cmp(params.N) >= 0 || signature.s.Sign() <= 0 || signature.s.Cmp(params.N) >= 0 {
+		return false
+	}
+	if !params.IsOnCurve(publicKey) {
+		return false
+	}
+	if id == nil {
+		id = DefaultSM2UserID
+	}
+	e, err := sm2MessageHash(params, publicKey, id, message)
+	if err != nil {
+		return false
+	}
+
+	// t = (r + s) mod n must not be zero
+	t := new(big.Int).Add(signature.r, signature.s)
+	t.Mod(t, params.N)
+	if t.Sign() == 0 {
+		return false
+	}
+
+	// R = (e + x1) mod n for (x1, y1) = s*G + t*P
+	P := addPoints(ScalarMult(signature.s, params.BasePoint, params), ScalarMult(t, publicKey, params), params)
+	if P.X.Sign() == 0 && P.Y.Sign() == 0 {
+		return false
+	}
+	R := new(big.Int).Add(e, P.X)
+	return R.Mod(R, params.N).Cmp(signature.r) == 0
+}
+
+// sm2KDF derives length bytes from z with the key derivation function of
+// GB/T 32918.4 section 5.4.3, SM3(z || ct) for a 32-bit counter ct from 1
+func sm2KDF(z []byte, length int) []byte {
+	out := make([]byte, 0, length+SM3Size)
+	var ct [4]byte
+	for counter := uint32(1); len(out) < length; counter++ {
+		binary.BigEndian.PutUint32(ct[:], counter)
+		h := NewSM3()
+		h.Write(z)
+		h.Write(ct[:])
+		out = h.Sum(out)
+	}
+	return out[:length]
+}
+
+// EncryptSM2 encrypts a message to the public key with the SM2 public key
+// encryption of GB/T 32918.4. The ciphertext is C1 || C3 || C2 with C1 the
+// uncompressed ephemeral point, C3 the SM3 check value and C2 the masked
+// message. The ephemeral scalar is read from rand, or crypto/rand when rand is
+// nil.
+func (publicKey *Point) EncryptSM2(rand io.Reader, message []byte, params *ECParams) ([]byte, error) {
+	if !params.IsOnCurve(publicKey) {
+		return nil, errors.New("ecc: SM2 public key is not on the curve")
+	}
+	if params.hasSmallOrder(publicKey) {
+		return nil, errors.New("ecc: SM2 public key has small order")
+	}
+	size := params.coordinateSize()
+
+	for {
+		k, err := params.randomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		C1 := ScalarMult(k, params.BasePoint, params)
+		S := ScalarMult(k, publicKey, params)
+		x2 := S.X.FillBytes(make([]byte, size))
+		y2 := S.Y.FillBytes(make([]byte, size))
+
+		// An all-zero mask would leave the message in the clear
+		t := sm2KDF(append(x2, y2...), len(message))
+		if len(message) > 0 && subtle.ConstantTimeCompare(t, make([]byte, len(t))) == 1 {
+			continue
+		}
+		C2 := make([]byte, len(message))
+		subtle.XORBytes(C2, message, t)
+
+		h := NewSM3()
+		h.Write(x2)
+		h.Write(message)
+		h.Write(y2)
+
+		ciphertext := params.marshalUncompressed(C1)
+		ciphertext = h.Sum(ciphertext)
+		return append(ciphertext, C2...), nil
+	}
+}
+
+// DecryptSM2 decrypts a C1 || C3 || C2 ciphertext created by EncryptSM2
+func (key *ECPrivateKey) DecryptSM2(ciphertext []byte) ([]byte, error) {
+	ec := key.curve
+	if ec == nil || !ec.IsValidPrivateKey(key) {
+		return nil, errors.New("ecc: invalid SM2 private key")
+	}
+	size := ec.coordinateSize()
+	if len(ciphertext) < 1+2*size+SM3Size {
+		return nil, errors.New("ecc: SM2 ciphertext is too short")
+	}
+	C1, err := ec.unmarshalUncompressed(ciphertext[:1+2*size])
+	if err != nil {
+		return nil, errors.New("ecc: invalid SM2 ciphertext")
+	}
+	if ec.hasSmallOrder(C1) {
+		return nil, errors.New("ecc: invalid SM2 ciphertext")
+	}
+	C3 := ciphertext[1+2*size : 1+2*size+SM3Size]
+	C2 := ciphertext[1+2*size+SM3Size:]
+
+	S := ScalarMult(key.D, C1, ec)
+	x2 := S.X.FillBytes(make([]byte, size))
+	y2 := S.Y.FillBytes(make([]byte, size))
+	t := sm2KDF(append(x2, y2...), len(C2))
+	if len(C2) > 0 && subtle.ConstantTimeCompare(t, make([]byte, len(t))) == 1 {
+		return nil, errors.New("ecc: invalid SM2 ciphertext")
+	}
+	message := make([]byte, len(C2))
+	subtle.XORBytes(message, C2, t)
+
+	h := NewSM3()
+	h.Write(x2)
+	h.Write(message)
+	h.Write(y2)
+	if subtle.ConstantTimeCompare(h.Sum(nil), C3) != 1 {
+		return nil, errors.New("ecc: SM2 ciphertext check value does not match")
+	}
+	return message, nil
+}
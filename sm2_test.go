@@ -0,0 +1,168 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// sm2TestCurve returns the 256-bit prime field curve used by the examples of
+// GB/T 32918 appendix A
+func sm2TestCurve() *ECParams {
+	p, _ := new(big.Int).SetString("8542D69E4C044F18E8B92435BF6FF7DE457283915C45517D722EDB8B08F1DFC3", 16)
+	a, _ := new(big.Int).SetString("787968B4FA32C3FD2417842E73BBFEFF2F3C848B6831D7E0EC65228B3937E498", 16)
+	b, _ := new(big.Int).SetString("63E4C6D3B23B0C849CF84241484BFE48F61D59A5B16BA06E6E12D1DA27C5249A", 16)
+	gx, _ := new(big.Int).SetString("421DEBD61B62EAB6746434EBC3CC315E32220B3BADD50BDC4C4E6C147FEDD43D", 16)
+	gy, _ := new(big.Int).SetString("0680512BCBB42C07D47349D2153B70C4E5D7FDFCBFA36EA1A85841B9E46E09A2", 16)
+	n, _ := new(big.Int).SetString("8542D69E4C044F18E8B92435BF6FF7DD297720630485628D5AE74EE7C32E79B7", 16)
+	return &ECParams{P: p, A: a, B: b, N: n, BasePoint: &Point{X: gx, Y: gy}}
+}
+
+func TestSM2_Parameters(t *testing.T) {
+	params := GetSM2Parameters().ECParams
+	if !params.IsOnCurve(params.BasePoint) {
+		t.Fatalf("Base point is not on the SM2 curve")
+	}
+	if R := ScalarMult(params.N, params.BasePoint, params); R.X.Sign() != 0 || R.Y.Sign() != 0 {
+		t.Fatalf("Base point does not have order N")
+	}
+	curve, err := LookupCurveByOID(OIDSM2)
+	if err != nil || curve.Name != "SM2" {
+		t.Fatalf("SM2 is not registered under its OID : %v", err)
+	}
+}
+
+func TestSM2_SignatureExample(t *testing.T) {
+
+	// GB/T 32918.2 appendix A.2
+	params := sm2TestCurve()
+	d, _ := new(big.Int).SetString("128B2FA8BD433C6C068C8D803DFF79792A519A55171B1B650C23661D15897263", 16)
+	k, _ := hex.DecodeString("6CB28D99385C175C94F94E934817663FC176D925DD72B727260DBAAE1FB2F96F")
+	id := []byte("ALICE123@YAHOO.COM")
+	message := []byte("message digest")
+
+	privateKey := CreatePrivateKeyFromScalar(params, d)
+	publicKey := privateKey.GeneratePublicKey()
+	expectedX := "0ae4c7798aa0f119471bee11825be46202bb79e2a5844495e97c04ff4df2548a"
+	if hex.EncodeToString(publicKey.X.Bytes()) != expectedX {
+		t.Fatalf("Unexpected public key. Expected %s, Observed %x", expectedX, publicKey.X)
+	}
+
+	za, _ := sm2UserHash(params, publicKey, id)
+	expectedZA := "f4a38489e32b45b6f876e3ac2168ca392362dc8f23459c1d1146fc3dbfb7bc9a"
+	if hex.EncodeToString(za) != expectedZA {
+		t.Fatalf("Unexpected Z_A. Expected %s, Observed %x", expectedZA, za)
+	}
+
+	signature, err := privateKey.SignSM2(bytes.NewReader(k), id, message)
+	if err != nil {
+		t.Fatalf("Failed to sign : %v", err)
+	}
+	expectedR := "40f1ec59f793d9f49e09dcef49130d4194f79fb1eed2caa55bacdb49c4e755d1"
+	expectedS := "6fc6dac32c5d5cf10c77dfb20f7c2eb667a457872fb09ec56327a67ec7deebe7"
+	if hex.EncodeToString(signature.r.Bytes()) != expectedR || hex.EncodeToString(signature.s.Bytes()) != expectedS {
+		t.Fatalf("Unexpected signature. Expected (%s, %s), Observed (%x, %x)", expectedR, expectedS, signature.r, signature.s)
+	}
+	if !publicKey.VerifySM2(message, id, signature, params) {
+		t.Fatalf("Standard signature does not verify")
+	}
+	if publicKey.VerifySM2(message, []byte("BILL456@YAHOO.COM"), signature, params) {
+		t.Fatalf("Signature verified with a different identity")
+	}
+}
+
+func TestSM2_EncryptionExample(t *testing.T) {
+
+	// GB/T 32918.4 appendix A.2
+	params := sm2TestCurve()
+	d, _ := new(big.Int).SetString("1649AB77A00637BD5E2EFE283FBF353534AA7F7CB89463F208DDBC2920BB0DA0", 16)
+	k, _ := hex.DecodeString("4C62EEFD6ECFC2B95B92FD6C3D9575148AFA17425546D49018E5388D49DD7B4F")
+	message := []byte("encryption standard")
+
+	privateKey := CreatePrivateKeyFromScalar(params, d)
+	publicKey := privateKey.GeneratePublicKey()
+
+	ciphertext, err := publicKey.EncryptSM2(bytes.NewReader(k), message, params)
+	if err != nil {
+		t.Fatalf("Failed to encrypt : %v", err)
+	}
+	expected := "04" +
+		"245c26fb68b1ddddb12c4b6bf9f2b6d5fe60a383b0d18d1c4144abf17f6252e7" +
+		"76cb9264c2a7e88e52b19903fdc47378f605e36811f5c07423a24b84400f01b8" +
+		"9c3d7360c30156fab7c80a0276712da9d8094a634b766d3a285e07480653426d" +
+		"650053a89b41c418b0c3aad00d886c00286467"
+	if hex.EncodeToString(ciphertext) != expected {
+		t.Fatalf("Unexpected ciphertext. Expected %s, Observed %x", expected, ciphertext)
+	}
+
+	decrypted, err := privateKey.DecryptSM2(ciphertext)
+	if err != nil {
+		t.Fatalf("Failed to decrypt : %v", err)
+	}
+	if !bytes.Equal(decrypted, message) {
+		t.Fatalf("Expected %s, Observed %s", message, decrypted)
+	}
+}
+
+func TestSM2_RecommendedCurve(t *testing.T) {
+	params := GetSM2Parameters().ECParams
+	privateKey, err := params.GeneratePrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate private key : %v", err)
+	}
+	publicKey := privateKey.PublicKey
+
+	signature, err := privateKey.SignSM2(nil, nil, []byte("Hello 123"))
+	if err != nil {
+		t.Fatalf("Failed to sign : %v", err)
+	}
+	if !publicKey.VerifySM2([]byte("Hello 123"), DefaultSM2UserID, signature, params) {
+		t.Fatalf("Signature does not verify")
+	}
+	if publicKey.VerifySM2([]byte("Hello 124"), nil, signature, params) {
+		t.Fatalf("Signature verified for a different message")
+	}
+
+	message := []byte("Hello 123, a message longer than a single SM3 output block")
+	ciphertext, err := publicKey.EncryptSM2(nil, message, params)
+	if err != nil {
+		t.Fatalf("Failed to encrypt : %v", err)
+	}
+	if len(ciphertext) != 1+64+SM3Size+len(message) {
+		t.Fatalf("Unexpected ciphertext length %d", len(ciphertext))
+	}
+	decrypted, err := privateKey.DecryptSM2(ciphertext)
+	if err != nil || !bytes.Equal(decrypted, message) {
+		t.Fatalf("Failed to decrypt : %v", err)
+	}
+
+	// Tampering with C2 is detected by C3
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err := privateKey.DecryptSM2(ciphertext); err == nil {
+		t.Fatalf("Expected error for a tampered ciphertext")
+	}
+	if _, err := privateKey.DecryptSM2(ciphertext[:90]); err == nil {
+		t.Fatalf("Expected error for a short ciphertext")
+	}
+}
+
+func TestSM2_RejectsInvalidKeys(t *testing.T) {
+	params := GetSM2Parameters().ECParams
+	privateKey, _ := params.GeneratePrivateKey(rand.Reader)
+	ciphertext, _ := privateKey.PublicKey.EncryptSM2(nil, []byte("Hello 123"), params)
+
+	for _, key := range []*ECPrivateKey{
+		{D: privateKey.D},
+		{D: big.NewInt(0), curve: params},
+		{D: params.N, curve: params},
+	} {
+		if _, err := key.SignSM2(nil, nil, []byte("Hello 123")); err == nil {
+			t.Fatalf("Expected error when signing with d = %x", key.D)
+		}
+		if _, err := key.DecryptSM2(ciphertext); err == nil {
+			t.Fatalf("Expected error when decrypting with d = %x", key.D)
+		}
+	}
+}
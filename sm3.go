@@ -0,0 +1,153 @@
+package ecc
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// SM3Size is the size of an SM3 checksum in bytes
+	SM3Size = 32
+
+	// SM3BlockSize is the block size of SM3 in bytes
+	SM3BlockSize = 64
+)
+
+// sm3IV is the initial value of the chaining variable, GB/T 32905 section 4.1
+var sm3IV = [8]uint32{0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600, 0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e}
+
+// sm3Digest computes the SM3 hash function of GB/T 32905
+type sm3Digest struct {
+	h   [8]uint32
+	x   [SM3BlockSize]byte
+	nx  int
+	len uint64
+}
+
+// NewSM3 returns a new hash.Hash computing the SM3 checksum
+func NewSM3() hash.Hash {
+	d := new(sm3Digest)
+	d.Reset()
+	return d
+}
+
+// SumSM3 returns the SM3 checksum of the data
+func SumSM3(data []byte) [SM3Size]byte {
+	d := new(sm3Digest)
+	d.Reset()
+	d.Write(data)
+	var sum [SM3Size]byte
+	d.Sum(sum[:0])
+	return sum
+}
+
+func (d *sm3Digest) Reset() {
+	d.h = sm3IV
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *sm3Digest) Size() int {
+	return SM3Size
+}
+
+func (d *sm3Digest) BlockSize() int {
+	return SM3BlockSize
+}
+
+func (d *sm3Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		copied := copy(d.x[d.nx:], p)
+		d.nx += copied
+		p = p[copied:]
+		if d.nx < SM3BlockSize {
+			return n, nil
+		}
+		d.block(d.x[:])
+		d.nx = 0
+	}
+	for len(p) >= SM3BlockSize {
+		d.block(p[:SM3BlockSize])
+		p = p[SM3BlockSize:]
+	}
+	d.nx = copy(d.x[:], p)
+	return n, nil
+}
+
+// Sum appends the checksum to b without changing the state of d
+func (d *sm3Digest) Sum(b []byte) []byte {
+	d0 := *d
+
+	// Padding: a one bit, zeros up to 56 bytes modulo 64 and the length in bits
+	var tmp [SM3BlockSize + 8]byte
+	tmp[0] = 0x80
+	padding := 56 - int(d0.len%SM3BlockSize)
+	if padding <= 0 {
+		padding += SM3BlockSize
+	}
+	binary.BigEndian.PutUint64(tmp[padding:], d0.len*8)
+	d0.Write(tmp[:padding+8])
+
+	var sum [SM3Size]byte
+	for i, v := range d0.h {
+		binary.BigEndian.PutUint32(sum[4*i:], v)
+	}
+	return append(b, sum[:]...)
+}
+
+// block runs the compression function of GB/T 32905 section 5.3 on one block
+func (d *sm3Digest) block(p []byte) {
+	var w [68]uint32
+	for j := 0; j < 16; j++ {
+		w[j] = binary.BigEndian.Uint32(p[4*j:])
+	}
+	for j := 16; j < 68; j++ {
+		w[j] = sm3P1(w[j-16]^w[j-9]^bits.RotateLeft32(w[j-3], 15)) ^ bits.RotateLeft32(w[j-13], 7) ^ w[j-6]
+	}
+
+	a, b, c, dd, e, f, g, h := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
+	for j := 0; j < 64; j++ {
+		var t, ff, gg uint32
+		if j < 16 {
+			t = 0x79cc4519
+			ff = a ^ b ^ c
+			gg = e ^ f ^ g
+		} else {
+			t = 0x7a879d8a
+			ff = (a & b) | (a & c) | (b & c)
+			gg = (e & f) | (^e & g)
+		}
+		ss1 := bits.RotateLeft32(bits.RotateLeft32(a, 12)+e+bits.RotateLeft32(t, j%32), 7)
+		ss2 := ss1 ^ bits.RotateLeft32(a, 12)
+		tt1 := ff + dd + ss2 + (w[j] ^ w[j+4])
+		tt2 := gg + h + ss1 + w[j]
+		dd = c
+		c = bits.RotateLeft32(b, 9)
+		b = a
+		a = tt1
+		h = g
+		g = bits.RotateLeft32(f, 19)
+		f = e
+		e = sm3P0(tt2)
+	}
+
+	d.h[0] ^= a
+	d.h[1] ^= b
+	d.h[2] ^= c
+	d.h[3] ^= dd
+	d.h[4] ^= e
+	d.h[5] ^= f
+	d.h[6] ^= g
+	d.h[7] ^= h
+}
+
+func sm3P0(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 9) ^ bits.RotateLeft32(x, 17)
+}
+
+func sm3P1(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23)
+}
@@ -0,0 +1,51 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSM3_StandardExamples(t *testing.T) {
+
+	// GB/T 32905 appendix A
+	vectors := []struct {
+		message, expected string
+	}{
+		{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+	}
+	for _, vector := range vectors {
+		sum := SumSM3([]byte(vector.message))
+		if hex.EncodeToString(sum[:]) != vector.expected {
+			t.Fatalf("Expected %s, Observed %x", vector.expected, sum)
+		}
+	}
+}
+
+func TestSM3_Streaming(t *testing.T) {
+	message := bytes.Repeat([]byte("0123456789"), 30)
+	expected := SumSM3(message)
+
+	for _, split := range []int{0, 1, 55, 56, 63, 64, 65, 128, 299} {
+		h := NewSM3()
+		h.Write(message[:split])
+		h.Write(message[split:])
+		if observed := h.Sum(nil); !bytes.Equal(observed, expected[:]) {
+			t.Fatalf("Checksum differs when splitting at %d", split)
+		}
+	}
+
+	// Sum does not change the state
+	h := NewSM3()
+	h.Write(message[:100])
+	h.Sum(nil)
+	h.Write(message[100:])
+	if observed := h.Sum(nil); !bytes.Equal(observed, expected[:]) {
+		t.Fatalf("Sum changed the state of the hash")
+	}
+	if h.Size() != SM3Size || h.BlockSize() != SM3BlockSize {
+		t.Fatalf("Unexpected sizes %d and %d", h.Size(), h.BlockSize())
+	}
+}
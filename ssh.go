@@ -0,0 +1,351 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// SSH encodings of ECDSA keys and signatures (RFC 4251, RFC 5656) and of the
+// OpenSSH "openssh-key-v1" private key container (PROTOCOL.key in OpenSSH)
+
+const (
+	sshKeyAlgorithmPrefix = "ecdsa-sha2-"
+	sshPrivateKeyMagic    = "openssh-key-v1\x00"
+	sshPrivateKeyPEMType  = "OPENSSH PRIVATE KEY"
+)
+
+// MarshalSSHPublicKey encodes the public key in the SSH wire format of RFC 5656
+// section 3.1, e.g. for the "ecdsa-sha2-nistp256" key type.
+func (publicKey *Point) MarshalSSHPublicKey() ([]byte, error) {
+	curve := lookupCurveOfPoint(publicKey)
+	if curve == nil || curve.SSH == "" {
+		return nil, errors.New("ssh: point is not on a curve supported by SSH")
+	}
+	return marshalSSHPublicKey(curve, publicKey), nil
+}
+
+// ParseSSHPublicKey decodes a public key in the SSH wire format and returns it
+// together with its curve parameters
+func ParseSSHPublicKey(blob []byte) (*Point, *ECParams, error) {
+	r := &sshReader{data: blob}
+	curve, publicKey, err := r.readPublicKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(r.data) != 0 {
+		return nil, nil, errors.New("ssh: trailing data after public key")
+	}
+	return publicKey, curve.Params, nil
+}
+
+// MarshalAuthorizedKey encodes the public key as a line of an OpenSSH
+// authorized_keys file
+func (publicKey *Point) MarshalAuthorizedKey(comment string) ([]byte, error) {
+	blob, err := publicKey.MarshalSSHPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	curve := lookupCurveOfPoint(publicKey)
+
+	line := sshKeyAlgorithmPrefix + curve.SSH + " " + base64.StdEncoding.EncodeToString(blob)
+	if comment != "" {
+		line += " " + comment
+	}
+	return []byte(line + "\n"), nil
+}
+
+// ParseAuthorizedKey decodes a line of an authorized_keys file holding an ECDSA
+// key and returns the key, its curve parameters and the comment. Lines with
+// options in front of the key type are not supported.
+func ParseAuthorizedKey(line []byte) (*Point, *ECParams, string, error) {
+	fields := bytes.Fields(line)
+	if len(fields) < 2 {
+		return nil, nil, "", errors.New("ssh: authorized key line must contain a key type and a key")
+	}
+	blob, err := base64.StdEncoding.DecodeString(string(fields[1]))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("ssh: invalid key encoding: %w", err)
+	}
+	publicKey, params, err := ParseSSHPublicKey(blob)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if curve := lookupCurve(params); string(fields[0]) != sshKeyAlgorithmPrefix+curve.SSH {
+		return nil, nil, "", fmt.Errorf("ssh: key type %q does not match the encoded key", fields[0])
+	}
+
+	comment := ""
+	if len(fields) > 2 {
+		comment = string(bytes.Join(fields[2:], []byte(" ")))
+	}
+	return publicKey, params, comment, nil
+}
+
+// MarshalOpenSSHPrivateKey encodes the private key as an unencrypted
+// "openssh-key-v1" container wrapped in a PEM block, as written by ssh-keygen
+func (key *ECPrivateKey) MarshalOpenSSHPrivateKey(comment string) ([]byte, error) {
+	curve := lookupCurve(key.curve)
+	if curve == nil || curve.SSH == "" {
+		return nil, errors.New("ssh: private key is not on a curve supported by SSH")
+	}
+	if key.D == nil || key.D.Sign() <= 0 || key.D.Cmp(key.curve.N) >= 0 {
+		return nil, errors.New("ssh: private key is out of range")
+	}
+	publicKey := ScalarMult(key.D, key.curve.BasePoint, key.curve)
+
+	// The two check integers let readers detect a wrong decryption passphrase
+	check, err := GenerateRandomBytes(4)
+	if err != nil {
+		return nil, err
+	}
+	var private []byte
+	private = append(private, check...)
+	private = append(private, check...)
+	private = appendSSHString(private, []byte(sshKeyAlgorithmPrefix+curve.SSH))
+	private = appendSSHString(private, []byte(curve.SSH))
+	private = appendSSHString(private, key.curve.marshalUncompressed(publicKey))
+	private = appendSSHMpint(private, key.D)
+	private = appendSSHString(private, []byte(comment))
+	for i := byte(1); len(private)%8 != 0; i++ {
+		private = append(private, i)
+	}
+
+	var container []byte
+	container = append(container, sshPrivateKeyMagic...)
+	container = appendSSHString(container, []byte("none")) // cipher
+	container = appendSSHString(container, []byte("none")) // kdf
+	container = appendSSHString(container, nil)            // kdf options
+	container = binary.BigEndian.AppendUint32(container, 1)
+	container = appendSSHString(container, marshalSSHPublicKey(curve, publicKey))
+	container = appendSSHString(container, private)
+
+	return pem.EncodeToMemory(&pem.Block{Type: sshPrivateKeyPEMType, Bytes: container}), nil
+}
+
+// ParseOpenSSHPrivateKey decodes an unencrypted PEM encoded "openssh-key-v1"
+// private key and returns the key and its comment
+func ParseOpenSSHPrivateKey(data []byte) (*ECPrivateKey, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != sshPrivateKeyPEMType {
+		return nil, "", errors.New("ssh: no OPENSSH PRIVATE KEY block found")
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(sshPrivateKeyMagic)) {
+		return nil, "", errors.New("ssh: invalid private key magic")
+	}
+	r := &sshReader{data: block.Bytes[len(sshPrivateKeyMagic):]}
+
+	cipher := r.readString()
+	kdf := r.readString()
+	r.readString() // kdf options
+	count := r.readUint32()
+	publicBlob := r.readString()
+	private := r.readString()
+	if r.err != nil {
+		return nil, "", r.err
+	}
+	if string(cipher) != "none" || string(kdf) != "none" {
+		return nil, "", fmt.Errorf("ssh: encrypted private keys (cipher %q) are not supported", cipher)
+	}
+	if count != 1 {
+		return nil, "", fmt.Errorf("ssh: expected a single key, found %d", count)
+	}
+	publicKey, params, err := ParseSSHPublicKey(publicBlob)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r = &sshReader{data: private}
+	check1 := r.readUint32()
+	check2 := r.readUint32()
+	if r.err == nil && check1 != check2 {
+		return nil, "", errors.New("ssh: private key check integers do not match")
+	}
+	curve, privatePublicKey, err := r.readPublicKey()
+	if err != nil {
+		return nil, "", err
+	}
+	d := r.readMpint()
+	comment := r.readString()
+	if r.err != nil {
+		return nil, "", r.err
+	}
+	for i, b := range r.data {
+		if b != byte(i+1) {
+			return nil, "", errors.New("ssh: invalid private key padding")
+		}
+	}
+
+	if !sameCurve(curve.Params, params) || privatePublicKey.X.Cmp(publicKey.X) != 0 || privatePublicKey.Y.Cmp(publicKey.Y) != 0 {
+		return nil, "", errors.New("ssh: public and private sections hold different keys")
+	}
+	if d.Sign() <= 0 || d.Cmp(params.N) >= 0 {
+		return nil, "", errors.New("ssh: private key is out of range")
+	}
+	derived := ScalarMult(d, params.BasePoint, params)
+	if derived.X.Cmp(publicKey.X) != 0 || derived.Y.Cmp(publicKey.Y) != 0 {
+		return nil, "", errors.New("ssh: public key does not match private key")
+	}
+
+	return &ECPrivateKey{D: d, curve: params, PublicKey: publicKey}, string(comment), nil
+}
+
+// MarshalSSH encodes the signature as an SSH signature blob (RFC 5656 section
+// 3.1.2): the key algorithm name followed by the mpints r and s. params
+// selects the algorithm name. The algorithm fixes the hash function, so
+// signatures made with parameters whose Hash differs are rejected.
+func (signature *ECSignature) MarshalSSH(params *ECParams) ([]byte, error) {
+	curve, err := lookupSSHCurve(params)
+	if err != nil {
+		return nil, err
+	}
+	if signature.curve != nil && signature.curve.signatureHash() != curve.Params.signatureHash() {
+		return nil, errors.New("ssh: signature hash does not match the SSH algorithm")
+	}
+	var inner []byte
+	inner = appendSSHMpint(inner, signature.r)
+	inner = appendSSHMpint(inner, signature.s)
+
+	var blob []byte
+	blob = appendSSHString(blob, []byte(sshKeyAlgorithmPrefix+curve.SSH))
+	blob = appendSSHString(blob, inner)
+	return blob, nil
+}
+
+// ParseSSHSignature decodes an SSH ECDSA signature blob. The algorithm name
+// must match params, whose hash function must be the one of the algorithm.
+func ParseSSHSignature(blob []byte, params *ECParams) (*ECSignature, error) {
+	curve, err := lookupSSHCurve(params)
+	if err != nil {
+		return nil, err
+	}
+	r := &sshReader{data: blob}
+	algorithm := r.readString()
+	inner := r.readString()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if string(algorithm) != sshKeyAlgorithmPrefix+curve.SSH {
+		return nil, fmt.Errorf("ssh: unexpected signature algorithm %q", algorithm)
+	}
+	if len(r.data) != 0 {
+		return nil, errors.New("ssh: trailing data after signature")
+	}
+
+	r = &sshReader{data: inner}
+	signature := &ECSignature{r: r.readMpint(), s: r.readMpint(), curve: params}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, errors.New("ssh: trailing data after signature")
+	}
+	return signature, nil
+}
+
+// lookupSSHCurve returns the registered curve of params for SSH signatures,
+// checking that params hash with the function of the ecdsa-sha2 algorithm
+func lookupSSHCurve(params *ECParams) (*CurveInfo, error) {
+	curve := lookupCurve(params)
+	if curve == nil || curve.SSH == "" {
+		return nil, errors.New("ssh: curve is not supported by SSH")
+	}
+	if params.signatureHash() != curve.Params.signatureHash() {
+		return nil, errors.New("ssh: hash of the curve parameters does not match the SSH algorithm")
+	}
+	return curve, nil
+}
+
+func marshalSSHPublicKey(curve *CurveInfo, publicKey *Point) []byte {
+	var blob []byte
+	blob = appendSSHString(blob, []byte(sshKeyAlgorithmPrefix+curve.SSH))
+	blob = appendSSHString(blob, []byte(curve.SSH))
+	blob = appendSSHString(blob, curve.Params.marshalUncompressed(publicKey))
+	return blob
+}
+
+func appendSSHString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// appendSSHMpint appends a non-negative integer as an SSH mpint: big-endian
+// two's complement with a leading zero byte if the top bit is set
+func appendSSHMpint(b []byte, x *big.Int) []byte {
+	encoded := x.Bytes()
+	if len(encoded) > 0 && encoded[0]&0x80 != 0 {
+		encoded = append([]byte{0}, encoded...)
+	}
+	return appendSSHString(b, encoded)
+}
+
+// sshReader decodes SSH wire format fields. The first error is kept in err and
+// makes later reads return zero values.
+type sshReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sshReader) readUint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 4 {
+		r.err = errors.New("ssh: unexpected end of data")
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+func (r *sshReader) readString() []byte {
+	length := r.readUint32()
+	if r.err != nil {
+		return nil
+	}
+	if uint64(length) > uint64(len(r.data)) {
+		r.err = errors.New("ssh: unexpected end of data")
+		return nil
+	}
+	s := r.data[:length]
+	r.data = r.data[length:]
+	return s
+}
+
+func (r *sshReader) readMpint() *big.Int {
+	encoded := r.readString()
+	if r.err != nil {
+		return new(big.Int)
+	}
+	if len(encoded) > 0 && encoded[0]&0x80 != 0 {
+		r.err = errors.New("ssh: negative mpint")
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(encoded)
+}
+
+// readPublicKey reads the key type, curve identifier and point of an ECDSA key
+func (r *sshReader) readPublicKey() (*CurveInfo, *Point, error) {
+	algorithm := r.readString()
+	identifier := r.readString()
+	encodedPoint := r.readString()
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+	curve := findCurve(func(curve *CurveInfo) bool {
+		return curve.SSH != "" && curve.SSH == string(identifier)
+	})
+	if curve == nil || string(algorithm) != sshKeyAlgorithmPrefix+curve.SSH {
+		return nil, nil, fmt.Errorf("ssh: unsupported key type %q", algorithm)
+	}
+	publicKey, err := curve.Params.unmarshalUncompressed(encodedPoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ssh: %w", err)
+	}
+	return curve, publicKey, nil
+}
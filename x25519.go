@@ -0,0 +1,156 @@
+package ecc
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// montgomeryCurve describes the x-only Diffie-Hellman function of RFC 7748 on
+// a Montgomery curve v^2 = u^3 + A*u^2 + u
+type montgomeryCurve struct {
+	name string
+	p    *big.Int
+	a24  *big.Int // (A - 2) / 4
+	size int      // length of scalars and u-coordinates in bytes
+	bits int      // number of scalar bits processed by the ladder
+	base []byte   // encoded u-coordinate of the base point, not shared with callers
+
+	// clamp sets and clears the fixed bits of a decoded scalar
+	clamp func(k []byte)
+}
+
+// curve25519 is the Montgomery curve of X25519, with p = 2^255 - 19 and
+// A = 486662
+var curve25519 = &montgomeryCurve{
+	name: "X25519",
+	p:    new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19)),
+	a24:  big.NewInt(121665),
+	size: 32,
+	bits: 255,
+	base: append([]byte(nil), X25519BasePoint...),
+	clamp: func(k []byte) {
+		k[0] &= 248
+		k[31] &= 127
+		k[31] |= 64
+	},
+}
+
+// X25519BasePoint is the u-coordinate 9 of the base point of Curve25519. Key
+// generation uses its own copy, so modifying it has no effect on the package.
+var X25519BasePoint = []byte{9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+// X25519 computes the X25519 function of RFC 7748 section 5 on a 32-byte
+// scalar and a 32-byte u-coordinate, both little-endian. The scalar is
+// clamped and the most significant bit of u is ignored. An all-zero result,
+// caused by a u-coordinate of small order, is reported as an error.
+//
+// The arithmetic uses math/big and is not constant time.
+func X25519(scalar, u []byte) ([]byte, error) {
+	return curve25519.scalarMult(scalar, u)
+}
+
+// scalarMult computes the Montgomery ladder of RFC 7748 section 5
+func (curve *montgomeryCurve) scalarMult(scalar, u []byte) ([]byte, error) {
+	if len(scalar) != curve.size {
+		return nil, errors.New("ecc: " + curve.name + " scalar has the wrong length")
+	}
+	if len(u) != curve.size {
+		return nil, errors.New("ecc: " + curve.name + " u-coordinate has the wrong length")
+	}
+
+	k := append([]byte(nil), scalar...)
+	curve.clamp(k)
+	x1 := curve.decodeU(u)
+
+	p := curve.p
+	x2, z2 := big.NewInt(1), big.NewInt(0)
+	x3, z3 := new(big.Int).Set(x1), big.NewInt(1)
+	A, AA, B, BB, E, C, D, DA, CB := new(big.Int), new(big.Int), new(big.Int), new(big.Int), new(big.Int), new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+
+	swap := uint(0)
+	for t := curve.bits - 1; t >= 0; t-- {
+		kt := uint(k[t/8]>>(t%8)) & 1
+		swap ^= kt
+		if swap == 1 {
+			x2, x3 = x3, x2
+			z2, z3 = z3, z2
+		}
+		swap = kt
+
+		A.Add(x2, z2)
+		AA.Mul(A, A).Mod(AA, p)
+		B.Sub(x2, z2)
+		BB.Mul(B, B).Mod(BB, p)
+		E.Sub(AA, BB)
+		C.Add(x3, z3)
+		D.Sub(x3, z3)
+		DA.Mul(D, A).Mod(DA, p)
+		CB.Mul(C, B).Mod(CB, p)
+
+		x3.Add(DA, CB)
+		x3.Mul(x3, x3).Mod(x3, p)
+		z3.Sub(DA, CB)
+		z3.Mul(z3, z3).Mul(z3, x1).Mod(z3, p)
+		x2.Mul(AA, BB).Mod(x2, p)
+		z2.Mul(curve.a24, E).Add(z2, AA).Mul(z2, E).Mod(z2, p)
+	}
+	if swap == 1 {
+		x2, z2 = x3, z3
+	}
+
+	// x2 / z2, where z2^(p-2) = 0 for z2 = 0
+	result := new(big.Int).Exp(z2, new(big.Int).Sub(p, big.NewInt(2)), p)
+	result.Mul(result, x2).Mod(result, p)
+
+	encoded := curve.encodeU(result)
+	if subtle.ConstantTimeCompare(encoded, make([]byte, curve.size)) == 1 {
+		return nil, errors.New("ecc: " + curve.name + " input point has small order")
+	}
+	return encoded, nil
+}
+
+// decodeU decodes a little-endian u-coordinate, ignoring the bits above the
+// size of p, and reduces it modulo p
+func (curve *montgomeryCurve) decodeU(u []byte) *big.Int {
+	b := reverseBytes(u)
+	if excess := curve.size*8 - curve.p.BitLen(); excess > 0 {
+		b[0] &= 0xff >> excess
+	}
+	x := new(big.Int).SetBytes(b)
+	return x.Mod(x, curve.p)
+}
+
+// encodeU encodes a field element as a little-endian u-coordinate
+func (curve *montgomeryCurve) encodeU(x *big.Int) []byte {
+	return reverseBytes(x.FillBytes(make([]byte, curve.size)))
+}
+
+// generateKey generates a random scalar and its public u-coordinate, reading
+// from crypto/rand when rand is nil
+func (curve *montgomeryCurve) generateKey(rand io.Reader) ([]byte, []byte, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	privateKey := make([]byte, curve.size)
+	if _, err := io.ReadFull(rand, privateKey); err != nil {
+		return nil, nil, err
+	}
+	publicKey, err := curve.scalarMult(privateKey, curve.base)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// reverseBytes returns a reversed copy of b, converting between little-endian
+// and big-endian encodings
+func reverseBytes(b []byte) []byte {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return reversed
+}
@@ -0,0 +1,106 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestX25519_RFC7748Vectors(t *testing.T) {
+
+	// RFC 7748 section 5.2
+	vectors := []struct {
+		scalar, u, expected string
+	}{
+		{"a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4",
+			"e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c",
+			"c3da55379de9c6908e94ea4df28d084f32eccf03491c71f754b4075577a28552"},
+		{"4b66e9d4d1b4673c5ad22691957d6af5c11b6421e0ea01d42ca4169e7918ba0d",
+			"e5210f12786811d3f4b7959d0538ae2c31dbe7106fc03c3efc4cd549c715a493",
+			"95cbde9476e8907d7aade45cb4b873f88b595a68799fa152e6f8f7647aac7957"},
+	}
+	for _, vector := range vectors {
+		scalar, _ := hex.DecodeString(vector.scalar)
+		u, _ := hex.DecodeString(vector.u)
+		observed, err := X25519(scalar, u)
+		if err != nil {
+			t.Fatalf("X25519 failed : %v", err)
+		}
+		if hex.EncodeToString(observed) != vector.expected {
+			t.Fatalf("Expected %s, Observed %x", vector.expected, observed)
+		}
+	}
+}
+
+func TestX25519_Iterated(t *testing.T) {
+
+	// RFC 7748 section 5.2, k = X25519(k, u) and u = old k, starting from k = u = 9
+	k := append([]byte(nil), X25519BasePoint...)
+	u := append([]byte(nil), X25519BasePoint...)
+	for i := 1; i <= 1000; i++ {
+		result, err := X25519(k, u)
+		if err != nil {
+			t.Fatalf("X25519 failed in iteration %d : %v", i, err)
+		}
+		u, k = k, result
+
+		if i == 1 && hex.EncodeToString(k) != "422c8e7a6227d7bca1350b3e2bb7279f7897b87bb6854b783c60e80311ae3079" {
+			t.Fatalf("Unexpected result after 1 iteration %x", k)
+		}
+	}
+	if hex.EncodeToString(k) != "684cf59ba83309552800ef566f2f4d3c1c3887c49360e3875f2eb94d99532c51" {
+		t.Fatalf("Unexpected result after 1000 iterations %x", k)
+	}
+}
+
+func TestX25519_DiffieHellman(t *testing.T) {
+
+	// RFC 7748 section 6.1
+	alicePrivate, _ := hex.DecodeString("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	bobPrivate, _ := hex.DecodeString("5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb")
+	expectedAlice, _ := hex.DecodeString("8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a")
+	expectedBob, _ := hex.DecodeString("de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f")
+	expectedShared, _ := hex.DecodeString("4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742")
+
+	alicePublic, _ := X25519(alicePrivate, X25519BasePoint)
+	bobPublic, _ := X25519(bobPrivate, X25519BasePoint)
+	if !bytes.Equal(alicePublic, expectedAlice) || !bytes.Equal(bobPublic, expectedBob) {
+		t.Fatalf("Public key mismatch. Observed %x and %x", alicePublic, bobPublic)
+	}
+	shared1, _ := X25519(alicePrivate, bobPublic)
+	shared2, _ := X25519(bobPrivate, alicePublic)
+	if !bytes.Equal(shared1, expectedShared) || !bytes.Equal(shared2, expectedShared) {
+		t.Fatalf("Shared secret mismatch. Expected %x, Observed %x and %x", expectedShared, shared1, shared2)
+	}
+}
+
+func TestX25519_BasePointIsCopied(t *testing.T) {
+	private := bytes.Repeat([]byte{0x42}, 32)
+	ka, _ := NewKeyAgreement("X25519")
+	expected, _ := ka.PublicKey(private)
+
+	saved := append([]byte(nil), X25519BasePoint...)
+	defer copy(X25519BasePoint, saved)
+	X25519BasePoint[0] = 4
+	observed, err := ka.PublicKey(private)
+	if err != nil || !bytes.Equal(observed, expected) {
+		t.Fatalf("Expected %x, Observed %x (%v)", expected, observed, err)
+	}
+}
+
+func TestX25519_RejectsInvalidInput(t *testing.T) {
+	scalar := bytes.Repeat([]byte{0x42}, 32)
+
+	// u = 0 and u = 1 have small order
+	for _, u := range [][]byte{make([]byte, 32), append([]byte{1}, make([]byte, 31)...)} {
+		if _, err := X25519(scalar, u); err == nil {
+			t.Fatalf("Expected error for small order point %x", u)
+		}
+	}
+	if _, err := X25519(scalar[:31], X25519BasePoint); err == nil {
+		t.Fatalf("Expected error for a short scalar")
+	}
+	if _, err := X25519(scalar, X25519BasePoint[:31]); err == nil {
+		t.Fatalf("Expected error for a short u-coordinate")
+	}
+}
@@ -0,0 +1,337 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// X3DH is the Extended Triple Diffie-Hellman key agreement of the Signal
+// protocol, with ECDH on a Weierstrass curve in place of X25519 and ECDSA
+// signatures of the identity key in place of XEdDSA. The responder B publishes
+// a prekey bundle with its identity key, a signed prekey and optionally a
+// one-time prekey; the initiator A uses it to compute a shared key while B is
+// offline and sends an initial message from which B computes the same key:
+//
+//	DH1 = DH(IKA, SPKB)   DH2 = DH(EKA, IKB)
+//	DH3 = DH(EKA, SPKB)   DH4 = DH(EKA, OPKB)
+//	SK = HKDF(F || DH1 || DH2 || DH3 [|| DH4])
+//
+// with F a string of 0xFF bytes as long as a shared secret, a zero salt of the
+// hash size and the info of X3DHOptions.
+
+// X3DHOptions configures the key derivation of X3DH. A nil *X3DHOptions
+// selects SHA-256, no info and a 32-byte key.
+type X3DHOptions struct {
+	Hash    crypto.Hash // SHA-256 when zero
+	Info    []byte      // application specific info of HKDF
+	KeySize int         // length of the shared key, 32 when zero
+}
+
+// X3DHSignedPrekey is a medium-term prekey together with the signature of its
+// public key by the identity key
+type X3DHSignedPrekey struct {
+	ID        uint32
+	Key       *ECPrivateKey
+	Signature *ECSignature
+}
+
+// X3DHOneTimePrekey is a prekey used for a single X3DH run
+type X3DHOneTimePrekey struct {
+	ID  uint32
+	Key *ECPrivateKey
+}
+
+// X3DHPrekeyBundle is the set of public keys a responder publishes. OneTimePrekey
+// is nil when the server has run out of one-time prekeys.
+type X3DHPrekeyBundle struct {
+	IdentityKey     *Point
+	SignedPrekeyID  uint32
+	SignedPrekey    *Point
+	Signature       *ECSignature
+	OneTimePrekeyID uint32
+	OneTimePrekey   *Point
+}
+
+// X3DHInitialMessage is what the initiator sends to the responder: its identity
+// key, its ephemeral key and the prekeys of the bundle it used
+type X3DHInitialMessage struct {
+	IdentityKey      *Point
+	EphemeralKey     *Point
+	SignedPrekeyID   uint32
+	HasOneTimePrekey bool
+	OneTimePrekeyID  uint32
+}
+
+// X3DHResult is the outcome of X3DH: the shared key and the associated data
+// AD = IKA || IKB, the SEC 1 uncompressed identity keys of the initiator and of
+// the responder, which the first message encrypted with the key should
+// authenticate
+type X3DHResult struct {
+	Key            []byte
+	AssociatedData []byte
+}
+
+// NewX3DHSignedPrekey generates a signed prekey on the curve of the identity
+// key and signs its SEC 1 uncompressed encoding with Sign
+func (identity *ECPrivateKey) NewX3DHSignedPrekey(id uint32, rand io.Reader) (*X3DHSignedPrekey, error) {
+	ec := identity.curve
+	if ec == nil || !ec.IsValidPrivateKey(identity) {
+		return nil, errors.New("ecc: invalid X3DH identity key")
+	}
+	key, err := ec.GeneratePrivateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	return &X3DHSignedPrekey{ID: id, Key: key, Signature: identity.Sign(ec.marshalUncompressed(key.PublicKey))}, nil
+}
+
+// NewX3DHOneTimePrekeys generates count one-time prekeys on the curve with the
+// identifiers firstID, firstID + 1, ...
+func (ec *ECParams) NewX3DHOneTimePrekeys(firstID uint32, count int, rand io.Reader) ([]*X3DHOneTimePrekey, error) {
+	prekeys := make([]*X3DHOneTimePrekey, count)
+	for i := range prekeys {
+		key, err := ec.GeneratePrivateKey(rand)
+		if err != nil {
+			return nil, err
+		}
+		prekeys[i] = &X3DHOneTimePrekey{ID: firstID + uint32(i), Key: key}
+	}
+	return prekeys, nil
+}
+
+// NewX3DHPrekeyBundle returns the bundle of the identity key, the signed prekey
+// and the one-time prekey, which may be nil
+func (identity *ECPrivateKey) NewX3DHPrekeyBundle(signed *X3DHSignedPrekey, oneTime *X3DHOneTimePrekey) *X3DHPrekeyBundle {
+	ec := identity.curve
+	bundle := &X3DHPrekeyBundle{
+		IdentityKey:    identity.publicPoint(ec),
+		SignedPrekeyID: signed.ID,
+		SignedPrekey:   signed.Key.publicPoint(ec),
+		Signature:      signed.Signature,
+	}
+	if oneTime != nil {
+		bundle.OneTimePrekeyID, bundle.OneTimePrekey = oneTime.ID, oneTime.Key.publicPoint(ec)
+	}
+	return bundle
+}
+
+// Verify reports whether the keys of the bundle are valid public keys of the
+// curve and the signed prekey is signed by the identity key
+func (bundle *X3DHPrekeyBundle) Verify(ec *ECParams) bool {
+	if !ec.isValidPublicKey(bundle.IdentityKey) || !ec.isValidPublicKey(bundle.SignedPrekey) {
+		return false
+	}
+	if bundle.OneTimePrekey != nil && !ec.isValidPublicKey(bundle.OneTimePrekey) {
+		return false
+	}
+	return bundle.IdentityKey.Verify(ec.marshalUncompressed(bundle.SignedPrekey), bundle.Signature, ec)
+}
+
+// InitiateX3DH runs X3DH as the initiator with the bundle of the responder. The
+// bundle is verified, an ephemeral key is generated from rand, or crypto/rand
+// when rand is nil, and the initial message for the responder is returned with
+// the result.
+func (identity *ECPrivateKey) InitiateX3DH(bundle *X3DHPrekeyBundle, rand io.Reader, opts *X3DHOptions) (*X3DHInitialMessage, *X3DHResult, error) {
+	ec := identity.curve
+	if ec == nil || !ec.IsValidPrivateKey(identity) {
+		return nil, nil, errors.New("ecc: invalid X3DH identity key")
+	}
+	if bundle == nil || !bundle.Verify(ec) {
+		return nil, nil, errors.New("ecc: invalid X3DH prekey bundle")
+	}
+	ephemeral, err := ec.GeneratePrivateKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := []*ECPrivateKey{identity, ephemeral, ephemeral}
+	peers := []*Point{bundle.SignedPrekey, bundle.IdentityKey, bundle.SignedPrekey}
+	if bundle.OneTimePrekey != nil {
+		keys, peers = append(keys, ephemeral), append(peers, bundle.OneTimePrekey)
+	}
+	result, err := x3dhResult(ec, keys, peers, identity.publicPoint(ec), bundle.IdentityKey, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	message := &X3DHInitialMessage{
+		IdentityKey:      identity.publicPoint(ec),
+		EphemeralKey:     ephemeral.PublicKey,
+		SignedPrekeyID:   bundle.SignedPrekeyID,
+		HasOneTimePrekey: bundle.OneTimePrekey != nil,
+		OneTimePrekeyID:  bundle.OneTimePrekeyID,
+	}
+	return message, result, nil
+}
+
+// RespondX3DH runs X3DH as the responder for the initial message, with the
+// signed prekey and the one-time prekey named by the message. oneTime must be
+// nil when the message uses no one-time prekey; the caller deletes it after a
+// successful run so that it is never used again.
+func (identity *ECPrivateKey) RespondX3DH(message *X3DHInitialMessage, signed *X3DHSignedPrekey, oneTime *X3DHOneTimePrekey, opts *X3DHOptions) (*X3DHResult, error) {
+	ec := identity.curve
+	if ec == nil || !ec.IsValidPrivateKey(identity) {
+		return nil, errors.New("ecc: invalid X3DH identity key")
+	}
+	if message == nil || signed == nil || message.SignedPrekeyID != signed.ID {
+		return nil, errors.New("ecc: X3DH message uses another signed prekey")
+	}
+	if message.HasOneTimePrekey != (oneTime != nil) || (oneTime != nil && message.OneTimePrekeyID != oneTime.ID) {
+		return nil, errors.New("ecc: X3DH message uses another one-time prekey")
+	}
+	if !ec.isValidPublicKey(message.IdentityKey) || !ec.isValidPublicKey(message.EphemeralKey) {
+		return nil, errors.New("ecc: invalid X3DH public key")
+	}
+
+	keys := []*ECPrivateKey{signed.Key, identity, signed.Key}
+	peers := []*Point{message.IdentityKey, message.EphemeralKey, message.EphemeralKey}
+	if oneTime != nil {
+		keys, peers = append(keys, oneTime.Key), append(peers, message.EphemeralKey)
+	}
+	return x3dhResult(ec, keys, peers, message.IdentityKey, identity.publicPoint(ec), opts)
+}
+
+// x3dhResult computes the DH outputs of the keys with the peer keys, derives
+// the shared key from them and computes the associated data of the identity
+// keys
+func x3dhResult(ec *ECParams, keys []*ECPrivateKey, peers []*Point, initiator, responder *Point, opts *X3DHOptions) (*X3DHResult, error) {
+	if opts == nil {
+		opts = &X3DHOptions{}
+	}
+	h := opts.Hash
+	if h == 0 {
+		h = crypto.SHA256
+	}
+	keySize := opts.KeySize
+	if keySize == 0 {
+		keySize = 32
+	}
+	ikm := bytes.Repeat([]byte{0xff}, ec.coordinateSize())
+	for i, key := range keys {
+		secret, err := key.SharedSecret(peers[i])
+		if err != nil {
+			return nil, err
+		}
+		ikm = append(ikm, secret...)
+	}
+	key, err := HKDF(h, ikm, nil, opts.Info, keySize)
+	if err != nil {
+		return nil, err
+	}
+	ad := append(ec.marshalUncompressed(initiator), ec.marshalUncompressed(responder)...)
+	return &X3DHResult{Key: key, AssociatedData: ad}, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is
+//
+//	curve ID || IK || SPK ID || SPK || signature [|| OPK ID || OPK]
+//
+// with points in the SEC 1 uncompressed form, 32-bit big-endian identifiers
+// and the signature as r || s.
+func (bundle *X3DHPrekeyBundle) MarshalBinary() ([]byte, error) {
+	curve := lookupCurveOfPoint(bundle.IdentityKey)
+	if curve == nil || curve.ID == 0 || bundle.SignedPrekey == nil || bundle.Signature == nil || bundle.Signature.r == nil {
+		return nil, errors.New("ecc: X3DH prekey bundle is not on a supported curve")
+	}
+	ec := curve.Params
+	data := append([]byte{curve.ID}, ec.marshalUncompressed(bundle.IdentityKey)...)
+	data = binary.BigEndian.AppendUint32(data, bundle.SignedPrekeyID)
+	data = append(data, ec.marshalUncompressed(bundle.SignedPrekey)...)
+	data = append(data, bundle.Signature.rawBytes(ec.scalarSize())...)
+	if bundle.OneTimePrekey != nil {
+		data = binary.BigEndian.AppendUint32(data, bundle.OneTimePrekeyID)
+		data = append(data, ec.marshalUncompressed(bundle.OneTimePrekey)...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The bundle is decoded
+// but not verified.
+func (bundle *X3DHPrekeyBundle) UnmarshalBinary(data []byte) error {
+	curve, data, err := splitCurveID(data)
+	if err != nil {
+		return err
+	}
+	ec := curve.Params
+	pointSize := 1 + 2*ec.coordinateSize()
+	size := 2*pointSize + 4 + 2*ec.scalarSize()
+	if len(data) != size && len(data) != size+4+pointSize {
+		return fmt.Errorf("ecc: X3DH prekey bundle must be %d or %d bytes, got %d", size, size+4+pointSize, len(data))
+	}
+
+	var decoded X3DHPrekeyBundle
+	if decoded.IdentityKey, err = ec.NewPublicKey(data[:pointSize]); err != nil {
+		return err
+	}
+	data = data[pointSize:]
+	decoded.SignedPrekeyID = binary.BigEndian.Uint32(data)
+	if decoded.SignedPrekey, err = ec.NewPublicKey(data[4 : 4+pointSize]); err != nil {
+		return err
+	}
+	data = data[4+pointSize:]
+	if decoded.Signature, err = parseRawSignature(data[:2*ec.scalarSize()], ec); err != nil {
+		return fmt.Errorf("ecc: %w", err)
+	}
+	data = data[2*ec.scalarSize():]
+	if len(data) > 0 {
+		decoded.OneTimePrekeyID = binary.BigEndian.Uint32(data)
+		if decoded.OneTimePrekey, err = ec.NewPublicKey(data[4:]); err != nil {
+			return err
+		}
+	}
+	*bundle = decoded
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is
+//
+//	curve ID || IKA || EKA || SPK ID [|| OPK ID]
+//
+// with points in the SEC 1 uncompressed form and 32-bit big-endian
+// identifiers.
+func (message *X3DHInitialMessage) MarshalBinary() ([]byte, error) {
+	curve := lookupCurveOfPoint(message.IdentityKey)
+	if curve == nil || curve.ID == 0 || message.EphemeralKey == nil {
+		return nil, errors.New("ecc: X3DH initial message is not on a supported curve")
+	}
+	ec := curve.Params
+	data := append([]byte{curve.ID}, ec.marshalUncompressed(message.IdentityKey)...)
+	data = append(data, ec.marshalUncompressed(message.EphemeralKey)...)
+	data = binary.BigEndian.AppendUint32(data, message.SignedPrekeyID)
+	if message.HasOneTimePrekey {
+		data = binary.BigEndian.AppendUint32(data, message.OneTimePrekeyID)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (message *X3DHInitialMessage) UnmarshalBinary(data []byte) error {
+	curve, data, err := splitCurveID(data)
+	if err != nil {
+		return err
+	}
+	ec := curve.Params
+	pointSize := 1 + 2*ec.coordinateSize()
+	size := 2*pointSize + 4
+	if len(data) != size && len(data) != size+4 {
+		return fmt.Errorf("ecc: X3DH initial message must be %d or %d bytes, got %d", size, size+4, len(data))
+	}
+
+	var decoded X3DHInitialMessage
+	if decoded.IdentityKey, err = ec.NewPublicKey(data[:pointSize]); err != nil {
+		return err
+	}
+	if decoded.EphemeralKey, err = ec.NewPublicKey(data[pointSize : 2*pointSize]); err != nil {
+		return err
+	}
+	decoded.SignedPrekeyID = binary.BigEndian.Uint32(data[2*pointSize:])
+	if len(data) == size+4 {
+		decoded.HasOneTimePrekey = true
+		decoded.OneTimePrekeyID = binary.BigEndian.Uint32(data[size:])
+	}
+	*message = decoded
+	return nil
+}
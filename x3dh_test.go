@@ -0,0 +1,154 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// x3dhTestKeys returns the P-256 identity and ephemeral keys of Alice and the
+// identity, signed prekey and one-time prekey of Bob
+func x3dhTestKeys(t *testing.T) (identityA, ephemeralA, identityB, signedKeyB, oneTimeKeyB *ECPrivateKey) {
+	keys := p256TestKeys(t,
+		"136410d54556f2b0847ec836227016c71bbd5291d0e4726a23213455ed16a7ae",
+		"c228f478bb97c0543fc58f038657fa8e2088e770cf96c83727950df8f5c84a41",
+		"dc3e561343b89f8f7025a1bfd3c8cf561acbceded32b1f0a04a39829591faf78",
+		"fc7bf3bb8eb9b18ad0e2243aead16922c97349c7b072d8c7b2516fae04ff2d74",
+		"600af6fbf479b2192a3b4088b42803a9bdc61d978728da811ce80e455fd0ac02",
+	)
+	return keys[0], keys[1], keys[2], keys[3], keys[4]
+}
+
+// Regression values for the keys of x3dhTestKeys, not published vectors. The
+// keys are HKDF-SHA-256 over 0xff...ff || DH1 || DH2 || DH3 [|| DH4] with a
+// zero salt and the info of opts.
+func TestX3DH_Regression(t *testing.T) {
+	identityA, ephemeralA, identityB, signedKeyB, oneTimeKeyB := x3dhTestKeys(t)
+	params := identityA.curve
+	signed := &X3DHSignedPrekey{ID: 7, Key: signedKeyB, Signature: identityB.Sign(params.marshalUncompressed(signedKeyB.PublicKey))}
+	opts := &X3DHOptions{Info: []byte("x3dh test")}
+
+	for _, v := range []struct {
+		oneTime *X3DHOneTimePrekey
+		key     string
+	}{
+		{nil, "02e0a8a8637a57b3bd3a4b72dc96c31303aa8e0e6a5811e24f1cbcea8cd82b78"},
+		{&X3DHOneTimePrekey{ID: 42, Key: oneTimeKeyB}, "35ba950d071be6a9d75ccdfd3fe84ed57fc4ac5bb3130069a7d9cc6b3b50f696"},
+	} {
+		bundle := identityB.NewX3DHPrekeyBundle(signed, v.oneTime)
+		message, resultA, err := identityA.InitiateX3DH(bundle, bytes.NewReader(ephemeralA.D.FillBytes(make([]byte, 32))), opts)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if hex.EncodeToString(resultA.Key) != v.key {
+			t.Fatalf("Expected %s, Observed %x", v.key, resultA.Key)
+		}
+		resultB, err := identityB.RespondX3DH(message, signed, v.oneTime, opts)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if !bytes.Equal(resultB.Key, resultA.Key) {
+			t.Fatalf("Expected %x, Observed %x", resultA.Key, resultB.Key)
+		}
+		expectedAD := append(params.marshalUncompressed(identityA.PublicKey), params.marshalUncompressed(identityB.PublicKey)...)
+		if !bytes.Equal(resultA.AssociatedData, expectedAD) || !bytes.Equal(resultB.AssociatedData, expectedAD) {
+			t.Fatalf("Expected associated data %x, Observed %x and %x", expectedAD, resultA.AssociatedData, resultB.AssociatedData)
+		}
+	}
+}
+
+func TestX3DH_RoundTrip(t *testing.T) {
+	for _, curve := range []Curve{GetSecp256k1Parametes(), GetSecp384r1Parameters(), GetBrainpoolP256r1Parameters()} {
+		params := curve.Params()
+		identityA, _ := params.GeneratePrivateKey(nil)
+		identityB, _ := params.GeneratePrivateKey(nil)
+		signed, err := identityB.NewX3DHSignedPrekey(1, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", curve.Name(), err)
+		}
+		oneTime, err := params.NewX3DHOneTimePrekeys(100, 3, nil)
+		if err != nil || len(oneTime) != 3 || oneTime[2].ID != 102 {
+			t.Fatalf("%s: Expected 3 one-time prekeys, Observed %d (%v)", curve.Name(), len(oneTime), err)
+		}
+
+		for _, prekey := range []*X3DHOneTimePrekey{oneTime[1], nil} {
+			// The bundle and the initial message go through their encodings
+			encodedBundle, err := identityB.NewX3DHPrekeyBundle(signed, prekey).MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			var bundle X3DHPrekeyBundle
+			if err := bundle.UnmarshalBinary(encodedBundle); err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			sent, resultA, err := identityA.InitiateX3DH(&bundle, nil, nil)
+			if err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			encodedMessage, err := sent.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			var message X3DHInitialMessage
+			if err := message.UnmarshalBinary(encodedMessage); err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			if message.HasOneTimePrekey != (prekey != nil) || (prekey != nil && message.OneTimePrekeyID != prekey.ID) {
+				t.Fatalf("%s: Expected the one-time prekey %v, Observed %v", curve.Name(), prekey, message)
+			}
+
+			resultB, err := identityB.RespondX3DH(&message, signed, prekey, nil)
+			if err != nil {
+				t.Fatalf("%s: %v", curve.Name(), err)
+			}
+			if len(resultA.Key) != 32 || !bytes.Equal(resultA.Key, resultB.Key) || !bytes.Equal(resultA.AssociatedData, resultB.AssociatedData) {
+				t.Fatalf("%s: Expected %x, Observed %x", curve.Name(), resultA.Key, resultB.Key)
+			}
+		}
+	}
+}
+
+func TestX3DH_Rejections(t *testing.T) {
+	params := GetSecp256r1Parameters().ECParams
+	identityA, _ := params.GeneratePrivateKey(nil)
+	identityB, _ := params.GeneratePrivateKey(nil)
+	signed, _ := identityB.NewX3DHSignedPrekey(1, nil)
+	oneTime, _ := params.NewX3DHOneTimePrekeys(1, 2, nil)
+
+	// A signed prekey that is not signed by the identity key of the bundle
+	forged := identityB.NewX3DHPrekeyBundle(signed, nil)
+	forged.IdentityKey = identityA.PublicKey
+	if _, _, err := identityA.InitiateX3DH(forged, nil, nil); err == nil {
+		t.Fatalf("Expected an error for a bundle with an invalid signature")
+	}
+	other := GetSecp256k1Parametes().ECParams
+	otherIdentity, _ := other.GeneratePrivateKey(nil)
+	if _, _, err := otherIdentity.InitiateX3DH(identityB.NewX3DHPrekeyBundle(signed, nil), nil, nil); err == nil {
+		t.Fatalf("Expected an error for a bundle on another curve")
+	}
+
+	message, _, err := identityA.InitiateX3DH(identityB.NewX3DHPrekeyBundle(signed, oneTime[0]), nil, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, err := identityB.RespondX3DH(message, signed, oneTime[1], nil); err == nil {
+		t.Fatalf("Expected an error for another one-time prekey")
+	}
+	if _, err := identityB.RespondX3DH(message, signed, nil, nil); err == nil {
+		t.Fatalf("Expected an error for a missing one-time prekey")
+	}
+	otherSigned, _ := identityB.NewX3DHSignedPrekey(2, nil)
+	if _, err := identityB.RespondX3DH(message, otherSigned, oneTime[0], nil); err == nil {
+		t.Fatalf("Expected an error for another signed prekey")
+	}
+
+	encoded, _ := message.MarshalBinary()
+	if err := new(X3DHInitialMessage).UnmarshalBinary(encoded[:len(encoded)-1]); err == nil {
+		t.Fatalf("Expected an error for a truncated message")
+	}
+	encoded, _ = identityB.NewX3DHPrekeyBundle(signed, nil).MarshalBinary()
+	encoded[5] ^= 1
+	if err := new(X3DHPrekeyBundle).UnmarshalBinary(encoded); err == nil {
+		t.Fatalf("Expected an error for an identity key that is not on the curve")
+	}
+}
@@ -0,0 +1,36 @@
+package ecc
+
+import (
+	"math/big"
+)
+
+// curve448 is the Montgomery curve of X448, with p = 2^448 - 2^224 - 1 and
+// A = 156326
+var curve448 = &montgomeryCurve{
+	name: "X448",
+	p: new(big.Int).Sub(
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 448), new(big.Int).Lsh(big.NewInt(1), 224)),
+		big.NewInt(1)),
+	a24:  big.NewInt(39081),
+	size: 56,
+	bits: 448,
+	base: append([]byte(nil), X448BasePoint...),
+	clamp: func(k []byte) {
+		k[0] &= 252
+		k[55] |= 128
+	},
+}
+
+// X448BasePoint is the u-coordinate 5 of the base point of Curve448. Key
+// generation uses its own copy, so modifying it has no effect on the package.
+var X448BasePoint = append([]byte{5}, make([]byte, 55)...)
+
+// X448 computes the X448 function of RFC 7748 section 5 on a 56-byte scalar
+// and a 56-byte u-coordinate, both little-endian. The scalar is clamped. An
+// all-zero result, caused by a u-coordinate of small order, is reported as an
+// error.
+//
+// The arithmetic uses math/big and is not constant time.
+func X448(scalar, u []byte) ([]byte, error) {
+	return curve448.scalarMult(scalar, u)
+}
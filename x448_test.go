@@ -0,0 +1,103 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestX448_RFC7748Vectors(t *testing.T) {
+
+	// RFC 7748 section 5.2
+	vectors := []struct {
+		scalar, u, expected string
+	}{
+		{"3d262fddf9ec8e88495266fea19a34d28882acef045104d0d1aae121700a779c984c24f8cdd78fbff44943eba368f54b29259a4f1c600ad3",
+			"06fce640fa3487bfda5f6cf2d5263f8aad88334cbd07437f020f08f9814dc031ddbdc38c19c6da2583fa5429db94ada18aa7a7fb4ef8a086",
+			"ce3e4ff95a60dc6697da1db1d85e6afbdf79b50a2412d7546d5f239fe14fbaadeb445fc66a01b0779d98223961111e21766282f73dd96b6f"},
+		{"203d494428b8399352665ddca42f9de8fef600908e0d461cb021f8c538345dd77c3e4806e25f46d3315c44e0a5b4371282dd2c8d5be3095f",
+			"0fbcc2f993cd56d3305b0b7d9e55d4c1a8fb5dbb52f8e9a1e9b6201b165d015894e56c4d3570bee52fe205e28a78b91cdfbde71ce8d157db",
+			"884a02576239ff7a2f2f63b2db6a9ff37047ac13568e1e30fe63c4a7ad1b3ee3a5700df34321d62077e63633c575c1c954514e99da7c179d"},
+	}
+	for _, vector := range vectors {
+		scalar, _ := hex.DecodeString(vector.scalar)
+		u, _ := hex.DecodeString(vector.u)
+		observed, err := X448(scalar, u)
+		if err != nil {
+			t.Fatalf("X448 failed : %v", err)
+		}
+		if hex.EncodeToString(observed) != vector.expected {
+			t.Fatalf("Expected %s, Observed %x", vector.expected, observed)
+		}
+	}
+}
+
+func TestX448_Iterated(t *testing.T) {
+
+	// RFC 7748 section 5.2, k = X448(k, u) and u = old k, starting from k = u = 5
+	k := append([]byte(nil), X448BasePoint...)
+	u := append([]byte(nil), X448BasePoint...)
+	for i := 1; i <= 1000; i++ {
+		result, err := X448(k, u)
+		if err != nil {
+			t.Fatalf("X448 failed in iteration %d : %v", i, err)
+		}
+		u, k = k, result
+
+		if i == 1 && hex.EncodeToString(k) != "3f482c8a9f19b01e6c46ee9711d9dc14fd4bf67af30765c2ae2b846a4d23a8cd0db897086239492caf350b51f833868b9bc2b3bca9cf4113" {
+			t.Fatalf("Unexpected result after 1 iteration %x", k)
+		}
+	}
+	if hex.EncodeToString(k) != "aa3b4749d55b9daf1e5b00288826c467274ce3ebbdd5c17b975e09d4af6c67cf10d087202db88286e2b79fceea3ec353ef54faa26e219f38" {
+		t.Fatalf("Unexpected result after 1000 iterations %x", k)
+	}
+}
+
+func TestX448_DiffieHellman(t *testing.T) {
+
+	// RFC 7748 section 6.2
+	alicePrivate, _ := hex.DecodeString("9a8f4925d1519f5775cf46b04b5800d4ee9ee8bae8bc5565d498c28dd9c9baf574a9419744897391006382a6f127ab1d9ac2d8c0a598726b")
+	bobPrivate, _ := hex.DecodeString("1c306a7ac2a0e2e0990b294470cba339e6453772b075811d8fad0d1d6927c120bb5ee8972b0d3e21374c9c921b09d1b0366f10b65173992d")
+	expectedAlice, _ := hex.DecodeString("9b08f7cc31b7e3e67d22d5aea121074a273bd2b83de09c63faa73d2c22c5d9bbc836647241d953d40c5b12da88120d53177f80e532c41fa0")
+	expectedBob, _ := hex.DecodeString("3eb7a829b0cd20f5bcfc0b599b6feccf6da4627107bdb0d4f345b43027d8b972fc3e34fb4232a13ca706dcb57aec3dae07bdc1c67bf33609")
+	expectedShared, _ := hex.DecodeString("07fff4181ac6cc95ec1c16a94a0f74d12da232ce40a77552281d282bb60c0b56fd2464c335543936521c24403085d59a449a5037514a879d")
+
+	alicePublic, _ := X448(alicePrivate, X448BasePoint)
+	bobPublic, _ := X448(bobPrivate, X448BasePoint)
+	if !bytes.Equal(alicePublic, expectedAlice) || !bytes.Equal(bobPublic, expectedBob) {
+		t.Fatalf("Public key mismatch. Observed %x and %x", alicePublic, bobPublic)
+	}
+	shared1, _ := X448(alicePrivate, bobPublic)
+	shared2, _ := X448(bobPrivate, alicePublic)
+	if !bytes.Equal(shared1, expectedShared) || !bytes.Equal(shared2, expectedShared) {
+		t.Fatalf("Shared secret mismatch. Expected %x, Observed %x and %x", expectedShared, shared1, shared2)
+	}
+}
+
+func TestX448_BasePointIsCopied(t *testing.T) {
+	private := bytes.Repeat([]byte{0x42}, 56)
+	ka, _ := NewKeyAgreement("X448")
+	expected, _ := ka.PublicKey(private)
+
+	saved := append([]byte(nil), X448BasePoint...)
+	defer copy(X448BasePoint, saved)
+	X448BasePoint[0] = 4
+	observed, err := ka.PublicKey(private)
+	if err != nil || !bytes.Equal(observed, expected) {
+		t.Fatalf("Expected %x, Observed %x (%v)", expected, observed, err)
+	}
+}
+
+func TestX448_RejectsInvalidInput(t *testing.T) {
+	scalar := bytes.Repeat([]byte{0x42}, 56)
+
+	// u = 0 and u = 1 have small order
+	for _, u := range [][]byte{make([]byte, 56), append([]byte{1}, make([]byte, 55)...)} {
+		if _, err := X448(scalar, u); err == nil {
+			t.Fatalf("Expected error for small order point %x", u)
+		}
+	}
+	if _, err := X448(scalar[:55], X448BasePoint); err == nil {
+		t.Fatalf("Expected error for a short scalar")
+	}
+}